@@ -18,10 +18,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/gorilla/websocket"
-	"net/url"
 	gpchannel "perun.network/go-perun/channel"
 	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/transport"
 	"perun.network/perun-cardano-backend/wire"
 )
 
@@ -29,7 +28,7 @@ import (
 // Instances should only be created using PAB.NewSubscription.
 type AdjudicatorSub struct {
 	eventQueue chan gpchannel.AdjudicatorEvent
-	connection *websocket.Conn
+	connection transport.Conn
 	lastError  chan error
 	ChannelID  types.ID
 	close      chan struct{}
@@ -39,11 +38,7 @@ type AdjudicatorSub struct {
 	receivedError     error
 }
 
-func newAdjudicatorSub(contractUrl *url.URL, id types.ID, isPerunSub bool) (*AdjudicatorSub, error) {
-	conn, _, err := websocket.DefaultDialer.Dial(contractUrl.String(), nil)
-	if err != nil {
-		return nil, errors.New("unable to establish connection to PAB")
-	}
+func newAdjudicatorSub(conn transport.Conn, id types.ID, isPerunSub bool) *AdjudicatorSub {
 	a := &AdjudicatorSub{
 		eventQueue: make(chan gpchannel.AdjudicatorEvent),
 		connection: conn,
@@ -53,7 +48,7 @@ func newAdjudicatorSub(contractUrl *url.URL, id types.ID, isPerunSub bool) (*Adj
 		IsPerunSub: isPerunSub,
 	}
 	go receiveEvents(a)
-	return a, nil
+	return a
 }
 
 func receiveEvents(a *AdjudicatorSub) {
@@ -64,12 +59,13 @@ func receiveEvents(a *AdjudicatorSub) {
 		_ = a.connection.Close()
 	}
 
-	pushEvent := func(e gpchannel.AdjudicatorEvent) {
+	pushEvent := func(e gpchannel.AdjudicatorEvent) bool {
 		select {
 		case a.eventQueue <- e:
+			return true
 		case <-a.close:
 			closeGracefully(errors.New("subscription closed by user"))
-			return
+			return false
 		}
 	}
 
@@ -90,19 +86,23 @@ func receiveEvents(a *AdjudicatorSub) {
 			return
 		}
 		for _, e := range events {
-			adjEvent, err := decodeEvent(e, a.ChannelID)
+			adjEvent, err := DecodeEvent(e, a.ChannelID)
 			if err != nil {
 				closeGracefully(err)
 				return
 			}
 			if !a.IsPerunSub {
-				pushEvent(adjEvent)
+				if !pushEvent(adjEvent) {
+					return
+				}
 			} else {
 				perunEvent := adjEvent.ToPerunEvent()
 				if perunEvent == nil {
 					continue
 				}
-				pushEvent(perunEvent)
+				if !pushEvent(perunEvent) {
+					return
+				}
 			}
 		}
 	}
@@ -146,18 +146,3 @@ func (a AdjudicatorSub) Close() error {
 	close(a.close)
 	return a.connection.Close()
 }
-
-func decodeEvent(event wire.Event, id types.ID) (InternalEvent, error) {
-	switch event.Tag {
-	case CreatedTag:
-		return Created{}.FromEvent(id, event)
-	case DepositedTag:
-		return Deposited{}.FromEvent(id, event)
-	case DisputedTag:
-		return Disputed{}.FromEvent(id, event)
-	case ConcludedTag:
-		return Concluded{}.FromEvent(id, event)
-	default:
-		return nil, fmt.Errorf("invalid event tag: %s", event.Tag)
-	}
-}
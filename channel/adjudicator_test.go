@@ -0,0 +1,110 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	gpchannel "perun.network/go-perun/channel"
+	gpwallet "perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel"
+	chtest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+// setupFundedChannel starts and funds a random channel on the ledger of the given MockPAB and returns an adjudicator
+// request to withdraw from it.
+func setupFundedChannel(t *testing.T, rng *rand.Rand, mock *chtest.MockPAB, pab *channel.PAB) gpchannel.AdjudicatorReq {
+	params, state := makeParamsAndState(rng)
+	p, s := convertParamsAndState(t, params, state)
+	token, err := mock.Start(p, s)
+	require.NoError(t, err)
+	require.NoError(t, mock.Deposit(params.ID(), 1, s.Balances[1]))
+	require.NoError(t, pab.SetChannelToken(params.ID(), token))
+
+	final := state.Clone()
+	final.Version = 1
+	final.IsFinal = true
+	sigs := make([]gpwallet.Sig, len(params.Parts))
+	for i := range sigs {
+		sigs[i] = make([]byte, wire.SignatureLength)
+		rng.Read(sigs[i])
+	}
+	return gpchannel.AdjudicatorReq{
+		Params: params,
+		Tx:     gpchannel.Transaction{State: final, Sigs: sigs},
+	}
+}
+
+func TestAdjudicator_Withdraw(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	req := setupFundedChannel(t, rng, mock, pab)
+	adj := channel.NewAdjudicator(pab)
+
+	sub, err := adj.Subscribe(context.Background(), req.Params.ID())
+	require.NoError(t, err)
+	defer sub.Close()
+	require.NoError(t, adj.Withdraw(context.Background(), req, nil))
+
+	// Created and Deposited events are internal events and not passed on to perun subscriptions.
+	event := sub.Next()
+	concluded, ok := event.(*gpchannel.ConcludedEvent)
+	require.True(t, ok, "unexpected event: %v", event)
+	require.Equal(t, req.Params.ID(), concluded.ID())
+	_, ok = mock.Datum(req.Params.ID())
+	require.False(t, ok)
+}
+
+func TestAdjudicator_WithdrawNonFinal(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	req := setupFundedChannel(t, rng, mock, pab)
+	req.Tx.State.IsFinal = false
+
+	err := channel.NewAdjudicator(pab).Withdraw(context.Background(), req, nil)
+	var contractErr *channel.ContractError
+	require.True(t, errors.As(err, &contractErr), "unexpected error: %v", err)
+	require.Equal(t, "close", contractErr.Endpoint)
+	_, ok := mock.Datum(req.Params.ID())
+	require.True(t, ok)
+}
+
+func TestAdjudicator_SubscribeDispute(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	req := setupFundedChannel(t, rng, mock, pab)
+	_, s := convertParamsAndState(t, req.Params, req.Tx.State)
+	s.Final = false
+
+	sub, err := channel.NewAdjudicator(pab).Subscribe(context.Background(), req.Params.ID())
+	require.NoError(t, err)
+	defer sub.Close()
+	// A peer disputes the channel.
+	require.NoError(t, mock.Dispute(req.Params.ID(), s, req.Tx.Sigs))
+
+	event := sub.Next()
+	registered, ok := event.(*gpchannel.RegisteredEvent)
+	require.True(t, ok, "unexpected event: %v", event)
+	require.Equal(t, req.Params.ID(), registered.ID())
+	require.Equal(t, s.Version, registered.Version())
+	datum, ok := mock.Datum(req.Params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Disputed)
+}
@@ -17,43 +17,151 @@ package channel
 import (
 	"fmt"
 	pchannel "perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
 	"perun.network/go-perun/wallet"
 	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wire"
 )
 
-// backend implements the wallet.Backend interface
-// The type is private since it only needs to be exposed as a singleton by the
-// `Backend` variable.
+// DefaultChannelIDCacheSize is the number of channel ids of the wallet server a backend created by NewBackend caches.
+const DefaultChannelIDCacheSize = 1024
+
+// backend implements the channel.Backend interface.
 // The current version of backend needs to use our wallet.RemoteBackend implementation.
 // This is a workaround that makes encoding state for signing and verifying possible.
 type backend struct {
 	walletBackend types.ExtendedWalletBackend
+	ids           *channelIDCache
+}
+
+// BatchVerifier is implemented by the channel backends of this package. It verifies the signatures of all
+// participants on a state in a single batch.
+type BatchVerifier interface {
+	VerifyAll(state *pchannel.State, addrs []wallet.Address, sigs []wallet.Sig) ([]bool, error)
 }
 
-// SetWalletBackend needs to be called initially.
+// ChannelIDVerifier is implemented by the channel backends of this package. It checks the channel id of the given
+// parameters against the channel id the wallet server calculates for them.
+type ChannelIDVerifier interface {
+	VerifyChannelID(params *pchannel.Params) error
+}
+
+// Backend is the channel backend set by SetWalletBackend.
+//
+// Deprecated: Use NewBackend, which returns independent backends.
+var Backend pchannel.Backend
+
+// SetWalletBackend sets Backend to a new channel backend using the given wallet backend.
+//
+// Deprecated: Use NewBackend, which returns independent backends.
 func SetWalletBackend(remoteBackend types.ExtendedWalletBackend) {
-	Backend = backend{walletBackend: remoteBackend}
+	Backend = NewBackend(remoteBackend)
+}
+
+// NewBackend returns a new channel backend using the given wallet backend. Every backend is independent of all other
+// backends, so backends of different configurations (e.g. for mainnet and testnet) can be used in the same process.
+// The returned backend implements BatchVerifier and ChannelIDVerifier.
+func NewBackend(walletBackend types.ExtendedWalletBackend) pchannel.Backend {
+	return newBackend(walletBackend)
+}
+
+func newBackend(walletBackend types.ExtendedWalletBackend) *backend {
+	return &backend{
+		walletBackend: walletBackend,
+		ids:           newChannelIDCache(DefaultChannelIDCacheSize),
+	}
 }
 
-// CalcID calculates the channel-id from the parameters.
-// Note that the remote wallet is used for this.
-func (b backend) CalcID(params *pchannel.Params) pchannel.ID {
+// ValidateParams returns an error, iff the backend does not support the given channel parameters. The backend supports
+// ledger channels without an app whose parties are all *address.Address of the signature scheme address.Ed25519 (see
+// types.MakeChannelParameters).
+func ValidateParams(params *pchannel.Params) error {
+	if params == nil {
+		return fmt.Errorf("params must not be nil")
+	}
+	_, err := types.MakeChannelParameters(*params)
+	return err
+}
+
+// ValidateProposal returns an error, iff the backend does not support channels of the given proposal (see
+// ValidateParams). go-perun calculates the channel id of a proposal right after it was accepted, so proposals must be
+// checked with ValidateProposal before they are made or accepted. The participant of the accepting party is not part
+// of the proposal, so it must be an Ed25519 address as well.
+func ValidateProposal(proposal client.ChannelProposal) error {
+	if proposal == nil {
+		return fmt.Errorf("proposal must not be nil")
+	}
+	ledgerProposal, ok := proposal.(*client.LedgerChannelProposalMsg)
+	if !ok {
+		return fmt.Errorf("the backend only supports Ledger Channels")
+	}
+	if !pchannel.IsNoApp(ledgerProposal.App) {
+		return fmt.Errorf("the backend does not support an app in parameters")
+	}
+	return types.ValidateParty(ledgerProposal.Participant)
+}
+
+// CalcID calculates the channel-id from the parameters. The wallet server is the authority for channel ids, so CalcID
+// returns the channel id the wallet server calculates and caches it. Only if the wallet server is unavailable, the
+// channel id is derived locally (see wire.CalculateChannelID). Channels with such an id are checked against the wallet
+// server before they are funded (see VerifyChannelID and Funder.Fund).
+//
+// CalcID supports the parameters accepted by ValidateParams. It panics for any other parameters, because
+// channel.Backend does not allow returning an error, so parameters must be checked with ValidateParams or
+// ValidateProposal first.
+func (b *backend) CalcID(params *pchannel.Params) pchannel.ID {
 	if params == nil {
 		panic("params must not be nil for channel id calculation")
 	}
 	p, err := types.MakeChannelParameters(*params)
 	if err != nil {
-		panic(err)
+		panic(fmt.Sprintf("unsupported channel parameters for channel id calculation: %v", err))
 	}
-	id, err := b.walletBackend.CalculateChannelID(p)
+	local := wire.CalculateChannelID(p)
+	id, err := b.serverChannelID(local, p)
 	if err != nil {
-		panic(err)
+		return local
 	}
 	return id
 }
 
+// VerifyChannelID returns nil, iff the channel id of the given parameters equals the channel id the wallet server
+// calculates for them. Otherwise, it returns a ChannelIDMismatchError or the error of the wallet server. The channel
+// id of the wallet server is requested at most once per channel.
+func (b *backend) VerifyChannelID(params *pchannel.Params) error {
+	if params == nil {
+		return fmt.Errorf("params must not be nil for channel id verification")
+	}
+	p, err := types.MakeChannelParameters(*params)
+	if err != nil {
+		return fmt.Errorf("unsupported channel parameters: %w", err)
+	}
+	serverID, err := b.serverChannelID(wire.CalculateChannelID(p), p)
+	if err != nil {
+		return fmt.Errorf("unable to calculate channel id on wallet server: %w", err)
+	}
+	if serverID != params.ID() {
+		return &ChannelIDMismatchError{ChannelID: params.ID(), ServerID: serverID}
+	}
+	return nil
+}
+
+// serverChannelID returns the channel id the wallet server calculates for the given parameters with the given local
+// channel id.
+func (b *backend) serverChannelID(local pchannel.ID, params types.ChannelParameters) (pchannel.ID, error) {
+	if id, ok := b.ids.get(local); ok {
+		return id, nil
+	}
+	id, err := b.walletBackend.CalculateChannelID(params)
+	if err != nil {
+		return pchannel.ID{}, err
+	}
+	b.ids.add(local, id)
+	return id, nil
+}
+
 // Sign signs the given state with the given account.
-func (b backend) Sign(account wallet.Account, state *pchannel.State) (wallet.Sig, error) {
+func (b *backend) Sign(account wallet.Account, state *pchannel.State) (wallet.Sig, error) {
 	if account == nil {
 		return nil, fmt.Errorf("account must not be nil for signing")
 	}
@@ -74,7 +182,7 @@ func (b backend) Sign(account wallet.Account, state *pchannel.State) (wallet.Sig
 }
 
 // Verify returns true, iff the signature is correct for the given state and address.
-func (b backend) Verify(addr wallet.Address, state *pchannel.State, sig wallet.Sig) (bool, error) {
+func (b *backend) Verify(addr wallet.Address, state *pchannel.State, sig wallet.Sig) (bool, error) {
 	if addr == nil {
 		return false, fmt.Errorf("address must not be nil for verification")
 	}
@@ -88,15 +196,40 @@ func (b backend) Verify(addr wallet.Address, state *pchannel.State, sig wallet.S
 	if err != nil {
 		return false, fmt.Errorf("unable to encode state for verifying: %w", err)
 	}
-	return Backend.walletBackend.VerifyChannelStateSignature(channelState, sig, addr)
+	return b.walletBackend.VerifyChannelStateSignature(channelState, sig, addr)
+}
+
+// VerifyAll returns for every given address, whether the signature at the same index is correct for the given state
+// and address. All signatures are verified in a single batch (see types.ExtendedWalletBackend).
+func (b *backend) VerifyAll(state *pchannel.State, addrs []wallet.Address, sigs []wallet.Sig) ([]bool, error) {
+	if state == nil {
+		return nil, fmt.Errorf("state must not be nil for verification")
+	}
+	if len(addrs) != len(sigs) {
+		return nil, fmt.Errorf("expected %d signatures for verification, got %d", len(addrs), len(sigs))
+	}
+	channelState, err := types.ConvertChannelState(*state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode state for verifying: %w", err)
+	}
+	batch := make([]types.ChannelStateSignature, len(addrs))
+	for i := range addrs {
+		if addrs[i] == nil || sigs[i] == nil {
+			return nil, fmt.Errorf("address and signature %d must not be nil for verification", i)
+		}
+		batch[i] = types.ChannelStateSignature{State: channelState, Sig: sigs[i], Address: addrs[i]}
+	}
+	return b.walletBackend.VerifyChannelStateSignatures(batch)
 }
 
 // NewAsset returns a variable of type Asset, which can be used for unmarshalling an asset from its binary
 // representation.
-func (b backend) NewAsset() pchannel.Asset {
+func (b *backend) NewAsset() pchannel.Asset {
 	return types.Asset
 }
 
-var Backend backend
-
-var _ pchannel.Backend = Backend
+var (
+	_ pchannel.Backend  = &backend{}
+	_ BatchVerifier     = &backend{}
+	_ ChannelIDVerifier = &backend{}
+)
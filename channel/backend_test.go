@@ -15,56 +15,27 @@
 package channel_test
 
 import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
 	"math/big"
-	"math/rand"
 	gpchannel "perun.network/go-perun/channel"
 	gptest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
 	gpwallet "perun.network/go-perun/wallet"
+	gpwallettest "perun.network/go-perun/wallet/test"
+	gpwire "perun.network/go-perun/wire"
 	"perun.network/perun-cardano-backend/channel"
+	chtest "perun.network/perun-cardano-backend/channel/test"
 	"perun.network/perun-cardano-backend/channel/types"
 	"perun.network/perun-cardano-backend/wallet"
 	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
 	pkgtest "polycry.pt/poly-go/test"
 	"testing"
 )
 
-func setup(rng *rand.Rand) *gptest.Setup {
-
-	w := test.NewRemoteWallet(GenericTestRemote)
-	acc, err := w.Unlock(&GenericTestRemote.AvailableAddresses[0])
-	newRandomAddress := func() gpwallet.Address {
-		rAddr := test.MakeRandomAddress(rng)
-		return &rAddr
-	}
-	newParamsAndState := func(opts ...gptest.RandomOpt) (*gpchannel.Params, *gpchannel.State) {
-		return gptest.NewRandomParamsAndState(
-			rng,
-			gptest.WithoutApp().
-				Append(gptest.WithParts(newRandomAddress(), newRandomAddress())).
-				Append(gptest.WithLedgerChannel(true)).
-				Append(gptest.WithVirtualChannel(false)).
-				Append(gptest.WithAssets(types.Asset)).
-				Append(gptest.WithBalancesInRange(new(big.Int).SetUint64(0), types.MaxBalance)).
-				Append(opts...),
-		)
-	}
-	p1, s1 := newParamsAndState()
-	// We need this because `s1` and `s2` must differ IN EVERY FIELD for the verification tests to work.
-	p2, s2 := newParamsAndState(gptest.WithIsFinal(!s1.IsFinal))
-	if err != nil {
-		panic(err)
-	}
-	return &gptest.Setup{
-		Params:        p1,
-		Params2:       p2,
-		State:         s1,
-		State2:        s2,
-		Account:       acc,
-		RandomAddress: newRandomAddress,
-	}
-}
-
 var GenericTestRemote *test.GenericRemote
 
 type main struct{}
@@ -80,13 +51,165 @@ func TestMain(m *testing.M) {
 	GenericTestRemote = test.NewGenericRemote([]address.Address{addr}, rng)
 	wb := wallet.MakeRemoteBackend(GenericTestRemote)
 	gpwallet.SetBackend(wb)
-	channel.SetWalletBackend(wb)
-	gpchannel.SetBackend(channel.Backend)
+	gpchannel.SetBackend(channel.NewBackend(wb))
+	gpwallettest.SetRandomizer(test.NewRandomizer(GenericTestRemote))
 
 	m.Run()
 }
 
 func TestBackend(t *testing.T) {
 	rng := pkgtest.Prng(t)
-	gptest.GenericBackendTest(t, setup(rng), gptest.IgnoreApp, gptest.IgnoreAssets)
+	for _, numParties := range []int{2, 3, 7} {
+		t.Run(fmt.Sprintf("%d parties", numParties), func(t *testing.T) {
+			gptest.GenericBackendTest(t, chtest.NewSetup(rng, numParties), gptest.IgnoreApp, gptest.IgnoreAssets)
+		})
+	}
+}
+
+// channelIDRemote counts the channel id calculations of the wrapped Remote. It fails them, if failing is set, and
+// alters the calculated channel id, if wrongID is set.
+type channelIDRemote struct {
+	wallet.Remote
+	calls   int
+	failing bool
+	wrongID bool
+}
+
+func (r *channelIDRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	if endpoint != wallet.EndpointCalculateChannelID {
+		return r.Remote.CallEndpoint(endpoint, body, result)
+	}
+	r.calls++
+	if r.failing {
+		return errors.New("wallet server unavailable")
+	}
+	if err := r.Remote.CallEndpoint(endpoint, body, result); err != nil {
+		return err
+	}
+	if r.wrongID {
+		result.(*wire.ChannelID)[0] ^= 0xff
+	}
+	return nil
+}
+
+func makeBackendTestParams(t *testing.T, addr address.Address) (*gpchannel.Params, *gpchannel.State) {
+	return gptest.NewRandomParamsAndState(pkgtest.Prng(t), gptest.WithoutApp().
+		Append(gptest.WithParts(&addr, &addr)).
+		Append(gptest.WithLedgerChannel(true)).
+		Append(gptest.WithVirtualChannel(false)).
+		Append(gptest.WithAssets(types.Asset)).
+		Append(gptest.WithBalancesInRange(new(big.Int).SetUint64(0), types.MaxBalance)))
+}
+
+func TestNewBackend(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	remote := &channelIDRemote{Remote: test.NewGenericRemote([]address.Address{addr}, rng), failing: true}
+	b := channel.NewBackend(wallet.MakeRemoteBackend(remote))
+	other := channel.NewBackend(wallet.MakeRemoteBackend(test.NewGenericRemote(nil, rng)))
+
+	params, state := makeBackendTestParams(t, addr)
+	p, err := types.MakeChannelParameters(*params)
+	require.NoError(t, err)
+	id := b.CalcID(params)
+	require.Equal(t, gpchannel.ID(wire.CalculateChannelID(p)), id, "channel id is not derived locally without wallet server")
+	require.Equal(t, id, b.CalcID(params), "channel id is not deterministic")
+	require.Equal(t, id, other.CalcID(params), "backends disagree on the channel id")
+	channel.SetWalletBackend(wallet.MakeRemoteBackend(remote))
+	require.Equal(t, id, channel.Backend.CalcID(params), "deprecated Backend does not delegate to NewBackend")
+
+	params.App = gpchannel.NewMockApp(&addr)
+	require.Error(t, channel.ValidateParams(params))
+	require.Panics(t, func() { b.CalcID(params) }, "CalcID must not derive a channel id for unsupported parameters")
+	params.App = gpchannel.NoApp()
+	require.NoError(t, channel.ValidateParams(params))
+	schnorrAddr, err := address.MakeAddressFromSchemePubKey(address.SchnorrSecp256k1, addr.GetPubKeySlice())
+	require.NoError(t, err)
+	params.Parts[1] = &schnorrAddr
+	require.Error(t, channel.ValidateParams(params), "failed to reject channel party of off-chain signature scheme")
+	params.Parts[1] = &addr
+
+	acc, err := test.NewRemoteWallet(remote).Unlock(&addr)
+	require.NoError(t, err)
+	state.ID = id
+	sig, err := b.Sign(acc, state)
+	require.NoError(t, err)
+	valid, err := b.Verify(&addr, state, sig)
+	require.NoError(t, err)
+	require.True(t, valid)
+	all, err := b.(channel.BatchVerifier).VerifyAll(state, []gpwallet.Address{&addr}, []gpwallet.Sig{sig})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, all)
+}
+
+func TestBackend_VerifyChannelID(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	remote := &channelIDRemote{Remote: test.NewGenericRemote([]address.Address{addr}, rng)}
+	b := channel.NewBackend(wallet.MakeRemoteBackend(remote))
+	v := b.(channel.ChannelIDVerifier)
+	params, _ := makeBackendTestParams(t, addr)
+	withNonce := func(nonce int64) *gpchannel.Params {
+		return gpchannel.NewParamsUnsafe(
+			params.ChallengeDuration,
+			params.Parts,
+			params.App,
+			new(big.Int).Add(params.Nonce, big.NewInt(nonce)),
+			params.LedgerChannel,
+			params.VirtualChannel,
+		)
+	}
+
+	p, err := types.MakeChannelParameters(*params)
+	require.NoError(t, err)
+	serverID, err := wallet.MakeRemoteBackend(remote).CalculateChannelID(p)
+	require.NoError(t, err)
+	remote.calls = 0
+	require.Equal(t, serverID, b.CalcID(params), "channel id is not the channel id of the wallet server")
+	require.Equal(t, serverID, b.CalcID(params), "channel id is not deterministic")
+	require.NoError(t, v.VerifyChannelID(params))
+	require.Equal(t, 1, remote.calls, "channel id of the wallet server is not cached")
+
+	// The wallet server is the authority for channel ids, so channels with a locally derived channel id that the
+	// wallet server does not confirm are rejected.
+	remote.wrongID = true
+	local := withNonce(1)
+	id := b.CalcID(local)
+	require.NotEqual(t, local.ID(), id, "channel id of the wallet server was not used")
+	err = v.VerifyChannelID(local)
+	var mismatch *channel.ChannelIDMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, local.ID(), mismatch.ChannelID)
+	require.Equal(t, id, mismatch.ServerID)
+
+	remote.wrongID = false
+	remote.failing = true
+	unavailable := withNonce(2)
+	require.Equal(t, unavailable.ID(), b.CalcID(unavailable), "channel id is not derived locally without wallet server")
+	require.Error(t, v.VerifyChannelID(unavailable))
+	require.False(t, errors.As(v.VerifyChannelID(unavailable), &mismatch), "unavailable wallet server caused mismatch")
+	remote.failing = false
+	require.NoError(t, v.VerifyChannelID(unavailable))
+}
+
+// newLedgerChannelProposal returns a two-party ledger channel proposal of the given participant.
+func newLedgerChannelProposal(t *testing.T, participant gpwallet.Address, opts ...client.ProposalOpts) client.ChannelProposal {
+	prop, err := client.NewLedgerChannelProposal(
+		60, participant, gpchannel.NewAllocation(2, types.Asset), make([]gpwire.Address, 2), opts...,
+	)
+	require.NoError(t, err)
+	return prop
+}
+
+func TestValidateProposal(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	require.NoError(t, channel.ValidateProposal(newLedgerChannelProposal(t, &addr)))
+
+	schnorrAddr, err := address.MakeAddressFromSchemePubKey(address.SchnorrSecp256k1, addr.GetPubKeySlice())
+	require.NoError(t, err)
+	require.Error(t, channel.ValidateProposal(newLedgerChannelProposal(t, &schnorrAddr)))
+	app := gpchannel.NewMockApp(&addr)
+	require.Error(t, channel.ValidateProposal(newLedgerChannelProposal(t, &addr, client.WithApp(app, gpchannel.NoData()))))
+	require.Error(t, channel.ValidateProposal(nil))
 }
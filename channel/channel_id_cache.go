@@ -0,0 +1,74 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"container/list"
+	pchannel "perun.network/go-perun/channel"
+	"sync"
+)
+
+// channelIDCache is a bounded map from locally derived channel ids to the channel ids the wallet server calculated for
+// the same parameters. If it is full, the least recently used entry is evicted. It is safe for concurrent use.
+type channelIDCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[pchannel.ID]*list.Element
+}
+
+// channelIDCacheEntry is an entry of a channelIDCache.
+type channelIDCacheEntry struct {
+	local  pchannel.ID
+	server pchannel.ID
+}
+
+func newChannelIDCache(size int) *channelIDCache {
+	return &channelIDCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[pchannel.ID]*list.Element),
+	}
+}
+
+// get returns the channel id the wallet server calculated for the parameters with the given local channel id, if it is
+// cached.
+func (c *channelIDCache) get(local pchannel.ID) (pchannel.ID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[local]
+	if !ok {
+		return pchannel.ID{}, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(channelIDCacheEntry).server, true
+}
+
+// add caches the channel id the wallet server calculated for the parameters with the given local channel id.
+func (c *channelIDCache) add(local pchannel.ID, server pchannel.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[local]; ok {
+		e.Value = channelIDCacheEntry{local: local, server: server}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[local] = c.order.PushFront(channelIDCacheEntry{local: local, server: server})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(channelIDCacheEntry).local)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"fmt"
+	gpchannel "perun.network/go-perun/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/errclass"
+	"time"
+)
+
+// MismatchingChannelTokenError is returned, if a channel was funded with a different channel token than the one it was
+// started with.
+type MismatchingChannelTokenError struct {
+	ChannelID types.ID
+	Expected  types.ChannelToken
+	Actual    types.ChannelToken
+}
+
+func (e *MismatchingChannelTokenError) Error() string {
+	return fmt.Sprintf("mismatching channel tokens for channel %x", e.ChannelID)
+}
+
+// Class returns errclass.Funding.
+func (e *MismatchingChannelTokenError) Class() errclass.Class {
+	return errclass.Funding
+}
+
+// MismatchingChannelIDError is returned, if a subscription yields an event of another channel.
+type MismatchingChannelIDError struct {
+	Expected types.ID
+	Actual   types.ID
+}
+
+func (e *MismatchingChannelIDError) Error() string {
+	return fmt.Sprintf("mismatching channel ids. expected: %x actual: %x", e.Expected, e.Actual)
+}
+
+// Class returns errclass.Protocol.
+func (e *MismatchingChannelIDError) Class() errclass.Class {
+	return errclass.Protocol
+}
+
+// UnexpectedEventError is returned, if a subscription yields an event of another type than expected.
+type UnexpectedEventError struct {
+	Expected string
+	Actual   gpchannel.AdjudicatorEvent
+}
+
+func (e *UnexpectedEventError) Error() string {
+	return fmt.Sprintf("expected %s event, got type %T, value: %v", e.Expected, e.Actual, e.Actual)
+}
+
+// Class returns errclass.Protocol.
+func (e *UnexpectedEventError) Class() errclass.Class {
+	return errclass.Protocol
+}
+
+// ChannelStateMismatchError is returned, if a channel was started on-chain with another state than requested.
+type ChannelStateMismatchError struct {
+	Expected types.ChannelState
+	Actual   types.ChannelState
+}
+
+func (e *ChannelStateMismatchError) Error() string {
+	return "on-chain channel state does not match channel state in funding request"
+}
+
+// Class returns errclass.Funding.
+func (e *ChannelStateMismatchError) Class() errclass.Class {
+	return errclass.Funding
+}
+
+// InsufficientFundingError is returned, if a party funded the channel with another amount than its balance.
+type InsufficientFundingError struct {
+	ChannelID types.ID
+	Index     gpchannel.Index
+	Expected  types.Balance
+	Actual    types.Balance
+}
+
+func (e *InsufficientFundingError) Error() string {
+	return fmt.Sprintf("party %d did not fund the channel correctly", e.Index)
+}
+
+// Class returns errclass.Funding.
+func (e *InsufficientFundingError) Class() errclass.Class {
+	return errclass.Funding
+}
+
+// ChannelParametersMismatchError is returned, if a channel was started on-chain with other parameters (parties, nonce
+// or challenge duration) than requested.
+type ChannelParametersMismatchError struct {
+	ChannelID types.ID
+	Expected  types.ChannelParameters
+	Actual    types.ChannelParameters
+}
+
+func (e *ChannelParametersMismatchError) Error() string {
+	return fmt.Sprintf("on-chain parameters of channel %x do not match channel parameters in funding request", e.ChannelID)
+}
+
+// Class returns errclass.Funding.
+func (e *ChannelParametersMismatchError) Class() errclass.Class {
+	return errclass.Funding
+}
+
+// InvalidDatumError is returned, if the on-chain datum of a channel in funding is inconsistent, e.g. because its
+// funding vector does not hold one entry per party or its Funded or Disputed flag is set wrongly.
+type InvalidDatumError struct {
+	ChannelID types.ID
+	Reason    string
+}
+
+func (e *InvalidDatumError) Error() string {
+	return fmt.Sprintf("invalid on-chain datum of channel %x: %s", e.ChannelID, e.Reason)
+}
+
+// Class returns errclass.Funding.
+func (e *InvalidDatumError) Class() errclass.Class {
+	return errclass.Funding
+}
+
+// ChannelIDMismatchError is returned, if the wallet server calculates another channel id for the channel parameters
+// than the one derived locally.
+type ChannelIDMismatchError struct {
+	ChannelID types.ID
+	ServerID  types.ID
+}
+
+func (e *ChannelIDMismatchError) Error() string {
+	return fmt.Sprintf("wallet server calculated channel id %x for channel %x", e.ServerID, e.ChannelID)
+}
+
+// Class returns errclass.Server.
+func (e *ChannelIDMismatchError) Class() errclass.Class {
+	return errclass.Server
+}
+
+// EndpointTimeoutError is returned, if the PAB contract instance neither handled a call to one of its endpoints nor
+// reported an error for it within the status timeout (see PAB.SetStatusTracking). TxID holds the id of the
+// transaction that was submitted for the call, if one was mentioned in the logs. The transaction may still be
+// confirmed.
+type EndpointTimeoutError struct {
+	InstanceID string
+	Endpoint   string
+	TxID       string
+	Timeout    time.Duration
+}
+
+func (e *EndpointTimeoutError) Error() string {
+	msg := fmt.Sprintf("contract instance %s did not handle endpoint %s within %s", e.InstanceID, e.Endpoint, e.Timeout)
+	if e.TxID != "" {
+		msg += fmt.Sprintf(" (transaction %s)", e.TxID)
+	}
+	return msg
+}
+
+// Class returns errclass.Ambiguous.
+func (e *EndpointTimeoutError) Class() errclass.Class {
+	return errclass.Ambiguous
+}
+
+// ContractError is returned, if the PAB contract instance reports an error for a call to one of its endpoints, e.g.
+// because the transaction does not balance or is rejected by the validator. TxID holds the id of the transaction that
+// was submitted for the call, if it was submitted before the error occurred.
+type ContractError struct {
+	InstanceID string
+	Endpoint   string
+	TxID       string
+	Message    string
+}
+
+func (e *ContractError) Error() string {
+	msg := fmt.Sprintf("contract instance %s failed in endpoint %s", e.InstanceID, e.Endpoint)
+	if e.TxID != "" {
+		msg += fmt.Sprintf(" (transaction %s)", e.TxID)
+	}
+	return msg + ": " + e.Message
+}
+
+// Class returns errclass.Contract.
+func (e *ContractError) Class() errclass.Class {
+	return errclass.Contract
+}
+
+var (
+	_ errclass.Classified = &MismatchingChannelTokenError{}
+	_ errclass.Classified = &MismatchingChannelIDError{}
+	_ errclass.Classified = &UnexpectedEventError{}
+	_ errclass.Classified = &ChannelStateMismatchError{}
+	_ errclass.Classified = &InsufficientFundingError{}
+	_ errclass.Classified = &ChannelParametersMismatchError{}
+	_ errclass.Classified = &InvalidDatumError{}
+	_ errclass.Classified = &ChannelIDMismatchError{}
+	_ errclass.Classified = &EndpointTimeoutError{}
+	_ errclass.Classified = &ContractError{}
+)
@@ -15,6 +15,7 @@
 package channel
 
 import (
+	"fmt"
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/wallet"
 	"perun.network/perun-cardano-backend/channel/types"
@@ -113,7 +114,7 @@ func (d Disputed) ToPerunEvent() channel.AdjudicatorEvent {
 
 func (d Disputed) FromEvent(id types.ID, ev wire.Event) (Disputed, error) {
 	if len(ev.DatumList) != 2 {
-		return d, types.DecodeEventError{DisputedTag, 2, len(ev.DatumList)}
+		return d, types.DecodeEventError{Tag: DisputedTag, ExpectedLen: 2, ActualLen: len(ev.DatumList)}
 	}
 	oldDatum, err := ev.DatumList[0].Decode()
 	if err != nil {
@@ -194,3 +195,19 @@ func (c Created) FromEvent(id types.ID, ev wire.Event) (Created, error) {
 		NewDatum:  datum,
 	}, nil
 }
+
+// DecodeEvent decodes the given event of the channel with the given id into the InternalEvent of its tag.
+func DecodeEvent(event wire.Event, id types.ID) (InternalEvent, error) {
+	switch event.Tag {
+	case CreatedTag:
+		return Created{}.FromEvent(id, event)
+	case DepositedTag:
+		return Deposited{}.FromEvent(id, event)
+	case DisputedTag:
+		return Disputed{}.FromEvent(id, event)
+	case ConcludedTag:
+		return Concluded{}.FromEvent(id, event)
+	default:
+		return nil, fmt.Errorf("invalid event tag: %s", event.Tag)
+	}
+}
@@ -2,31 +2,87 @@ package channel
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"math"
 	"perun.network/go-perun/channel"
 	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wire"
 	"time"
 )
 
-var (
-	MismatchingChannelTokenError = errors.New("mismatching channel tokens")
-	MismatchingChannelIDError    = errors.New("mismatching channel ids")
+// DefaultChainIndexDelay is the default duration for which the Funder waits before starting or funding a channel.
+const DefaultChainIndexDelay = 5 * time.Second
+
+// FundingMode determines the order in which the parties of a channel deposit their funds. All parties of a channel
+// must fund it in the same mode.
+type FundingMode int
+
+const (
+	// SequentialFunding lets party 0 start the channel and every other party deposit after all parties with a lower
+	// index, so opening a channel with n parties takes n sequential confirmations.
+	SequentialFunding FundingMode = iota
+	// ParallelFunding lets party 0 start the channel and every other party deposit as soon as the channel exists, so
+	// opening a channel takes two confirmation rounds independent of the number of parties. The deposits of the other
+	// parties may be observed in any order. ParallelFunding requires a contract that accepts deposits in any order,
+	// which the deployed channel validator does not (see PAB.SetDepositsInAnyOrder).
+	ParallelFunding
 )
 
 type Funder struct {
-	pab *PAB
+	pab             *PAB
+	chainIndexDelay time.Duration
+	mode            FundingMode
+	ids             ChannelIDVerifier
 }
 
+// NewFunder returns a Funder that funds channels via the given PAB. It checks the channel id of every channel against
+// the wallet server of the account of the PAB before funding it (see SetChannelIDVerifier).
 func NewFunder(pab *PAB) *Funder {
 	return &Funder{
-		pab: pab,
+		pab:             pab,
+		chainIndexDelay: DefaultChainIndexDelay,
+		mode:            SequentialFunding,
+		ids:             newBackend(wallet.MakeRemoteBackend(pab.acc.GetWalletServer())),
+	}
+}
+
+// SetChainIndexDelay sets the duration for which the Funder waits before starting or funding a channel. This delay
+// avoids a race in the Adjudicator Subscription due to a slow chain index and can be reduced for PABs with a fast chain
+// index (e.g. in tests).
+func (f *Funder) SetChainIndexDelay(delay time.Duration) {
+	f.chainIndexDelay = delay
+}
+
+// SetChannelIDVerifier sets the ChannelIDVerifier with which the Funder checks the channel id of every channel before
+// funding it. Setting the channel backend of the client (see NewBackend) reuses the channel ids it already requested
+// from the wallet server.
+func (f *Funder) SetChannelIDVerifier(ids ChannelIDVerifier) {
+	f.ids = ids
+}
+
+// SetFundingMode sets the FundingMode in which the Funder funds channels. The default is SequentialFunding. It returns
+// an error for ParallelFunding, unless the PAB declares that its contract accepts deposits in any order (see
+// PAB.SetDepositsInAnyOrder).
+func (f *Funder) SetFundingMode(mode FundingMode) error {
+	switch mode {
+	case SequentialFunding:
+	case ParallelFunding:
+		if !f.pab.anyOrder {
+			return fmt.Errorf("parallel funding requires a contract that accepts deposits in any order")
+		}
+	default:
+		return fmt.Errorf("unknown funding mode: %d", mode)
 	}
+	f.mode = mode
+	return nil
 }
 
 func (f Funder) Fund(_ context.Context, req channel.FundingReq) error {
 	// TODO implement funding abort (reclamation of funds on peer misbehaviour)
+	if err := f.ids.VerifyChannelID(req.Params); err != nil {
+		return err
+	}
 	sub, err := f.pab.NewInternalSubscription(req.Params.ID())
 	if err != nil {
 		return fmt.Errorf("unable to create subscription: %w", err)
@@ -44,99 +100,263 @@ func (f Funder) Fund(_ context.Context, req channel.FundingReq) error {
 	if err != nil {
 		return fmt.Errorf("unable to convert channel state for funding: %w", err)
 	}
+	if f.mode == ParallelFunding {
+		return f.fundParallel(req.Params.ID(), sub, params, state, req.Idx)
+	}
 
+	// Parties with a zero balance never deposit (see types.ChannelDatum.FundingComplete), so there is no Deposited
+	// event to wait for.
+	var datum types.ChannelDatum
 	for i := channel.Index(0); i < req.Idx; i++ {
 		if i == 0 {
-			err = f.ExpectAndHandleStartEvent(req.Params.ID(), sub, state)
-			if err != nil {
-				return err
-			}
-		} else {
-			err = f.ExpectAndHandleDepositedEvent(req.Params.ID(), sub, i)
-			if err != nil {
-				return err
-			}
+			datum, err = f.ExpectAndHandleStartEvent(req.Params.ID(), sub, params, state)
+		} else if state.Balances[i] != 0 {
+			datum, err = f.ExpectAndHandleDepositedEvent(req.Params.ID(), sub, params, state, i)
+		}
+		if err != nil {
+			return err
 		}
 	}
 	// Unfortunately, this sleep is necessary to avoid a race in the Adjudicator Subscription due to a slow chain index.
-	time.Sleep(5 * time.Second)
+	time.Sleep(f.chainIndexDelay)
 	if req.Idx == channel.Index(0) {
 		err = f.pab.Start(req.Params.ID(), params, state)
 		if err != nil {
 			return err
 		}
-		err = f.ExpectAndHandleStartEvent(req.Params.ID(), sub, state)
+		datum, err = f.ExpectAndHandleStartEvent(req.Params.ID(), sub, params, state)
 		if err != nil {
 			return err
 		}
 
-	} else {
+	} else if state.Balances[req.Idx] != 0 {
 		err = f.pab.Fund(req.Params.ID(), req.Idx)
 		if err != nil {
 			return err
 		}
-		err = f.ExpectAndHandleDepositedEvent(req.Params.ID(), sub, req.Idx)
+		datum, err = f.ExpectAndHandleDepositedEvent(req.Params.ID(), sub, params, state, req.Idx)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Narrowing is safe, because we already checked that the number of parties is smaller than math.MaxUint16
-	for i := req.Idx + 1; i < channel.Index(len(params.Parties)); i++ {
-		err = f.ExpectAndHandleDepositedEvent(req.Params.ID(), sub, i)
+	for i := req.Idx + 1; i < channel.Index(len(params.Parties)) && !datum.Funded; i++ {
+		if state.Balances[i] == 0 {
+			continue
+		}
+		datum, err = f.ExpectAndHandleDepositedEvent(req.Params.ID(), sub, params, state, i)
 		if err != nil {
 			return err
 		}
 	}
+	if !datum.Funded {
+		return &InvalidDatumError{ChannelID: req.Params.ID(), Reason: "channel is not marked as funded after all deposits"}
+	}
 	return nil
 }
 
-func (f Funder) ExpectAndHandleStartEvent(id types.ID, sub *AdjudicatorSub, state types.ChannelState) error {
+// ExpectAndHandleStartEvent waits for the Created event of the channel with the given id, stores its channel token and
+// verifies its datum against the given parameters and initial state. It returns the verified datum.
+func (f Funder) ExpectAndHandleStartEvent(
+	id types.ID,
+	sub *AdjudicatorSub,
+	params types.ChannelParameters,
+	state types.ChannelState,
+) (types.ChannelDatum, error) {
 	event := sub.Next()
 	if event.ID() != id {
-		return MismatchingChannelIDError
+		return types.ChannelDatum{}, &MismatchingChannelIDError{Expected: id, Actual: event.ID()}
 	}
 	start, ok := event.(Created)
 	if !ok {
-		return fmt.Errorf("expected Created event, got type %T, value: %v", event, event)
+		return types.ChannelDatum{}, &UnexpectedEventError{Expected: "Created", Actual: event}
+	}
+	if err := verifyDatum(start.NewDatum, params, state); err != nil {
+		return types.ChannelDatum{}, err
 	}
 	err := f.pab.SetChannelToken(start.ID(), start.NewDatum.ChannelToken)
 	if err != nil {
-		return fmt.Errorf("unable to set channel token: %w", err)
+		return types.ChannelDatum{}, fmt.Errorf("unable to set channel token: %w", err)
 	}
+	return start.NewDatum, verifyFunding(start.NewDatum, 0)
+}
 
-	return verifyStartEvent(start.NewDatum, state)
+// ExpectAndHandleDepositedEvent waits for the Deposited event of the party with the given index and verifies its datum
+// against the channel token of the channel with the given id and the given parameters and initial state. It returns
+// the verified datum.
+func (f Funder) ExpectAndHandleDepositedEvent(
+	id types.ID,
+	sub *AdjudicatorSub,
+	params types.ChannelParameters,
+	state types.ChannelState,
+	idx channel.Index,
+) (types.ChannelDatum, error) {
+	datum, err := f.expectDepositedEvent(id, sub, params, state)
+	if err != nil {
+		return types.ChannelDatum{}, err
+	}
+	return datum, verifyFunding(datum, idx)
 }
 
-func (f Funder) ExpectAndHandleDepositedEvent(id types.ID, sub *AdjudicatorSub, idx channel.Index) error {
+// fundParallel funds the channel with the given id in ParallelFunding mode. Party 0 starts the channel, every other
+// party deposits as soon as the Created event was observed. Afterwards, the Deposited events of all parties are
+// verified in the order they are observed, until the channel is funded.
+func (f Funder) fundParallel(
+	id types.ID,
+	sub *AdjudicatorSub,
+	params types.ChannelParameters,
+	state types.ChannelState,
+	idx channel.Index,
+) error {
+	if idx == channel.Index(0) {
+		// Unfortunately, this sleep is necessary to avoid a race in the Adjudicator Subscription due to a slow chain
+		// index.
+		time.Sleep(f.chainIndexDelay)
+		if err := f.pab.Start(id, params, state); err != nil {
+			return err
+		}
+	}
+	datum, err := f.ExpectAndHandleStartEvent(id, sub, params, state)
+	if err != nil {
+		return err
+	}
+	// Parties with a zero balance never deposit (see types.ChannelDatum.FundingComplete).
+	if idx != channel.Index(0) && state.Balances[idx] != 0 {
+		time.Sleep(f.chainIndexDelay)
+		if err = f.pab.Fund(id, idx); err != nil {
+			return err
+		}
+	}
+	for !datum.Funded {
+		datum, err = f.expectDepositedEvent(id, sub, params, state)
+		if err != nil {
+			return err
+		}
+		if err = verifyDeposits(datum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expectDepositedEvent waits for the next Deposited event of the channel with the given id and verifies its datum
+// against the channel token of the channel and the given parameters and initial state. It does not verify the
+// deposits.
+func (f Funder) expectDepositedEvent(
+	id types.ID,
+	sub *AdjudicatorSub,
+	params types.ChannelParameters,
+	state types.ChannelState,
+) (types.ChannelDatum, error) {
 	event := sub.Next()
 	if event.ID() != id {
-		return MismatchingChannelIDError
+		return types.ChannelDatum{}, &MismatchingChannelIDError{Expected: id, Actual: event.ID()}
 	}
 	deposited, ok := event.(Deposited)
 	if !ok {
-		return fmt.Errorf("expected Deposited event, got type %T, value: %v", event, event)
+		return types.ChannelDatum{}, &UnexpectedEventError{Expected: "Deposited", Actual: event}
 	}
 	token, err := f.pab.GetChannelToken(deposited.ID())
 	if err != nil {
-		return err
+		return types.ChannelDatum{}, err
 	}
 	if token != deposited.NewDatum.ChannelToken {
-		return MismatchingChannelTokenError
+		return types.ChannelDatum{}, &MismatchingChannelTokenError{
+			ChannelID: id,
+			Expected:  token,
+			Actual:    deposited.NewDatum.ChannelToken,
+		}
+	}
+	if err = verifyDatum(deposited.NewDatum, params, state); err != nil {
+		return types.ChannelDatum{}, err
+	}
+	return deposited.NewDatum, nil
+}
+
+// verifyDatum verifies that the given datum of a channel in funding holds the given parameters (see equalInContract)
+// and initial state, a funding vector with one entry per party and no dispute. Its Funded flag must be set, iff every
+// party funded at least its initial balance (see types.ChannelDatum.FundingComplete).
+func verifyDatum(datum types.ChannelDatum, params types.ChannelParameters, state types.ChannelState) error {
+	if !equalInContract(datum.ChannelParameters, params) {
+		return &ChannelParametersMismatchError{ChannelID: state.ID, Expected: params, Actual: datum.ChannelParameters}
+	}
+	if !datum.ChannelState.Equal(state) {
+		return &ChannelStateMismatchError{Expected: state, Actual: datum.ChannelState}
+	}
+	if len(datum.FundingBalances) != len(params.Parties) {
+		return &InvalidDatumError{
+			ChannelID: state.ID,
+			Reason: fmt.Sprintf(
+				"funding vector has %d entries for %d parties",
+				len(datum.FundingBalances),
+				len(params.Parties),
+			),
+		}
 	}
-	return verifyFundedEvent(deposited.NewDatum, idx)
+	if datum.Disputed {
+		return &InvalidDatumError{ChannelID: state.ID, Reason: "channel is disputed during funding"}
+	}
+	if funded := datum.FundingComplete(); datum.Funded != funded {
+		return &InvalidDatumError{
+			ChannelID: state.ID,
+			Reason:    fmt.Sprintf("channel is marked as funded: %t, but funding is complete: %t", datum.Funded, funded),
+		}
+	}
+	return nil
 }
 
-func verifyStartEvent(outputDatum types.ChannelDatum, state types.ChannelState) error {
-	if !outputDatum.ChannelState.Equal(state) {
-		return errors.New("on-chain channel state does not match channel state in funding request")
+// equalInContract returns true, iff the given parameters agree in the fields the deployed contract stores: the
+// challenge duration, the signing public keys, the payment public key hashes and the nonce. The deployed contract keeps
+// neither payout addresses nor multi-signature policies (see wire.ChannelParameters), so its datums do not echo the
+// stake credentials and multi-signature policies of the parties.
+func equalInContract(a types.ChannelParameters, b types.ChannelParameters) bool {
+	storedA, err := contractParameters(a)
+	if err != nil {
+		return false
 	}
-	return verifyFundedEvent(outputDatum, 0)
+	storedB, err := contractParameters(b)
+	if err != nil {
+		return false
+	}
+	return storedA.Equal(storedB)
+}
+
+// contractParameters returns the given parameters as stored by the deployed contract.
+func contractParameters(params types.ChannelParameters) (types.ChannelParameters, error) {
+	p := wire.MakeChannelParameters(params)
+	p.PaymentAddresses = nil
+	p.MultiSigPolicies = nil
+	return p.Decode()
 }
 
-func verifyFundedEvent(outputDatum types.ChannelDatum, idx channel.Index) error {
-	if outputDatum.FundingBalances[idx] != outputDatum.ChannelState.Balances[idx] {
-		return fmt.Errorf("party %d did not fund the channel correctly", idx)
+// verifyFunding verifies that every party up to and including the given index funded exactly its initial balance.
+func verifyFunding(datum types.ChannelDatum, idx channel.Index) error {
+	for i := channel.Index(0); i <= idx; i++ {
+		if datum.FundingBalances[i] != datum.ChannelState.Balances[i] {
+			return &InsufficientFundingError{
+				ChannelID: datum.ChannelState.ID,
+				Index:     i,
+				Expected:  datum.ChannelState.Balances[i],
+				Actual:    datum.FundingBalances[i],
+			}
+		}
+	}
+	return nil
+}
+
+// verifyDeposits verifies that every party, which already deposited into the channel, funded exactly its initial
+// balance. Parties may deposit in any order, so parties without a deposit are not considered.
+func verifyDeposits(datum types.ChannelDatum) error {
+	for i, funding := range datum.FundingBalances {
+		if funding != 0 && funding != datum.ChannelState.Balances[i] {
+			return &InsufficientFundingError{
+				ChannelID: datum.ChannelState.ID,
+				Index:     channel.Index(i),
+				Expected:  datum.ChannelState.Balances[i],
+				Actual:    funding,
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,456 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"math/rand"
+	gpchannel "perun.network/go-perun/channel"
+	gpwallet "perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel"
+	chtest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+	"time"
+)
+
+// newMockPAB starts a MockPAB and returns it with a PAB client connected to it.
+func newMockPAB(t *testing.T, rng *rand.Rand) (*chtest.MockPAB, *channel.PAB) {
+	mock := chtest.NewMockPAB(rng)
+	t.Cleanup(mock.Close)
+	acc := wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), test.NewGenericRemote(nil, rng), "wallet")
+	pab, err := channel.NewPAB(mock.URL(), acc)
+	require.NoError(t, err)
+	pab.SetStatusTracking(time.Millisecond, time.Second)
+	// The MockPAB accepts deposits in any order.
+	pab.SetDepositsInAnyOrder(true)
+	return mock, pab
+}
+
+func newMockFunder(pab *channel.PAB) *channel.Funder {
+	funder := channel.NewFunder(pab)
+	funder.SetChainIndexDelay(0)
+	return funder
+}
+
+func newParallelMockFunder(t *testing.T, pab *channel.PAB) *channel.Funder {
+	funder := newMockFunder(pab)
+	require.NoError(t, funder.SetFundingMode(channel.ParallelFunding))
+	return funder
+}
+
+// makeParamsAndState returns random two-party channel parameters and a matching initial state.
+func makeParamsAndState(rng *rand.Rand) (*gpchannel.Params, *gpchannel.State) {
+	return makeNPartyParamsAndState(rng, 2)
+}
+
+// makeNPartyParamsAndState returns random channel parameters with the given number of parties and a matching initial
+// state.
+func makeNPartyParamsAndState(rng *rand.Rand, numParties int) (*gpchannel.Params, *gpchannel.State) {
+	parts := make([]gpwallet.Address, numParties)
+	for i := range parts {
+		addr := test.MakeRandomAddress(rng)
+		parts[i] = &addr
+	}
+	params := gpchannel.NewParamsUnsafe(
+		uint64(rng.Intn(1000)+1),
+		parts,
+		gpchannel.NoApp(),
+		new(big.Int).SetUint64(rng.Uint64()),
+		true,
+		false,
+	)
+	alloc := gpchannel.NewAllocation(len(parts), types.Asset)
+	for i := range parts {
+		alloc.SetBalance(gpchannel.Index(i), types.Asset, new(big.Int).SetUint64(uint64(rng.Intn(1_000_000_000)+1)))
+	}
+	state := &gpchannel.State{
+		ID:         params.ID(),
+		Version:    0,
+		App:        gpchannel.NoApp(),
+		Allocation: *alloc,
+		Data:       gpchannel.NoData(),
+	}
+	return params, state
+}
+
+func convertParamsAndState(t *testing.T, params *gpchannel.Params, state *gpchannel.State) (types.ChannelParameters, types.ChannelState) {
+	p, err := types.MakeChannelParameters(*params)
+	require.NoError(t, err)
+	s, err := types.ConvertChannelState(*state)
+	require.NoError(t, err)
+	return p, s
+}
+
+// depositOnStart lets a peer deposit the given amount into the channel with the given id, once it was started on the
+// ledger of the given MockPAB. The returned channel yields the result of the deposit.
+func depositOnStart(mock *chtest.MockPAB, id types.ID, idx uint16, amount types.Balance) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		deadline := time.Now().Add(5 * time.Second)
+		for _, ok := mock.Datum(id); !ok; _, ok = mock.Datum(id) {
+			if time.Now().After(deadline) {
+				done <- errors.New("channel was not started")
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		done <- mock.Deposit(id, idx, amount)
+	}()
+	return done
+}
+
+func TestFunder_Start(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeParamsAndState(rng)
+	_, s := convertParamsAndState(t, params, state)
+
+	// The honest peer funds the channel after it was started.
+	deposited := depositOnStart(mock, params.ID(), 1, s.Balances[1])
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 0}
+	require.NoError(t, newMockFunder(pab).Fund(context.Background(), req))
+	require.NoError(t, <-deposited)
+
+	datum, ok := mock.Datum(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+	require.Equal(t, s.Balances, datum.FundingBalances)
+}
+
+func TestFunder_ChannelIDMismatch(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, _ := newMockPAB(t, rng)
+	remote := &channelIDRemote{Remote: test.NewGenericRemote(nil, rng), wrongID: true}
+	pab, err := channel.NewPAB(mock.URL(), wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), remote, "wallet"))
+	require.NoError(t, err)
+	params, state := makeParamsAndState(rng)
+
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 0}
+	err = newMockFunder(pab).Fund(context.Background(), req)
+	var mismatch *channel.ChannelIDMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, params.ID(), mismatch.ChannelID)
+	_, ok := mock.Datum(params.ID())
+	require.False(t, ok, "channel with unconfirmed channel id was started")
+}
+
+func TestFunder_Fund(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeParamsAndState(rng)
+	p, s := convertParamsAndState(t, params, state)
+
+	// The honest peer starts the channel before we fund it.
+	_, err := mock.Start(p, s)
+	require.NoError(t, err)
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 1}
+	require.NoError(t, newMockFunder(pab).Fund(context.Background(), req))
+
+	datum, ok := mock.Datum(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+}
+
+func TestFunder_InsufficientPeerFunding(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeParamsAndState(rng)
+	_, s := convertParamsAndState(t, params, state)
+
+	// The dishonest peer deposits less than its initial balance.
+	deposited := depositOnStart(mock, params.ID(), 1, s.Balances[1]-1)
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 0}
+	err := newMockFunder(pab).Fund(context.Background(), req)
+	require.NoError(t, <-deposited)
+	var fundingErr *channel.InsufficientFundingError
+	require.True(t, errors.As(err, &fundingErr), "unexpected error: %v", err)
+	require.Equal(t, gpchannel.Index(1), fundingErr.Index)
+	require.Equal(t, s.Balances[1]-1, fundingErr.Actual)
+}
+
+func TestFunder_MismatchingStartState(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeParamsAndState(rng)
+	p, s := convertParamsAndState(t, params, state)
+
+	// The dishonest peer starts the channel with a different initial state.
+	mock.SetStartBehavior(func(datum *types.ChannelDatum) {
+		datum.ChannelState.Balances = []types.Balance{s.Balances[0] + s.Balances[1], 0}
+		datum.FundingBalances[0] = datum.ChannelState.Balances[0]
+	})
+	_, err := mock.Start(p, s)
+	require.NoError(t, err)
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 1}
+	err = newMockFunder(pab).Fund(context.Background(), req)
+	var mismatchErr *channel.ChannelStateMismatchError
+	require.True(t, errors.As(err, &mismatchErr), "unexpected error: %v", err)
+}
+
+func TestFunder_ContractError(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeParamsAndState(rng)
+
+	mock.SetEndpointFailure(chtest.StartEndpoint, "transaction does not balance")
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 0}
+	err := newMockFunder(pab).Fund(context.Background(), req)
+	var contractErr *channel.ContractError
+	require.True(t, errors.As(err, &contractErr), "unexpected error: %v", err)
+	require.Equal(t, "start", contractErr.Endpoint)
+	require.Equal(t, "transaction does not balance", contractErr.Message)
+	_, ok := mock.Datum(params.ID())
+	require.False(t, ok)
+}
+
+func TestFunder_InsufficientOwnFunding(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeParamsAndState(rng)
+	p, s := convertParamsAndState(t, params, state)
+
+	// The PAB deposits nothing on our behalf.
+	mock.SetDepositBehavior(func(uint16, types.Balance) types.Balance { return 0 })
+	_, err := mock.Start(p, s)
+	require.NoError(t, err)
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 1}
+	err = newMockFunder(pab).Fund(context.Background(), req)
+	var fundingErr *channel.InsufficientFundingError
+	require.True(t, errors.As(err, &fundingErr), "unexpected error: %v", err)
+	require.Equal(t, gpchannel.Index(1), fundingErr.Index)
+	require.Zero(t, fundingErr.Actual)
+}
+
+func TestFunder_ParallelStart(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeNPartyParamsAndState(rng, 4)
+	_, s := convertParamsAndState(t, params, state)
+
+	// The honest peers deposit concurrently after the channel was started.
+	var deposits []<-chan error
+	for i := len(s.Balances) - 1; i > 0; i-- {
+		deposits = append(deposits, depositOnStart(mock, params.ID(), uint16(i), s.Balances[i]))
+	}
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 0}
+	require.NoError(t, newParallelMockFunder(t, pab).Fund(context.Background(), req))
+	for _, deposited := range deposits {
+		require.NoError(t, <-deposited)
+	}
+
+	datum, ok := mock.Datum(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+	require.Equal(t, s.Balances, datum.FundingBalances)
+}
+
+func TestFunder_ParallelOutOfOrder(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeNPartyParamsAndState(rng, 3)
+	p, s := convertParamsAndState(t, params, state)
+
+	// The peer with the highest index deposits before we do.
+	_, err := mock.Start(p, s)
+	require.NoError(t, err)
+	require.NoError(t, mock.Deposit(params.ID(), 2, s.Balances[2]))
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 1}
+	require.NoError(t, newParallelMockFunder(t, pab).Fund(context.Background(), req))
+
+	datum, ok := mock.Datum(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+}
+
+func TestFunder_ParallelInsufficientPeerFunding(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	params, state := makeNPartyParamsAndState(rng, 3)
+	_, s := convertParamsAndState(t, params, state)
+
+	// The dishonest peer with the highest index deposits less than its initial balance.
+	deposited := depositOnStart(mock, params.ID(), 2, s.Balances[2]-1)
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 0}
+	err := newParallelMockFunder(t, pab).Fund(context.Background(), req)
+	require.NoError(t, <-deposited)
+	var fundingErr *channel.InsufficientFundingError
+	require.True(t, errors.As(err, &fundingErr), "unexpected error: %v", err)
+	require.Equal(t, gpchannel.Index(2), fundingErr.Index)
+	require.Equal(t, s.Balances[2]-1, fundingErr.Actual)
+}
+
+func TestFunder_ParallelFundingRequiresAnyOrder(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, _ := newMockPAB(t, rng)
+	pab, err := channel.NewPAB(mock.URL(), wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), nil, "wallet"))
+	require.NoError(t, err)
+	funder := channel.NewFunder(pab)
+	require.Error(t, funder.SetFundingMode(channel.ParallelFunding), "parallel funding was enabled without any order")
+	pab.SetDepositsInAnyOrder(true)
+	require.NoError(t, funder.SetFundingMode(channel.ParallelFunding))
+	require.Error(t, funder.SetFundingMode(channel.FundingMode(-1)), "unknown funding mode was enabled")
+}
+
+func TestFunder_ZeroBalances(t *testing.T) {
+	testCases := []struct {
+		name string
+		// zero holds the indices of the parties with a zero balance.
+		zero []int
+		idx  gpchannel.Index
+	}{
+		{name: "funded on start/starter", zero: []int{1, 2}, idx: 0},
+		{name: "funded on start/zero balance", zero: []int{1, 2}, idx: 2},
+		{name: "funded without own deposit", zero: []int{2}, idx: 2},
+		{name: "funded by own deposit", zero: []int{2}, idx: 1},
+		{name: "no deposit of lower party", zero: []int{1}, idx: 2},
+	}
+	modes := map[string]func(*testing.T, *channel.PAB) *channel.Funder{
+		"sequential": func(_ *testing.T, pab *channel.PAB) *channel.Funder { return newMockFunder(pab) },
+		"parallel":   newParallelMockFunder,
+	}
+	for _, tc := range testCases {
+		for mode, newFunder := range modes {
+			tc, newFunder := tc, newFunder
+			t.Run(mode+"/"+tc.name, func(t *testing.T) {
+				rng := pkgtest.Prng(t)
+				mock, pab := newMockPAB(t, rng)
+				params, state := makeNPartyParamsAndState(rng, 3)
+				for _, i := range tc.zero {
+					state.Allocation.SetBalance(gpchannel.Index(i), types.Asset, big.NewInt(0))
+				}
+				p, s := convertParamsAndState(t, params, state)
+
+				// The honest peers with a lower index start and fund the channel before we fund it.
+				if tc.idx > 0 {
+					_, err := mock.Start(p, s)
+					require.NoError(t, err)
+				}
+				for i := 1; i < int(tc.idx); i++ {
+					if s.Balances[i] != 0 {
+						require.NoError(t, mock.Deposit(params.ID(), uint16(i), s.Balances[i]))
+					}
+				}
+				req := gpchannel.FundingReq{Params: params, State: state, Idx: tc.idx}
+				require.NoError(t, newFunder(t, pab).Fund(context.Background(), req))
+
+				datum, ok := mock.Datum(params.ID())
+				require.True(t, ok)
+				require.True(t, datum.Funded)
+				require.Equal(t, s.Balances, datum.FundingBalances)
+			})
+		}
+	}
+}
+
+func TestFunder_DatumWithoutPayoutAddresses(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock, pab := newMockPAB(t, rng)
+	parts := make([]gpwallet.Address, 2)
+	for i := range parts {
+		addr := test.MakeRandomAddressWithStakeCredential(rng)
+		parts[i] = &addr
+	}
+	params := gpchannel.NewParamsUnsafe(60, parts, gpchannel.NoApp(), big.NewInt(rng.Int63()), true, false)
+	_, state := makeParamsAndState(rng)
+	state.ID = params.ID()
+	p, s := convertParamsAndState(t, params, state)
+
+	// The deployed contract keeps neither stake credentials nor multi-signature policies, so its datum lacks them.
+	mock.SetStartBehavior(func(datum *types.ChannelDatum) {
+		parties := make([]address.Address, len(datum.ChannelParameters.Parties))
+		for i, party := range datum.ChannelParameters.Parties {
+			party.RemoveStakeCredential()
+			parties[i] = party
+		}
+		datum.ChannelParameters.Parties = parties
+	})
+	_, err := mock.Start(p, s)
+	require.NoError(t, err)
+	req := gpchannel.FundingReq{Params: params, State: state, Idx: 1}
+	require.NoError(t, newMockFunder(pab).Fund(context.Background(), req))
+	datum, ok := mock.Datum(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+}
+
+func TestFunder_InvalidStartDatum(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tamper   func(datum *types.ChannelDatum)
+		expected interface{}
+	}{
+		{
+			name:     "challenge duration",
+			tamper:   func(datum *types.ChannelDatum) { datum.ChannelParameters.Timeout += time.Second },
+			expected: new(*channel.ChannelParametersMismatchError),
+		},
+		{
+			name: "parties",
+			tamper: func(datum *types.ChannelDatum) {
+				parties := datum.ChannelParameters.Parties
+				datum.ChannelParameters.Parties = []address.Address{parties[1], parties[0]}
+			},
+			expected: new(*channel.ChannelParametersMismatchError),
+		},
+		{
+			name:     "funding vector length",
+			tamper:   func(datum *types.ChannelDatum) { datum.FundingBalances = append(datum.FundingBalances, 0) },
+			expected: new(*channel.InvalidDatumError),
+		},
+		{
+			name:     "disputed",
+			tamper:   func(datum *types.ChannelDatum) { datum.Disputed = true },
+			expected: new(*channel.InvalidDatumError),
+		},
+		{
+			name:     "funded",
+			tamper:   func(datum *types.ChannelDatum) { datum.Funded = true },
+			expected: new(*channel.InvalidDatumError),
+		},
+		{
+			name: "not funded",
+			tamper: func(datum *types.ChannelDatum) {
+				datum.FundingBalances = append([]types.Balance(nil), datum.ChannelState.Balances...)
+			},
+			expected: new(*channel.InvalidDatumError),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := pkgtest.Prng(t)
+			mock, pab := newMockPAB(t, rng)
+			params, state := makeParamsAndState(rng)
+			p, s := convertParamsAndState(t, params, state)
+
+			// The dishonest peer starts the channel with a tampered datum.
+			mock.SetStartBehavior(tc.tamper)
+			_, err := mock.Start(p, s)
+			require.NoError(t, err)
+			req := gpchannel.FundingReq{Params: params, State: state, Idx: 1}
+			err = newMockFunder(pab).Fund(context.Background(), req)
+			require.True(t, errors.As(err, tc.expected), "unexpected error: %v", err)
+			_, ok := mock.Datum(params.ID())
+			require.True(t, ok)
+		})
+	}
+}
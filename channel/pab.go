@@ -1,28 +1,41 @@
 package channel
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"perun.network/go-perun/channel"
 	gpwallet "perun.network/go-perun/wallet"
 	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/transport"
 	"perun.network/perun-cardano-backend/wallet"
 	"perun.network/perun-cardano-backend/wire"
+	"time"
 )
 
 const (
-	ContractEndpoint    = "/api/contract"
-	ActivateEndpoint    = ContractEndpoint + "/activate"
-	InstanceEndpoint    = ContractEndpoint + "/instance"
-	WebSocketEndpoint   = "/ws"
-	StartEndpointFormat = InstanceEndpoint + "/%s/endpoint/start"
-	FundEndpointFormat  = InstanceEndpoint + "/%s/endpoint/fund"
-	CloseEndpointFormat = InstanceEndpoint + "/%s/endpoint/close"
+	ContractEndpoint     = "/api/contract"
+	ActivateEndpoint     = ContractEndpoint + "/activate"
+	InstanceEndpoint     = ContractEndpoint + "/instance"
+	WebSocketEndpoint    = "/ws"
+	StartEndpointFormat  = InstanceEndpoint + "/%s/endpoint/start"
+	FundEndpointFormat   = InstanceEndpoint + "/%s/endpoint/fund"
+	CloseEndpointFormat  = InstanceEndpoint + "/%s/endpoint/close"
+	StatusEndpointFormat = InstanceEndpoint + "/%s/status"
+
+	DisputeEndpointFormat    = InstanceEndpoint + "/%s/endpoint/dispute"
+	ForceCloseEndpointFormat = InstanceEndpoint + "/%s/endpoint/forceClose"
+	AbortEndpointFormat      = InstanceEndpoint + "/%s/endpoint/abort"
+)
+
+const (
+	// DefaultStatusPollInterval is the recommended interval in which the status of the contract instance is queried
+	// after calling one of its endpoints (see PAB.SetStatusTracking).
+	DefaultStatusPollInterval = time.Second
+	// DefaultStatusTimeout is the recommended duration for which the status of the contract instance is tracked after
+	// calling one of its endpoints (see PAB.SetStatusTracking).
+	DefaultStatusTimeout = 2 * time.Minute
 )
 
 // PAB is a client for the PAB server. It is used to create and interact with Perun Channel contracts through the PAB
@@ -30,40 +43,67 @@ const (
 // One PAB instance can be used by one account to create multiple channels and create an arbitrary number of
 // subscriptions per channel.
 type PAB struct {
-	tokenMap            map[channel.ID]types.ChannelToken
-	contractInstanceID  string
-	acc                 wallet.RemoteAccount
-	subscriptionUrlBase *url.URL
+	tokenMap           map[channel.ID]types.ChannelToken
+	contractInstanceID string
+	acc                wallet.RemoteAccount
+	statusPollInterval time.Duration
+	statusTimeout      time.Duration
+	// anyOrder is set, if the contract accepts the deposits of the parties of a channel in any order.
+	anyOrder bool
 	pabRemote
 }
 
 // pabRemote is responsible for calling the PAB server endpoints.
 type pabRemote struct {
-	pabUrl *url.URL
-	client *http.Client
+	// pabUrls holds the url of the PAB server followed by the urls of its failover hosts.
+	pabUrls []string
+	// wsUrls holds the websocket base url for each of pabUrls.
+	wsUrls    []*url.URL
+	transport *transport.Config
 }
 
-// NewPAB creates a new PAB instance. It expects a host string in the format "host:port" (e.g. "localhost:9080").
-func NewPAB(host string, acc wallet.RemoteAccount) (*PAB, error) {
-	pabUrl, err := url.Parse("http://" + host)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse pab url: %w", err)
-	}
-	subscriptionUrl, err := url.Parse("ws://" + host + WebSocketEndpoint)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse subscription url: %w", err)
+// NewPAB creates a new PAB instance. It expects a host string in the format "host:port" (e.g. "localhost:9080") or a
+// url with scheme http or https (e.g. "https://pab.example.com"). Without a scheme, https and wss are used, iff TLS
+// options are given. The options configure TLS, authentication and timeouts of all requests to the PAB server, as well
+// as retries and failover (see transport.WithRetryPolicy and transport.WithFailoverHosts). Requests to contract
+// endpoints are never repeated after they may have reached the PAB server, to prevent double submission. The session
+// with the PAB server, including all subscriptions, can be recorded and replayed (see transport.WithRecorder and
+// transport.WithReplayer).
+func NewPAB(host string, acc wallet.RemoteAccount, opts ...transport.Option) (*PAB, error) {
+	t := transport.NewConfig(opts...)
+	remote := pabRemote{transport: t}
+	for _, h := range append([]string{host}, t.FailoverHosts()...) {
+		pabUrl, wsUrl, err := t.BaseURLs(h)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse pab url: %w", err)
+		}
+		remote.pabUrls = append(remote.pabUrls, pabUrl.String())
+		remote.wsUrls = append(remote.wsUrls, wsUrl)
 	}
 	return &PAB{
-		tokenMap:            make(map[channel.ID]types.ChannelToken),
-		acc:                 acc,
-		subscriptionUrlBase: subscriptionUrl,
-		pabRemote: pabRemote{
-			pabUrl: pabUrl,
-			client: &http.Client{},
-		},
+		tokenMap:  make(map[channel.ID]types.ChannelToken),
+		acc:       acc,
+		pabRemote: remote,
 	}, nil
 }
 
+// SetStatusTracking enables tracking the status of the contract instance after calling one of its endpoints (see
+// Start). The status is queried in the given interval for at most the given timeout, which bounds how long Start, Fund
+// and Close block. Status tracking is disabled by default and a non-positive timeout disables it again. Without status
+// tracking, Start, Fund and Close return as soon as the PAB accepted the call. DefaultStatusPollInterval and
+// DefaultStatusTimeout are the recommended values.
+func (p *PAB) SetStatusTracking(interval time.Duration, timeout time.Duration) {
+	p.statusPollInterval = interval
+	p.statusTimeout = timeout
+}
+
+// SetDepositsInAnyOrder declares whether the channel contract served by the PAB accepts the deposits of the parties of
+// a channel in any order. The deployed channel validator requires the parties to deposit in the order of their index,
+// so this is disabled by default. ParallelFunding requires it (see Funder.SetFundingMode).
+func (p *PAB) SetDepositsInAnyOrder(anyOrder bool) {
+	p.anyOrder = anyOrder
+}
+
 // CallEndpoint calls the given endpoint on the remote wallet and decodes the json response into the given result.
 // `result` must be a pointer.
 func (r *pabRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
@@ -84,35 +124,27 @@ func (r *pabRemote) CallEndpoint(endpoint string, body interface{}, result inter
 	return nil
 }
 
-// callEndpoint issues a request to the given endpoint with the given body.
-func (r *pabRemote) callEndpoint(jsonBody []byte, endpoint string) ([]byte, error) {
-	request, err := http.NewRequest("POST", r.pabUrl.String()+endpoint, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("unable to prepare http request: %w", err)
-	}
-	request.Header.Set("Content-Type", "application/json")
-
-	response, err := r.client.Do(request)
+// QueryEndpoint queries the given endpoint of the PAB server and decodes the json response into the given result.
+// Queries are retried and failed over like requests to idempotent endpoints. `result` must be a pointer.
+func (r *pabRemote) QueryEndpoint(endpoint string, result interface{}) error {
+	jsonResponse, err := r.transport.Query("PAB server", r.pabUrls, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("unable to send http request: %w", err)
-	}
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		errorBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to interact with PAB server: %s", response.Status)
-		}
-		return nil, fmt.Errorf(
-			"failed to interact with PAB server: %s with error: %s",
-			response.Status,
-			string(errorBody),
-		)
+		return fmt.Errorf("failed to query endpoint: %w", err)
 	}
-	jsonResponse, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("unable to read server response: %w", err)
+	if err = json.Unmarshal(jsonResponse, result); err != nil {
+		return fmt.Errorf("failed to unmarshal PAB server response: %w", err)
 	}
-	return jsonResponse, nil
+	return nil
+}
+
+// callEndpoint issues a request to the given endpoint with the given body.
+func (r *pabRemote) callEndpoint(jsonBody []byte, endpoint string) ([]byte, error) {
+	return r.transport.Call("PAB server", r.pabUrls, endpoint, false, jsonBody)
+}
+
+// subscriptionUrl returns the websocket url of the given contract instance on the current PAB server.
+func (r *pabRemote) subscriptionUrl(contractInstanceID string) *url.URL {
+	return r.wsUrls[r.transport.CurrentHost()].JoinPath(WebSocketEndpoint, contractInstanceID)
 }
 
 func (p *PAB) SetChannelToken(id channel.ID, token types.ChannelToken) error {
@@ -156,8 +188,92 @@ func (p *PAB) createSubscription(id channel.ID, isPerunSub bool) (*AdjudicatorSu
 	if err != nil {
 		return nil, fmt.Errorf("failed to activate subscription contract: %w", err)
 	}
-	subUrl := p.subscriptionUrlBase.JoinPath(response.Decode())
-	return newAdjudicatorSub(subUrl, id, isPerunSub)
+	subUrl := p.subscriptionUrl(response.Decode())
+	conn, err := p.transport.Dial("PAB server", subUrl.String(), WebSocketEndpoint+"/"+response.Decode())
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish connection to PAB: %w", err)
+	}
+	return newAdjudicatorSub(conn, id, isPerunSub), nil
+}
+
+// instanceStatus queries the status of the perun contract instance.
+func (p *PAB) instanceStatus() (wire.ContractInstanceStatus, error) {
+	var status wire.ContractInstanceStatus
+	err := p.pabRemote.QueryEndpoint(fmt.Sprintf(StatusEndpointFormat, p.contractInstanceID), &status)
+	if err != nil {
+		return status, fmt.Errorf("failed to query contract instance status: %w", err)
+	}
+	return status, nil
+}
+
+// callContractEndpoint calls the given endpoint of the perun contract instance. If status tracking is enabled, it
+// tracks the instance status until the contract instance handled the call (see awaitEndpoint).
+func (p *PAB) callContractEndpoint(endpoint string, endpointFormat string, request interface{}) error {
+	if p.contractInstanceID == "" {
+		if err := p.activateContract(); err != nil {
+			return fmt.Errorf("failed to activate contract: %w", err)
+		}
+	}
+	var before wire.ContractInstanceStatus
+	if p.statusTimeout > 0 {
+		var err error
+		if before, err = p.instanceStatus(); err != nil {
+			return err
+		}
+	}
+	err := p.pabRemote.CallEndpoint(fmt.Sprintf(endpointFormat, p.contractInstanceID), request, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call endpoint %s: %w", endpoint, err)
+	}
+	if p.statusTimeout <= 0 {
+		return nil
+	}
+	return p.awaitEndpoint(endpoint, before)
+}
+
+// awaitEndpoint polls the status of the contract instance, until it exposes the given endpoint again with a new
+// request id, i.e., the contract handled the call. If the contract instance reports an error in the meantime, either
+// through a new error state or through an error log message emitted after the call, a ContractError holding the id of
+// the transaction last mentioned in these logs is returned. Errors reported before the call are not attributed to it. If the call is neither handled nor reported as failed within the status timeout,
+// an EndpointTimeoutError is returned.
+func (p *PAB) awaitEndpoint(endpoint string, before wire.ContractInstanceStatus) error {
+	requestID := before.LatestRequestID()
+	deadline := time.Now().Add(p.statusTimeout)
+	var txID string
+	for {
+		status, err := p.instanceStatus()
+		if err != nil {
+			return err
+		}
+		for _, log := range status.LogsSince(len(before.CurrentState.Logs)) {
+			if ids := log.TxIDs(); len(ids) > 0 {
+				txID = ids[len(ids)-1]
+			}
+			if log.IsError() {
+				return &ContractError{
+					InstanceID: p.contractInstanceID,
+					Endpoint:   endpoint,
+					TxID:       txID,
+					Message:    log.Message(),
+				}
+			}
+		}
+		if msg, failed := status.ErrorSince(before); failed {
+			return &ContractError{InstanceID: p.contractInstanceID, Endpoint: endpoint, TxID: txID, Message: msg}
+		}
+		if status.ExposesEndpointSince(endpoint, requestID) {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return &EndpointTimeoutError{
+				InstanceID: p.contractInstanceID,
+				Endpoint:   endpoint,
+				TxID:       txID,
+				Timeout:    p.statusTimeout,
+			}
+		}
+		time.Sleep(p.statusPollInterval)
+	}
 }
 
 // NewInternalSubscription creates a new adjudicator subscription for the given channel. The subscription will return
@@ -176,38 +292,25 @@ func (p *PAB) NewPerunEventSubscription(id channel.ID) (*AdjudicatorSub, error)
 	return p.createSubscription(id, true)
 }
 
-// Start issues a request to the PAB to start the channel with the given parameters and initial state.
+// Start issues a request to the PAB to start the channel with the given parameters and initial state. If status
+// tracking is enabled (see SetStatusTracking), it tracks the status of the contract instance after the request was
+// accepted, like Fund and Close, and returns a ContractError, if the contract instance reports an error for the call
+// (e.g. the transaction does not balance or is rejected by the validator). It then blocks until the contract instance
+// handled the call, but at most for the status timeout, after which it returns an EndpointTimeoutError. Otherwise, it
+// returns as soon as the PAB accepted the request.
 func (p *PAB) Start(cid channel.ID, params types.ChannelParameters, state types.ChannelState) error {
-	if p.contractInstanceID == "" {
-		if err := p.activateContract(); err != nil {
-			return fmt.Errorf("failed to activate contract: %w", err)
-		}
-	}
 	request := wire.MakeOpenParams(cid, params, state)
-	err := p.pabRemote.CallEndpoint(fmt.Sprintf(StartEndpointFormat, p.contractInstanceID), request, nil)
-	if err != nil {
-		return fmt.Errorf("failed to call endpoint start: %w", err)
-	}
-	return nil
+	return p.callContractEndpoint("start", StartEndpointFormat, request)
 }
 
-// Fund issues a request to the PAB to fund the channel with the given parameters.
+// Fund issues a request to the PAB to fund the channel with the given parameters. It blocks like Start.
 func (p *PAB) Fund(cid channel.ID, index channel.Index) error {
-	if p.contractInstanceID == "" {
-		if err := p.activateContract(); err != nil {
-			return fmt.Errorf("failed to activate contract: %w", err)
-		}
-	}
 	ct, err := p.GetChannelToken(cid)
 	if err != nil {
 		return fmt.Errorf("failed to fund channel: %w", err)
 	}
 	request := wire.MakeFundParams(cid, ct, uint16(index))
-	err = p.pabRemote.CallEndpoint(fmt.Sprintf(FundEndpointFormat, p.contractInstanceID), request, nil)
-	if err != nil {
-		return fmt.Errorf("failed to call endpoint fund: %w", err)
-	}
-	return nil
+	return p.callContractEndpoint("fund", FundEndpointFormat, request)
 }
 
 // Abort issues a request to the PAB to abort the channel. This only works on channels that
@@ -223,23 +326,15 @@ func (p *PAB) Dispute() {
 	panic("implement me")
 }
 
-// Close issues a request to the PAB to close the channel with the given parameters and final state.
+// Close issues a request to the PAB to close the channel with the given parameters and final state. It blocks like
+// Start.
 func (p *PAB) Close(id channel.ID, params types.ChannelParameters, state types.ChannelState, sigs []gpwallet.Sig) error {
-	if p.contractInstanceID == "" {
-		if err := p.activateContract(); err != nil {
-			return fmt.Errorf("failed to activate contract: %w", err)
-		}
-	}
 	ct, err := p.GetChannelToken(id)
 	if err != nil {
 		return fmt.Errorf("failed to close channel: %w", err)
 	}
 	request := wire.MakeCloseParams(id, ct, params, state, sigs)
-	err = p.pabRemote.CallEndpoint(fmt.Sprintf(CloseEndpointFormat, p.contractInstanceID), request, nil)
-	if err != nil {
-		return fmt.Errorf("failed to call endpoint close: %w", err)
-	}
-	return nil
+	return p.callContractEndpoint("close", CloseEndpointFormat, request)
 }
 
 // ForceClose issues a request to the PAB to force close the channel with the given parameters and state. This settles
@@ -0,0 +1,287 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	gpchannel "perun.network/go-perun/channel"
+	"perun.network/perun-cardano-backend/channel"
+	chtest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testInstanceID = "4f3c6a8b-perun"
+	testTxID       = "a707536d289e9eb51f251ffb193704f8c1c99692148bcc992616b1b11d364c41"
+)
+
+// fakeContractInstance is a PAB server hosting a single perun contract instance. Calls to the fund endpoint update the
+// instance status with onFund.
+type fakeContractInstance struct {
+	mu     sync.Mutex
+	status wire.ContractInstanceStatus
+	onFund func(status *wire.ContractInstanceStatus)
+	calls  int
+}
+
+// setOnFund replaces the function with which calls to the fund endpoint update the instance status.
+func (f *fakeContractInstance) setOnFund(onFund func(status *wire.ContractInstanceStatus)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onFund = onFund
+}
+
+// fundCalls returns the number of calls to the fund endpoint.
+func (f *fakeContractInstance) fundCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func newFakeContractInstance(onFund func(status *wire.ContractInstanceStatus)) *fakeContractInstance {
+	f := &fakeContractInstance{onFund: onFund}
+	f.status.ContractID.ID = testInstanceID
+	f.status.Status = wire.ContractInstanceStatusActive
+	f.status.CurrentState.Hooks = []wire.ContractHook{makeHook(0, "start"), makeHook(1, "fund")}
+	return f
+}
+
+func (f *fakeContractInstance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var response interface{}
+	switch {
+	case r.URL.Path == channel.ActivateEndpoint:
+		response = wire.ContractInstanceID{ID: testInstanceID}
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/status"):
+		response = f.status
+	case strings.HasSuffix(r.URL.Path, "/endpoint/fund"):
+		f.calls++
+		f.onFund(&f.status)
+		response = []interface{}{}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func makeHook(requestID int, endpoint string) wire.ContractHook {
+	var hook wire.ContractHook
+	hook.RequestID = requestID
+	hook.Request.Description.Endpoint = endpoint
+	return hook
+}
+
+func makeLog(level string, message string) wire.LogMessage {
+	content, _ := json.Marshal(message)
+	return wire.LogMessage{Level: level, Content: content}
+}
+
+func newTestPAB(t *testing.T, instance *fakeContractInstance) (*channel.PAB, types.ID) {
+	server := httptest.NewServer(instance)
+	t.Cleanup(server.Close)
+	rng := pkgtest.Prng(t)
+	acc := wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), nil, "wallet")
+	pab, err := channel.NewPAB(server.URL, acc)
+	require.NoError(t, err)
+	pab.SetStatusTracking(time.Millisecond, time.Second)
+	var id types.ID
+	rng.Read(id[:])
+	require.NoError(t, pab.SetChannelToken(id, types.ChannelToken{TokenName: "token"}))
+	return pab, id
+}
+
+func TestPAB_Fund(t *testing.T) {
+	instance := newFakeContractInstance(func(status *wire.ContractInstanceStatus) {
+		status.CurrentState.Logs = append(status.CurrentState.Logs, makeLog("Info", "funded with "+testTxID))
+		status.CurrentState.Hooks = []wire.ContractHook{makeHook(2, "start"), makeHook(3, "fund")}
+	})
+	pab, id := newTestPAB(t, instance)
+	require.NoError(t, pab.Fund(id, 0))
+	require.Equal(t, testInstanceID, pab.GetContractInstanceID())
+}
+
+func TestPAB_ContractErrorLog(t *testing.T) {
+	instance := newFakeContractInstance(func(status *wire.ContractInstanceStatus) {
+		status.CurrentState.Logs = append(status.CurrentState.Logs,
+			makeLog("Info", "submitted transaction "+testTxID),
+			makeLog(wire.LogLevelError, "validator rejected transaction"),
+		)
+	})
+	pab, id := newTestPAB(t, instance)
+	err := pab.Fund(id, 1)
+	var contractErr *channel.ContractError
+	require.True(t, errors.As(err, &contractErr))
+	require.Equal(t, testInstanceID, contractErr.InstanceID)
+	require.Equal(t, "fund", contractErr.Endpoint)
+	require.Equal(t, testTxID, contractErr.TxID)
+	require.Equal(t, "validator rejected transaction", contractErr.Message)
+	require.Equal(t, errclass.Contract, errclass.Of(err))
+}
+
+func TestPAB_ContractErrorState(t *testing.T) {
+	instance := newFakeContractInstance(func(status *wire.ContractInstanceStatus) {
+		status.CurrentState.Err = json.RawMessage(`{"tag":"WalletContractError","contents":"transaction does not balance"}`)
+	})
+	pab, id := newTestPAB(t, instance)
+	err := pab.Fund(id, 1)
+	var contractErr *channel.ContractError
+	require.True(t, errors.As(err, &contractErr))
+	require.Empty(t, contractErr.TxID)
+	require.Contains(t, contractErr.Message, "transaction does not balance")
+
+	// The error is not attributed to subsequent calls, which the contract instance handles.
+	instance.setOnFund(func(status *wire.ContractInstanceStatus) {
+		status.CurrentState.Hooks = []wire.ContractHook{makeHook(2, "start"), makeHook(3, "fund")}
+	})
+	require.NoError(t, pab.Fund(id, 1))
+	require.Equal(t, 2, instance.fundCalls())
+}
+
+func TestPAB_NoStatusTracking(t *testing.T) {
+	// The contract instance never handles the call.
+	instance := newFakeContractInstance(func(*wire.ContractInstanceStatus) {})
+	server := httptest.NewServer(instance)
+	t.Cleanup(server.Close)
+	rng := pkgtest.Prng(t)
+	acc := wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), nil, "wallet")
+	pab, err := channel.NewPAB(server.URL, acc)
+	require.NoError(t, err)
+	var id types.ID
+	rng.Read(id[:])
+	require.NoError(t, pab.SetChannelToken(id, types.ChannelToken{TokenName: "token"}))
+
+	// Without status tracking, which is disabled by default, calls return as soon as the PAB accepted them.
+	require.NoError(t, pab.Fund(id, 0))
+	require.Equal(t, 1, instance.fundCalls())
+
+	// Status tracking can be disabled again.
+	pab.SetStatusTracking(time.Millisecond, time.Second)
+	pab.SetStatusTracking(time.Millisecond, 0)
+	require.NoError(t, pab.Fund(id, 0))
+	require.Equal(t, 2, instance.fundCalls())
+}
+
+func TestPAB_StatusTimeout(t *testing.T) {
+	instance := newFakeContractInstance(func(status *wire.ContractInstanceStatus) {
+		status.CurrentState.Logs = append(status.CurrentState.Logs, makeLog("Info", "submitted transaction "+testTxID))
+	})
+	pab, id := newTestPAB(t, instance)
+	pab.SetStatusTracking(time.Millisecond, 20*time.Millisecond)
+	start := time.Now()
+	err := pab.Fund(id, 0)
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	var timeoutErr *channel.EndpointTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, testInstanceID, timeoutErr.InstanceID)
+	require.Equal(t, "fund", timeoutErr.Endpoint)
+	require.Equal(t, testTxID, timeoutErr.TxID)
+	require.Equal(t, 20*time.Millisecond, timeoutErr.Timeout)
+	require.Equal(t, errclass.Ambiguous, errclass.Of(err))
+}
+
+func TestPAB_FaultyTransport(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock := chtest.NewMockPAB(rng)
+	t.Cleanup(mock.Close)
+	faulty, err := chtest.NewFaultyPAB(mock.URL(), rand.New(rand.NewSource(rng.Int63())), chtest.PABFaults{
+		DuplicateEventRate: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(faulty.Close)
+	acc := wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), nil, "wallet")
+	pab, err := channel.NewPAB(faulty.URL(), acc, transport.WithRetryPolicy(transport.RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	}))
+	require.NoError(t, err)
+	pab.SetStatusTracking(time.Millisecond, time.Second)
+
+	params, state := makeParamsAndState(rng)
+	p, s := convertParamsAndState(t, params, state)
+	token, err := mock.Start(p, s)
+	require.NoError(t, err)
+	require.NoError(t, pab.SetChannelToken(params.ID(), token))
+	sub, err := pab.NewInternalSubscription(params.ID())
+	require.NoError(t, err)
+	defer sub.Close()
+	for i := 0; i < 2; i++ {
+		_, ok := sub.Next().(channel.Created)
+		require.True(t, ok, "created event was not duplicated")
+	}
+
+	faulty.SetFaults(chtest.PABFaults{ServerErrorRate: 1})
+	err = pab.Fund(params.ID(), 1)
+	var statusErr *transport.StatusError
+	require.True(t, errors.As(err, &statusErr), "injected server error was not reported")
+	require.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+
+	faulty.SetFaults(chtest.PABFaults{})
+	require.NoError(t, pab.Fund(params.ID(), 1), "funding failed without faults")
+}
+
+func TestPAB_RecordReplay(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock := chtest.NewMockPAB(rng)
+	acc := wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), nil, "wallet")
+	params, state := makeParamsAndState(rng)
+	p, s := convertParamsAndState(t, params, state)
+	token, err := mock.Start(p, s)
+	require.NoError(t, err)
+
+	session := func(host string, opt transport.Option) []gpchannel.AdjudicatorEvent {
+		pab, err := channel.NewPAB(host, acc, opt)
+		require.NoError(t, err)
+		pab.SetStatusTracking(time.Millisecond, time.Second)
+		require.NoError(t, pab.SetChannelToken(params.ID(), token))
+		sub, err := pab.NewInternalSubscription(params.ID())
+		require.NoError(t, err)
+		defer sub.Close()
+		events := []gpchannel.AdjudicatorEvent{sub.Next()}
+		require.NoError(t, pab.Fund(params.ID(), 1))
+		return append(events, sub.Next())
+	}
+
+	var recording bytes.Buffer
+	recorder := transport.NewRecorder(&recording)
+	recorded := session(mock.URL(), transport.WithRecorder(recorder))
+	require.NoError(t, recorder.Close())
+	mock.Close()
+	require.IsType(t, channel.Created{}, recorded[0])
+	require.IsType(t, channel.Deposited{}, recorded[1])
+
+	replayer, err := transport.ReadRecording(&recording)
+	require.NoError(t, err)
+	replayed := session("http://replayed.example.com", transport.WithReplayer(replayer))
+	require.Equal(t, recorded, replayed, "replayed session yields different events")
+	require.Zero(t, replayer.Unconsumed(), "recorded exchanges were not replayed")
+}
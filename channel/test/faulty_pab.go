@@ -0,0 +1,222 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"fmt"
+	"github.com/gorilla/websocket"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// PABFaults configures the faults a FaultyPAB injects into the traffic between PAB clients and the PAB server. Rates
+// are probabilities in [0, 1], which are rolled independently for every request or websocket message.
+type PABFaults struct {
+	// MaxLatency is the maximum latency added to every request and websocket message. The latency is uniformly
+	// distributed in [0, MaxLatency].
+	MaxLatency time.Duration
+	// DropRate is the probability of the connection of a request being closed before the request is forwarded.
+	DropRate float64
+	// ServerErrorRate is the probability of a request failing with 503 Service Unavailable before it is forwarded.
+	ServerErrorRate float64
+	// TruncateRate is the probability of the response body of a forwarded request being truncated.
+	TruncateRate float64
+	// DuplicateEventRate is the probability of a websocket message being relayed twice.
+	DuplicateEventRate float64
+	// ReorderEventRate is the probability of a websocket message being held back and relayed after the next message.
+	ReorderEventRate float64
+	// DropSubscriptionRate is the probability of a websocket connection being closed instead of relaying a message.
+	DropSubscriptionRate float64
+}
+
+// FaultyPAB is a proxy in front of a PAB server (e.g. a real PAB or a MockPAB), which injects the faults configured by
+// PABFaults into the HTTP and websocket traffic. The faults are driven by the given rng, so a failing run can be
+// reproduced with the same seed, as long as requests and events occur in the same order.
+// FaultyPAB should only be instantiated using NewFaultyPAB.
+type FaultyPAB struct {
+	server   *httptest.Server
+	target   *url.URL
+	proxy    *httputil.ReverseProxy
+	upgrader websocket.Upgrader
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	faults PABFaults
+}
+
+// NewFaultyPAB starts a new FaultyPAB forwarding to the PAB server with the given url (e.g. "http://localhost:9080").
+// The FaultyPAB must receive an exclusive rand.Rand instance and must be closed using Close.
+func NewFaultyPAB(target string, rng *rand.Rand, faults PABFaults) (*FaultyPAB, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse pab url: %w", err)
+	}
+	p := &FaultyPAB{
+		target: targetURL,
+		proxy:  httputil.NewSingleHostReverseProxy(targetURL),
+		rng:    rng,
+		faults: faults,
+	}
+	p.server = httptest.NewServer(p)
+	return p, nil
+}
+
+// URL returns the url of the FaultyPAB, which can be passed to channel.NewPAB.
+func (p *FaultyPAB) URL() string {
+	return p.server.URL
+}
+
+// Close shuts down the FaultyPAB. It does not close the PAB server behind it.
+func (p *FaultyPAB) Close() {
+	p.server.CloseClientConnections()
+	p.server.Close()
+}
+
+// SetFaults sets the faults injected into subsequent requests and websocket messages.
+func (p *FaultyPAB) SetFaults(faults PABFaults) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = faults
+}
+
+// ServeHTTP implements http.Handler.
+func (p *FaultyPAB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		p.relaySubscription(w, r)
+		return
+	}
+	p.mu.Lock()
+	latency := p.latency()
+	drop := p.roll(p.faults.DropRate)
+	serverError := p.roll(p.faults.ServerErrorRate)
+	truncate := p.roll(p.faults.TruncateRate)
+	p.mu.Unlock()
+
+	time.Sleep(latency)
+	switch {
+	case drop:
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	case serverError:
+		http.Error(w, "injected fault", http.StatusServiceUnavailable)
+	case truncate:
+		recorder := httptest.NewRecorder()
+		p.proxy.ServeHTTP(recorder, r)
+		body := recorder.Body.Bytes()
+		for k, v := range recorder.Header() {
+			if k != "Content-Length" {
+				w.Header()[k] = v
+			}
+		}
+		w.WriteHeader(recorder.Code)
+		_, _ = w.Write(body[:len(body)/2])
+	default:
+		p.proxy.ServeHTTP(w, r)
+	}
+}
+
+// relaySubscription relays the websocket connection of the given request to the PAB server and injects faults into
+// the messages from the server to the client.
+func (p *FaultyPAB) relaySubscription(w http.ResponseWriter, r *http.Request) {
+	targetURL := *p.target
+	targetURL.Scheme = "ws"
+	if p.target.Scheme == "https" {
+		targetURL.Scheme = "wss"
+	}
+	targetURL.Path = r.URL.Path
+	upstream, response, err := websocket.DefaultDialer.Dial(targetURL.String(), nil)
+	if err != nil {
+		status := http.StatusBadGateway
+		if response != nil {
+			status = response.StatusCode
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	defer upstream.Close()
+	client, err := p.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	// Closing the client connection closes the upstream connection.
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				_ = upstream.Close()
+				return
+			}
+		}
+	}()
+	var held []byte
+	for {
+		messageType, message, err := upstream.ReadMessage()
+		if err != nil {
+			return
+		}
+		p.mu.Lock()
+		latency := p.latency()
+		dropSubscription := p.roll(p.faults.DropSubscriptionRate)
+		duplicate := p.roll(p.faults.DuplicateEventRate)
+		reorder := held == nil && p.roll(p.faults.ReorderEventRate)
+		p.mu.Unlock()
+
+		time.Sleep(latency)
+		if dropSubscription {
+			return
+		}
+		if reorder {
+			held = message
+			continue
+		}
+		messages := [][]byte{message}
+		if duplicate {
+			messages = append(messages, message)
+		}
+		if held != nil {
+			messages = append(messages, held)
+			held = nil
+		}
+		for _, m := range messages {
+			if err = client.WriteMessage(messageType, m); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// latency returns a random latency. p.mu must be held.
+func (p *FaultyPAB) latency() time.Duration {
+	if p.faults.MaxLatency <= 0 {
+		return 0
+	}
+	return time.Duration(p.rng.Int63n(int64(p.faults.MaxLatency) + 1))
+}
+
+// roll returns true with the given probability. p.mu must be held.
+func (p *FaultyPAB) roll(rate float64) bool {
+	return p.rng.Float64() < rate
+}
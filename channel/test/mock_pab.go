@@ -0,0 +1,615 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	gpwallet "perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wire"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the endpoints of the perun contract instances hosted by the MockPAB.
+const (
+	StartEndpoint = "start"
+	FundEndpoint  = "fund"
+	CloseEndpoint = "close"
+)
+
+// MockPAB is an in-process PAB server for testing the Funder, Adjudicator, PAB and AdjudicatorSub against. It hosts
+// perun contract instances exposing the start, fund and close endpoints and adjudicator subscription instances, which
+// stream the NewObservableState events of their channel via websocket. Subscriptions replay all past events of their
+// channel first, so they can be created at any time.
+// By default, the MockPAB behaves like an honest PAB with honest peers. The behavior of the contract endpoints can be
+// scripted (see SetEndpointFailure, SetStartBehavior and SetDepositBehavior) and peers can be simulated by acting on
+// the mocked ledger directly (see Start, Deposit, Dispute, Conclude and EmitEvent).
+// Note: The MockPAB does not verify signatures.
+// MockPAB should only be instantiated using NewMockPAB.
+type MockPAB struct {
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+	rng      *rand.Rand
+
+	mu sync.Mutex
+	// instances holds the perun contract instances by instance id.
+	instances map[string]*mockInstance
+	// subscriptions holds the channel id of every adjudicator subscription instance by instance id.
+	subscriptions map[string]types.ID
+	channels      map[types.ID]*mockChannel
+	failures      map[string]string
+	onStart       func(datum *types.ChannelDatum)
+	depositAmount func(index uint16, amount types.Balance) types.Balance
+}
+
+// mockInstance is a perun contract instance hosted by the MockPAB.
+type mockInstance struct {
+	id        string
+	requestID int
+	logs      []wire.LogMessage
+}
+
+// mockChannel is a channel on the ledger mocked by the MockPAB.
+type mockChannel struct {
+	datum     types.ChannelDatum
+	concluded bool
+	// events holds all events emitted for the channel, which are replayed to new subscriptions.
+	events []wire.Event
+	subs   []*mockSubscription
+}
+
+// mockSubscription streams the events of a channel to a websocket connection.
+type mockSubscription struct {
+	conn   *websocket.Conn
+	mu     sync.Mutex
+	queue  []wire.Event
+	notify chan struct{}
+	done   chan struct{}
+}
+
+// NewMockPAB starts a new MockPAB. It must be closed using Close.
+func NewMockPAB(rng *rand.Rand) *MockPAB {
+	m := &MockPAB{
+		rng:           rng,
+		instances:     make(map[string]*mockInstance),
+		subscriptions: make(map[string]types.ID),
+		channels:      make(map[types.ID]*mockChannel),
+		failures:      make(map[string]string),
+	}
+	m.server = httptest.NewServer(m)
+	return m
+}
+
+// URL returns the url of the MockPAB, which can be passed to channel.NewPAB.
+func (m *MockPAB) URL() string {
+	return m.server.URL
+}
+
+// Close shuts down the MockPAB and closes all subscriptions.
+func (m *MockPAB) Close() {
+	m.mu.Lock()
+	for _, c := range m.channels {
+		for _, sub := range c.subs {
+			_ = sub.conn.Close()
+		}
+	}
+	m.mu.Unlock()
+	m.server.Close()
+}
+
+// SetEndpointFailure makes all subsequent calls to the given endpoint of the perun contract instances fail with the
+// given message, which the contract instance reports through an error log message. An empty message makes the endpoint
+// behave honestly again.
+func (m *MockPAB) SetEndpointFailure(endpoint string, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if message == "" {
+		delete(m.failures, endpoint)
+		return
+	}
+	m.failures[endpoint] = message
+}
+
+// SetStartBehavior sets a function which may modify the datum of every channel started through the start endpoint or
+// Start, before the channel is created on the mocked ledger. Nil resets the honest behavior.
+func (m *MockPAB) SetStartBehavior(onStart func(datum *types.ChannelDatum)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onStart = onStart
+}
+
+// SetDepositBehavior sets a function which returns the amount actually deposited, when the party with the given index
+// funds a channel with the given amount through the fund endpoint. Nil resets the honest behavior.
+func (m *MockPAB) SetDepositBehavior(depositAmount func(index uint16, amount types.Balance) types.Balance) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.depositAmount = depositAmount
+}
+
+// Start creates the channel with the given parameters and initial state on the mocked ledger, as if it was started by
+// the first party, and returns the channel token of the channel.
+func (m *MockPAB) Start(params types.ChannelParameters, state types.ChannelState) (types.ChannelToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.start(params, state)
+}
+
+// Deposit deposits the given amount for the party with the given index into the channel with the given id.
+func (m *MockPAB) Deposit(id types.ID, index uint16, amount types.Balance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deposit(id, index, amount)
+}
+
+// Dispute registers the given state with the given signatures for the channel with the given id.
+func (m *MockPAB) Dispute(id types.ID, state types.ChannelState, sigs []gpwallet.Sig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, err := m.openChannel(id)
+	if err != nil {
+		return err
+	}
+	old := c.datum
+	c.datum.ChannelState = state
+	c.datum.Disputed = true
+	c.datum.Time = now()
+	signatures := make([]wire.Signature, len(sigs))
+	for i, sig := range sigs {
+		signatures[i] = wire.MakeSignature(sig)
+	}
+	m.emit(c, wire.Event{
+		Tag:        channel.DisputedTag,
+		DatumList:  []wire.ChannelDatum{wire.MakeChannelDatum(old), wire.MakeChannelDatum(c.datum)},
+		Signatures: signatures,
+	})
+	return nil
+}
+
+// Conclude concludes the channel with the given id in its current state.
+func (m *MockPAB) Conclude(id types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, err := m.openChannel(id)
+	if err != nil {
+		return err
+	}
+	m.conclude(c)
+	return nil
+}
+
+// EmitEvent emits the given event to all subscriptions of the channel with the given id, without changing the mocked
+// ledger. It can be used to simulate a misbehaving PAB.
+func (m *MockPAB) EmitEvent(id types.ID, event wire.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.channels[id]
+	if !ok {
+		return fmt.Errorf("unknown channel: %x", id)
+	}
+	m.emit(c, event)
+	return nil
+}
+
+// Datum returns the current datum of the channel with the given id and true, iff the channel exists and is not
+// concluded.
+func (m *MockPAB) Datum(id types.ID) (types.ChannelDatum, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.channels[id]
+	if !ok || !c.started() || c.concluded {
+		return types.ChannelDatum{}, false
+	}
+	datum := c.datum
+	datum.FundingBalances = append([]types.Balance(nil), datum.FundingBalances...)
+	datum.ChannelState.Balances = append([]types.Balance(nil), datum.ChannelState.Balances...)
+	return datum, true
+}
+
+// ServeHTTP implements http.Handler.
+func (m *MockPAB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && path == channel.ActivateEndpoint:
+		m.handleActivate(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, channel.WebSocketEndpoint+"/"):
+		m.handleSubscription(w, r, strings.TrimPrefix(path, channel.WebSocketEndpoint+"/"))
+	case strings.HasPrefix(path, channel.InstanceEndpoint+"/"):
+		parts := strings.Split(strings.TrimPrefix(path, channel.InstanceEndpoint+"/"), "/")
+		switch {
+		case r.Method == http.MethodGet && len(parts) == 2 && parts[1] == "status":
+			m.handleStatus(w, parts[0])
+		case r.Method == http.MethodPost && len(parts) == 3 && parts[1] == "endpoint":
+			m.handleEndpoint(w, r, parts[0], parts[2])
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *MockPAB) handleActivate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		CaID struct {
+			Tag       string          `json:"tag"`
+			ChannelID *wire.ChannelID `json:"contents"`
+		} `json:"caID"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.newInstanceID()
+	switch body.CaID.Tag {
+	case wire.PerunContractTag:
+		m.instances[id] = &mockInstance{id: id}
+	case wire.AdjudicatorTag:
+		if body.CaID.ChannelID == nil {
+			http.Error(w, "missing channel id", http.StatusBadRequest)
+			return
+		}
+		m.subscriptions[id] = types.ID(*body.CaID.ChannelID)
+	default:
+		http.Error(w, "unknown contract: "+body.CaID.Tag, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, wire.ContractInstanceID{ID: id})
+}
+
+func (m *MockPAB) handleStatus(w http.ResponseWriter, instanceID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	instance, ok := m.instances[instanceID]
+	if !ok {
+		http.Error(w, "unknown contract instance", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, instance.status())
+}
+
+func (m *MockPAB) handleEndpoint(w http.ResponseWriter, r *http.Request, instanceID string, endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	instance, ok := m.instances[instanceID]
+	if !ok {
+		http.Error(w, "unknown contract instance", http.StatusNotFound)
+		return
+	}
+	var err error
+	if msg, ok := m.failures[endpoint]; ok {
+		err = errors.New(msg)
+	} else {
+		switch endpoint {
+		case StartEndpoint:
+			var params wire.OpenParams
+			if err = json.NewDecoder(r.Body).Decode(&params); err == nil {
+				err = m.handleStart(params)
+			}
+		case FundEndpoint:
+			var params wire.FundParams
+			if err = json.NewDecoder(r.Body).Decode(&params); err == nil {
+				err = m.handleFund(params)
+			}
+		case CloseEndpoint:
+			var params wire.CloseParams
+			if err = json.NewDecoder(r.Body).Decode(&params); err == nil {
+				err = m.handleClose(params)
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+	}
+	if err != nil {
+		instance.log(wire.LogLevelError, err.Error())
+	} else {
+		instance.log("Info", fmt.Sprintf("submitted transaction %s", m.newTxID()))
+		// The contract instance exposes its endpoints with new request ids after handling the call.
+		instance.requestID++
+	}
+	writeJSON(w, []interface{}{})
+}
+
+func (m *MockPAB) handleStart(params wire.OpenParams) error {
+	p, err := params.ChannelParameters().Decode()
+	if err != nil {
+		return fmt.Errorf("invalid channel parameters: %w", err)
+	}
+	_, err = m.start(p, types.MakeChannelState(types.ID(params.ChannelID), params.Balances, 0, false))
+	return err
+}
+
+func (m *MockPAB) handleFund(params wire.FundParams) error {
+	id := types.ID(params.ChannelID)
+	c, err := m.openChannel(id)
+	if err != nil {
+		return err
+	}
+	if err = checkToken(params.ChannelToken, c.datum.ChannelToken); err != nil {
+		return err
+	}
+	if int(params.Index) >= len(c.datum.ChannelState.Balances) {
+		return fmt.Errorf("invalid party index: %d", params.Index)
+	}
+	amount := c.datum.ChannelState.Balances[params.Index]
+	if m.depositAmount != nil {
+		amount = m.depositAmount(params.Index, amount)
+	}
+	return m.deposit(id, params.Index, amount)
+}
+
+func (m *MockPAB) handleClose(params wire.CloseParams) error {
+	c, err := m.openChannel(types.ID(params.ChannelID))
+	if err != nil {
+		return err
+	}
+	if err = checkToken(params.ChannelToken, c.datum.ChannelToken); err != nil {
+		return err
+	}
+	if !c.datum.Funded {
+		return errors.New("channel is not funded")
+	}
+	state := params.SignedState.ChannelState.Decode()
+	if !state.Final {
+		return errors.New("state is not final")
+	}
+	if state.ID != c.datum.ChannelState.ID {
+		return errors.New("state belongs to a different channel")
+	}
+	if state.Version < c.datum.ChannelState.Version {
+		return errors.New("state is outdated")
+	}
+	m.conclude(c)
+	return nil
+}
+
+func (m *MockPAB) handleSubscription(w http.ResponseWriter, r *http.Request, instanceID string) {
+	m.mu.Lock()
+	id, ok := m.subscriptions[instanceID]
+	m.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown contract instance", http.StatusNotFound)
+		return
+	}
+	conn, err := m.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	sub := &mockSubscription{conn: conn, notify: make(chan struct{}, 1), done: make(chan struct{})}
+	m.mu.Lock()
+	c, ok := m.channels[id]
+	if !ok {
+		c = &mockChannel{}
+		m.channels[id] = c
+	}
+	c.subs = append(c.subs, sub)
+	sub.push(c.events...)
+	m.mu.Unlock()
+	go sub.receive()
+	sub.send()
+}
+
+func (m *MockPAB) start(params types.ChannelParameters, state types.ChannelState) (types.ChannelToken, error) {
+	if c, ok := m.channels[state.ID]; ok && c.started() {
+		return types.ChannelToken{}, fmt.Errorf("channel already exists: %x", state.ID)
+	}
+	if len(state.Balances) != len(params.Parties) {
+		return types.ChannelToken{}, errors.New("mismatching number of balances and parties")
+	}
+	var symbol [28]byte
+	m.rng.Read(symbol[:])
+	token := types.ChannelToken{
+		TokenSymbol: hex.EncodeToString(symbol[:]),
+		TokenName:   hex.EncodeToString(state.ID[:]),
+		TxOutRef:    types.TxOutRef{TxID: m.newTxID(), Index: m.rng.Intn(8)},
+	}
+	funding := make([]types.Balance, len(state.Balances))
+	funding[0] = state.Balances[0]
+	datum := types.ChannelDatum{
+		ChannelParameters: params,
+		ChannelToken:      token,
+		ChannelState:      state,
+		Time:              now(),
+		FundingBalances:   funding,
+	}
+	datum.Funded = datum.FundingComplete()
+	if m.onStart != nil {
+		m.onStart(&datum)
+	}
+	c, ok := m.channels[state.ID]
+	if !ok {
+		c = &mockChannel{}
+		m.channels[state.ID] = c
+	}
+	c.datum = datum
+	m.emit(c, wire.Event{Tag: channel.CreatedTag, DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(datum)}})
+	return datum.ChannelToken, nil
+}
+
+func (m *MockPAB) deposit(id types.ID, index uint16, amount types.Balance) error {
+	c, err := m.openChannel(id)
+	if err != nil {
+		return err
+	}
+	if int(index) >= len(c.datum.FundingBalances) {
+		return fmt.Errorf("invalid party index: %d", index)
+	}
+	if c.datum.Funded {
+		return errors.New("channel is already funded")
+	}
+	if c.datum.FundingBalances[index] >= c.datum.ChannelState.Balances[index] {
+		return fmt.Errorf("party %d already funded its balance", index)
+	}
+	old := c.datum
+	c.datum.FundingBalances = append([]types.Balance(nil), old.FundingBalances...)
+	c.datum.FundingBalances[index] += amount
+	c.datum.Funded = c.datum.FundingComplete()
+	c.datum.Time = now()
+	m.emit(c, wire.Event{
+		Tag:       channel.DepositedTag,
+		DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(old), wire.MakeChannelDatum(c.datum)},
+	})
+	return nil
+}
+
+func (m *MockPAB) conclude(c *mockChannel) {
+	c.concluded = true
+	m.emit(c, wire.Event{Tag: channel.ConcludedTag, DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(c.datum)}})
+}
+
+// openChannel returns the channel with the given id, iff it was started and is not concluded.
+func (m *MockPAB) openChannel(id types.ID) (*mockChannel, error) {
+	c, ok := m.channels[id]
+	if !ok || !c.started() {
+		return nil, fmt.Errorf("unknown channel: %x", id)
+	}
+	if c.concluded {
+		return nil, fmt.Errorf("channel is concluded: %x", id)
+	}
+	return c, nil
+}
+
+// emit records the given event for the given channel and pushes it to all of its subscriptions.
+func (m *MockPAB) emit(c *mockChannel, event wire.Event) {
+	c.events = append(c.events, event)
+	for _, sub := range c.subs {
+		sub.push(event)
+	}
+}
+
+func (m *MockPAB) newInstanceID() string {
+	var id [16]byte
+	m.rng.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+func (m *MockPAB) newTxID() string {
+	var id [32]byte
+	m.rng.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// started returns true, iff the channel was started, i.e., it is not only known from a subscription.
+func (c *mockChannel) started() bool {
+	return len(c.datum.FundingBalances) != 0
+}
+
+// status returns the status of the contract instance, which always exposes all of its endpoints.
+func (i *mockInstance) status() wire.ContractInstanceStatus {
+	var status wire.ContractInstanceStatus
+	status.ContractID.ID = i.id
+	status.Status = wire.ContractInstanceStatusActive
+	status.CurrentState.Logs = append([]wire.LogMessage(nil), i.logs...)
+	for _, endpoint := range []string{StartEndpoint, FundEndpoint, CloseEndpoint} {
+		var hook wire.ContractHook
+		hook.RequestID = i.requestID
+		hook.Request.Description.Endpoint = endpoint
+		status.CurrentState.Hooks = append(status.CurrentState.Hooks, hook)
+	}
+	return status
+}
+
+func (i *mockInstance) log(level string, message string) {
+	content, _ := json.Marshal(message)
+	i.logs = append(i.logs, wire.LogMessage{Level: level, Content: content})
+}
+
+// push queues the given events to be sent to the subscriber.
+func (s *mockSubscription) push(events ...wire.Event) {
+	if len(events) == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.queue = append(s.queue, events...)
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// send sends queued events to the subscriber, until the connection is closed.
+func (s *mockSubscription) send() {
+	defer s.conn.Close()
+	for {
+		select {
+		case <-s.notify:
+		case <-s.done:
+			return
+		}
+		s.mu.Lock()
+		events := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		for _, event := range events {
+			contents, err := json.Marshal([]wire.Event{event})
+			if err != nil {
+				return
+			}
+			err = s.conn.WriteJSON(wire.SubscriptionMessage{Tag: wire.EventMessageTag, Contents: contents})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// receive discards all messages of the subscriber and closes done, once the connection is closed.
+func (s *mockSubscription) receive() {
+	defer close(s.done)
+	for {
+		if _, _, err := s.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// checkToken returns an error, iff the given asset class does not identify the given channel token.
+func checkToken(assetClass wire.AssetClass, token types.ChannelToken) error {
+	actual, err := json.Marshal(assetClass)
+	if err != nil {
+		return err
+	}
+	expected, err := json.Marshal(wire.MakeAssetClass(token))
+	if err != nil {
+		return err
+	}
+	if string(actual) != string(expected) {
+		return errors.New("invalid channel token")
+	}
+	return nil
+}
+
+// now returns the current time at the millisecond precision of datums.
+func now() time.Time {
+	return time.UnixMilli(time.Now().UnixMilli())
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"math/big"
+	"math/rand"
+	"perun.network/go-perun/channel"
+	gptest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+	"perun.network/perun-cardano-backend/channel/types"
+)
+
+// NewRandomParamsAndState returns random parameters of a ledger channel with the given number of parties and a random
+// state of it, which are supported by this backend. The given options are applied last. Addresses are created with the
+// go-perun wallet test randomizer, which must be set (see wallet/test.NewRandomizer).
+func NewRandomParamsAndState(rng *rand.Rand, numParties int, opts ...gptest.RandomOpt) (*channel.Params, *channel.State) {
+	// The balances of all parties must not exceed the maximum balance in sum.
+	maxBalance := new(big.Int).Div(types.MaxBalance, big.NewInt(int64(numParties)))
+	return gptest.NewRandomParamsAndState(
+		rng,
+		gptest.WithoutApp().
+			Append(gptest.WithNumParts(numParties)).
+			Append(gptest.WithLedgerChannel(true)).
+			Append(gptest.WithVirtualChannel(false)).
+			Append(gptest.WithAssets(types.Asset)).
+			Append(gptest.WithBalancesInRange(new(big.Int), maxBalance)).
+			Append(opts...),
+	)
+}
+
+// NewSetup returns a setup for the generic go-perun channel backend tests (see gptest.GenericBackendTest) with
+// channels of the given number of parties. The account and addresses of the setup are created with the go-perun
+// wallet test randomizer, which must be set (see wallet/test.NewRandomizer).
+func NewSetup(rng *rand.Rand, numParties int) *gptest.Setup {
+	params, state := NewRandomParamsAndState(rng, numParties)
+	// State2 must differ from State in every field.
+	params2, state2 := NewRandomParamsAndState(rng, numParties, gptest.WithIsFinal(!state.IsFinal))
+	return &gptest.Setup{
+		Params:  params,
+		Params2: params2,
+		State:   state,
+		State2:  state2,
+		Account: wallettest.NewRandomAccount(rng),
+		RandomAddress: func() wallet.Address {
+			return wallettest.NewRandomAddress(rng)
+		},
+	}
+}
@@ -21,8 +21,16 @@ import (
 )
 
 func MakeRandomChannelState(rng *rand.Rand) types.ChannelState {
+	return MakeRandomNPartyChannelState(rng, 2)
+}
+
+// MakeRandomNPartyChannelState returns a random ChannelState with balances of the given number of parties.
+func MakeRandomNPartyChannelState(rng *rand.Rand, numParties int) types.ChannelState {
 	channelID := MakeRandomChannelID(rng)
-	balances := []uint64{rng.Uint64(), rng.Uint64()}
+	balances := make([]uint64, numParties)
+	for i := range balances {
+		balances[i] = rng.Uint64()
+	}
 	version := rng.Uint64()
 	final := rng.Intn(2) == 1
 	return types.ChannelState{
@@ -26,3 +26,18 @@ type ChannelDatum struct {
 	Funded            bool
 	Disputed          bool
 }
+
+// FundingComplete returns true, iff every party funded at least its initial balance. The channel validator marks a
+// channel as funded exactly then, so a channel whose other parties have a zero balance is funded on creation and
+// parties with a zero balance never deposit.
+func (d ChannelDatum) FundingComplete() bool {
+	if len(d.FundingBalances) != len(d.ChannelState.Balances) {
+		return false
+	}
+	for i, balance := range d.ChannelState.Balances {
+		if d.FundingBalances[i] < balance {
+			return false
+		}
+	}
+	return true
+}
@@ -18,18 +18,24 @@ import (
 	"fmt"
 	"math/big"
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
 	"perun.network/perun-cardano-backend/wallet/address"
 	"time"
 )
 
 // ChannelParameters is the cardano backend equivalent to go-perun's channel.Params.
 type ChannelParameters struct {
+	// Parties holds the signing key and payout address of every channel participant. The deployed contract pays out
+	// to the enterprise address of the payment key hash of every party and ignores stake credentials. Payouts go to
+	// the base address of a party carrying a stake credential only with a contract that supports the payout address
+	// extension (see wire.ChannelParameters).
 	Parties []address.Address
 	Nonce   channel.Nonce
 	Timeout time.Duration
 }
 
-// MakeChannelParameters constructs ChannelParameters from a go-perun channel.Params.
+// MakeChannelParameters constructs ChannelParameters from a go-perun channel.Params. It accepts ledger channels
+// without an app whose parties are all *address.Address of the signature scheme address.Ed25519.
 func MakeChannelParameters(params channel.Params) (ChannelParameters, error) {
 	if params.App != channel.NoApp() {
 		return ChannelParameters{}, fmt.Errorf("the backend does not support an app in parameters")
@@ -42,11 +48,11 @@ func MakeChannelParameters(params channel.Params) (ChannelParameters, error) {
 	}
 	parties := make([]address.Address, len(params.Parts))
 	for i, party := range params.Parts {
-		addr, ok := party.(*address.Address)
-		if !ok {
-			return ChannelParameters{}, fmt.Errorf("address %s is not of type address.Address", party.String())
+		addr, err := makeParty(party)
+		if err != nil {
+			return ChannelParameters{}, err
 		}
-		parties[i] = *addr
+		parties[i] = addr
 	}
 	return ChannelParameters{
 		Parties: parties,
@@ -55,6 +61,30 @@ func MakeChannelParameters(params channel.Params) (ChannelParameters, error) {
 	}, nil
 }
 
+// ValidateParty returns an error, iff the given address can not be a channel party (see MakeChannelParameters).
+func ValidateParty(party wallet.Address) error {
+	_, err := makeParty(party)
+	return err
+}
+
+func makeParty(party wallet.Address) (address.Address, error) {
+	if party == nil {
+		return address.Address{}, fmt.Errorf("party address must not be nil")
+	}
+	addr, ok := party.(*address.Address)
+	if !ok {
+		return address.Address{}, fmt.Errorf("address %s is not of type address.Address", party.String())
+	}
+	if addr.GetSignatureScheme() != address.Ed25519 {
+		return address.Address{}, fmt.Errorf(
+			"address %s uses signature scheme %s, but the channel validator only verifies Ed25519 signatures",
+			party.String(),
+			addr.GetSignatureScheme(),
+		)
+	}
+	return *addr, nil
+}
+
 func (cp ChannelParameters) Equal(other ChannelParameters) bool {
 	if cp.Timeout != other.Timeout {
 		return false
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"perun.network/perun-cardano-backend/errclass"
+)
 
 type DecodeEventError struct {
 	Tag         string
@@ -16,6 +19,11 @@ func NewDecodeEventError(tag string, expectedLen int, actualLen int) *DecodeEven
 	}
 }
 
+// Class returns errclass.Protocol.
+func (e DecodeEventError) Class() errclass.Class {
+	return errclass.Protocol
+}
+
 func (e DecodeEventError) Error() string {
 	return fmt.Sprintf("invalid amount of ChannelDatums received in %s event. Expected: %d, Actual: %d",
 		e.Tag,
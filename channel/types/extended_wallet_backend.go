@@ -8,6 +8,8 @@ import (
 type ExtendedWalletBackend interface {
 	wallet.Backend
 	VerifyChannelStateSignature(state ChannelState, sig wallet.Sig, a wallet.Address) (bool, error)
+	// VerifyChannelStateSignatures returns the result of VerifyChannelStateSignature for every given signature.
+	VerifyChannelStateSignatures(sigs []ChannelStateSignature) ([]bool, error)
 	CalculateChannelID(parameters ChannelParameters) (pchannel.ID, error)
 	ToChannelStateSigningAccount(account wallet.Account) (ChannelStateSigningAccount, error)
 }
@@ -16,3 +18,10 @@ type ChannelStateSigningAccount interface {
 	wallet.Account
 	SignChannelState(state ChannelState) (wallet.Sig, error)
 }
+
+// ChannelStateSignature is a signature on a ChannelState by the participant with the given address.
+type ChannelStateSignature struct {
+	State   ChannelState
+	Sig     wallet.Sig
+	Address wallet.Address
+}
@@ -0,0 +1,177 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command perun-cardano-wallet is a wallet server implementing the json api of the perun-cardano-wallet (see
+// wallet.PerunCardanoWallet) with Ed25519 payment signing keys held in local key files. It can be used as the wallet
+// server of a channel client or as a local stand-in for it in integration tests.
+//
+// Usage:
+//
+//	perun-cardano-wallet -keys <directory> [-addr localhost:8888] [-wallet-id <id>]
+//		[-tls-cert <file> -tls-key <file>] [-bearer-token-file <file> | -hmac-key-id <id> -hmac-secret-file <file>]
+//
+// All files with the extension .skey in the key directory are loaded as (extended) payment signing keys as written by
+// cardano-cli. Keys created through the /createAccount endpoint are written to the key directory.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/keyfile"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultAddr    = "localhost:8888"
+	hmacMaxAge     = 5 * time.Minute
+	shutdownPeriod = 10 * time.Second
+)
+
+type config struct {
+	addr            string
+	keys            string
+	walletID        string
+	tlsCert         string
+	tlsKey          string
+	bearerTokenFile string
+	hmacKeyID       string
+	hmacSecretFile  string
+}
+
+func main() {
+	var c config
+	flag.StringVar(&c.addr, "addr", defaultAddr, "address to listen on")
+	flag.StringVar(&c.keys, "keys", "", "directory holding the payment signing key files (*.skey)")
+	flag.StringVar(&c.walletID, "wallet-id", "", "cardano wallet id reported for the accounts")
+	flag.StringVar(&c.tlsCert, "tls-cert", "", "certificate file to serve https with")
+	flag.StringVar(&c.tlsKey, "tls-key", "", "private key file of the certificate")
+	flag.StringVar(&c.bearerTokenFile, "bearer-token-file", "", "file holding the bearer token requests must carry")
+	flag.StringVar(&c.hmacKeyID, "hmac-key-id", "", "key id of HMAC-SHA256 signed requests")
+	flag.StringVar(&c.hmacSecretFile, "hmac-secret-file", "", "file holding the secret of HMAC-SHA256 signed requests")
+	flag.Parse()
+
+	if err := run(c); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c config) error {
+	if c.keys == "" {
+		return errors.New("the key directory must be given with -keys")
+	}
+	if (c.tlsCert == "") != (c.tlsKey == "") {
+		return errors.New("-tls-cert and -tls-key must be given together")
+	}
+	remote, err := keyfile.LoadRemote(c.keys)
+	if err != nil {
+		return err
+	}
+	remote.SetWalletID(c.walletID)
+	for _, addr := range remote.Addresses() {
+		log.Printf("serving account %s", addr)
+	}
+	handler, err := authenticate(c, wallet.NewServer(remote))
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Addr: c.addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan error, 1)
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownPeriod)
+		defer cancel()
+		done <- server.Shutdown(ctx)
+	}()
+	log.Printf("listening on %s", c.addr)
+	if c.tlsCert != "" {
+		err = server.ListenAndServeTLS(c.tlsCert, c.tlsKey)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-done
+}
+
+// authenticate wraps the given handler, so that it only serves requests authenticated as configured.
+func authenticate(c config, handler http.Handler) (http.Handler, error) {
+	switch {
+	case c.bearerTokenFile != "" && c.hmacSecretFile != "":
+		return nil, errors.New("only one of -bearer-token-file and -hmac-secret-file may be given")
+	case c.bearerTokenFile != "":
+		token, err := readSecret(c.bearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		expected := []byte("Bearer " + string(token))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}), nil
+	case c.hmacSecretFile != "":
+		secret, err := readSecret(c.hmacSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		auth := transport.HMACAuth{KeyID: c.hmacKeyID, Secret: secret}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, wallet.DefaultMaxRequestSize))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("unable to read request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if err = auth.Verify(r, body, hmacMaxAge); err != nil {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			handler.ServeHTTP(w, r)
+		}), nil
+	default:
+		return handler, nil
+	}
+}
+
+// readSecret returns the content of the given file without surrounding whitespace.
+func readSecret(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret: %w", err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return nil, fmt.Errorf("secret file %s is empty", file)
+	}
+	return []byte(secret), nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command perun-cardano inspects channels, addresses and wire messages of the perun cardano backend.
+//
+// Usage:
+//
+//	perun-cardano address [-testnet] [-stake-key-hash <hex>] <pubkey>
+//	perun-cardano channel-id [-wallet <url>] [file]
+//	perun-cardano decode-datum [file]
+//	perun-cardano decode-event [file]
+//	perun-cardano watch -pab <url> -channel <id> [-wallet-id <id>] [-count <n>] [-bearer-token-file <file>]
+//
+// The decode commands and channel-id read json from the given file or from stdin, if no file is given.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+	"strings"
+)
+
+const usage = `usage: perun-cardano <command> [arguments]
+
+commands:
+  address       convert a public key to a mainnet or testnet address
+  channel-id    compute the channel id of json encoded channel parameters
+  decode-datum  decode a json encoded channel datum
+  decode-event  decode json encoded channel events or subscription messages
+  watch         print the events of a channel observed by a PAB
+
+Run 'perun-cardano <command> -h' for the arguments of a command.
+`
+
+// command runs a subcommand with the given arguments.
+type command func(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+
+var commands = map[string]command{
+	"address":      runAddress,
+	"channel-id":   runChannelID,
+	"decode-datum": runDecodeDatum,
+	"decode-event": runDecodeEvent,
+	"watch":        runWatch,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "perun-cardano:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run runs the subcommand named by the first argument.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return flag.ErrHelp
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+// newFlagSet returns a new flag.FlagSet for the given subcommand, which prints its usage to stderr.
+func newFlagSet(name string, arguments string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: perun-cardano %s %s\n", name, arguments)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// readInput returns the content of the file named by the only positional argument or of stdin, if there is none.
+func readInput(fs *flag.FlagSet, stdin io.Reader) ([]byte, error) {
+	switch fs.NArg() {
+	case 0:
+		return io.ReadAll(stdin)
+	case 1:
+		return os.ReadFile(fs.Arg(0))
+	default:
+		fs.Usage()
+		return nil, errors.New("too many arguments")
+	}
+}
+
+func runAddress(args []string, _ io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := newFlagSet("address", "[-testnet] [-stake-key-hash <hex>] <pubkey>", stderr)
+	testnet := fs.Bool("testnet", false, "print the testnet address instead of the mainnet address")
+	stakeKeyHash := fs.String("stake-key-hash", "", "hex encoded stake key hash of a base address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one hex encoded public key")
+	}
+	pubKey, err := hex.DecodeString(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	addr, err := address.MakeAddressFromSinglePubKey(pubKey)
+	if err != nil {
+		return err
+	}
+	if *stakeKeyHash != "" {
+		hash, err := hex.DecodeString(*stakeKeyHash)
+		if err != nil || len(hash) != address.CredentialHashLength {
+			return fmt.Errorf("stake key hash must be %d hex encoded bytes", address.CredentialHashLength)
+		}
+		var credential [address.CredentialHashLength]byte
+		copy(credential[:], hash)
+		addr.SetStakeCredential(address.MakeKeyHashCredential(credential))
+	}
+	network := address.Mainnet
+	if *testnet {
+		network = address.Testnet
+	}
+	encoded, err := addr.GetPaymentAddress(network).Encode()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, encoded)
+	return err
+}
+
+func runChannelID(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := newFlagSet("channel-id", "[-wallet <url>] [file]", stderr)
+	walletURL := fs.String("wallet", "", "wallet server to compute the channel id with instead of computing it locally")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	data, err := readInput(fs, stdin)
+	if err != nil {
+		return err
+	}
+	var request wire.ChannelParameters
+	if err = json.Unmarshal(data, &request); err != nil {
+		return fmt.Errorf("invalid channel parameters: %w", err)
+	}
+	params, err := request.Decode()
+	if err != nil {
+		return fmt.Errorf("invalid channel parameters: %w", err)
+	}
+	id := wire.CalculateChannelID(params)
+	if *walletURL != "" {
+		id, err = wallet.MakeRemoteBackend(wallet.NewPerunCardanoWallet(*walletURL)).CalculateChannelID(params)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(stdout, hex.EncodeToString(id[:]))
+	return err
+}
+
+func runDecodeDatum(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := newFlagSet("decode-datum", "[file]", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	data, err := readInput(fs, stdin)
+	if err != nil {
+		return err
+	}
+	var datum wire.ChannelDatum
+	if err = json.Unmarshal(data, &datum); err != nil {
+		return fmt.Errorf("invalid channel datum: %w", err)
+	}
+	decoded, err := datum.Decode()
+	if err != nil {
+		return fmt.Errorf("invalid channel datum: %w", err)
+	}
+	return printDatum(stdout, "", decoded)
+}
+
+func runDecodeEvent(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := newFlagSet("decode-event", "[file]", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	data, err := readInput(fs, stdin)
+	if err != nil {
+		return err
+	}
+	events, err := parseEvents(data)
+	if err != nil {
+		return err
+	}
+	for i, e := range events {
+		if len(e.DatumList) == 0 {
+			return fmt.Errorf("event %d has no datums", i)
+		}
+		id := types.ID(e.DatumList[len(e.DatumList)-1].ChannelState.ChannelID)
+		event, err := channel.DecodeEvent(e, id)
+		if err != nil {
+			return fmt.Errorf("invalid event %d: %w", i, err)
+		}
+		if err = printEvent(stdout, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseEvents parses a single wire.Event, a list of them or a wire.SubscriptionMessage holding them.
+func parseEvents(data []byte) ([]wire.Event, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var events []wire.Event
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, fmt.Errorf("invalid events: %w", err)
+		}
+		return events, nil
+	}
+	var message wire.SubscriptionMessage
+	if err := json.Unmarshal(data, &message); err == nil && len(message.Contents) > 0 {
+		if message.Tag != wire.EventMessageTag {
+			return nil, fmt.Errorf("subscription message of type %s holds no events", message.Tag)
+		}
+		return parseEvents(message.Contents)
+	}
+	var event wire.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	return []wire.Event{event}, nil
+}
+
+func runWatch(args []string, _ io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := newFlagSet("watch", "-pab <url> -channel <id> [-wallet-id <id>] [-count <n>] [-bearer-token-file <file>]", stderr)
+	pabURL := fs.String("pab", "", "url of the PAB server (e.g. http://localhost:9080)")
+	channelID := fs.String("channel", "", "hex encoded id of the channel to watch")
+	walletID := fs.String("wallet-id", "", "cardano wallet id to activate the subscription contract with")
+	count := fs.Int("count", 0, "exit after the given number of events (0 watches until the subscription ends)")
+	bearerTokenFile := fs.String("bearer-token-file", "", "file holding the bearer token of the PAB server")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pabURL == "" || *channelID == "" {
+		fs.Usage()
+		return errors.New("-pab and -channel are required")
+	}
+	var id wire.ChannelID
+	if err := id.UnmarshalJSON([]byte(`"` + *channelID + `"`)); err != nil {
+		return fmt.Errorf("invalid channel id: %w", err)
+	}
+	var opts []transport.Option
+	if *bearerTokenFile != "" {
+		token, err := os.ReadFile(*bearerTokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to read bearer token: %w", err)
+		}
+		opts = append(opts, transport.WithBearerToken(strings.TrimSpace(string(token))))
+	}
+	pab, err := channel.NewPAB(*pabURL, wallet.MakeRemoteAccount(address.Address{}, nil, *walletID), opts...)
+	if err != nil {
+		return err
+	}
+	sub, err := pab.NewInternalSubscription(types.ID(id))
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	for n := 0; *count == 0 || n < *count; n++ {
+		event := sub.Next()
+		if event == nil {
+			return sub.Err()
+		}
+		internal, ok := event.(channel.InternalEvent)
+		if !ok {
+			return fmt.Errorf("unexpected event: %T", event)
+		}
+		if err = printEvent(stdout, internal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
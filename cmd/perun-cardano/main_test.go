@@ -0,0 +1,183 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"perun.network/perun-cardano-backend/channel"
+	chtest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func makeParams(t *testing.T, numParties int) (types.ChannelParameters, types.ChannelState) {
+	rng := pkgtest.Prng(t)
+	params := types.ChannelParameters{
+		Parties: make([]address.Address, numParties),
+		Nonce:   new(big.Int).SetUint64(rng.Uint64()),
+		Timeout: time.Duration(rng.Intn(1000)+1) * time.Second,
+	}
+	for i := range params.Parties {
+		params.Parties[i] = test.MakeRandomAddress(rng)
+	}
+	state := chtest.MakeRandomNPartyChannelState(rng, numParties)
+	state.ID = wire.CalculateChannelID(params)
+	return params, state
+}
+
+func TestAddress(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	pubKey := hex.EncodeToString(addr.GetPubKeySlice())
+
+	out, err := runCommand(t, "", "address", pubKey)
+	require.NoError(t, err)
+	expected, err := addr.GetMainnetAddressOfPubKey()
+	require.NoError(t, err)
+	require.Equal(t, expected+"\n", out)
+
+	out, err = runCommand(t, "", "address", "-testnet", pubKey)
+	require.NoError(t, err)
+	expected, err = addr.GetTestnetAddressOfPubKey()
+	require.NoError(t, err)
+	require.Equal(t, expected+"\n", out)
+
+	withStake := test.MakeRandomAddressWithStakeCredential(rng)
+	stake, ok := withStake.GetStakeCredential()
+	require.True(t, ok)
+	out, err = runCommand(t, "", "address", "-stake-key-hash", hex.EncodeToString(stake.Hash[:]), hex.EncodeToString(withStake.GetPubKeySlice()))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "addr1"), "base address expected, got %s", out)
+
+	_, err = runCommand(t, "", "address", "not hex")
+	require.Error(t, err)
+}
+
+func TestChannelID(t *testing.T) {
+	params, _ := makeParams(t, 2)
+	encoded, err := json.Marshal(wire.MakeChannelParameters(params))
+	require.NoError(t, err)
+	id := wire.CalculateChannelID(params)
+
+	out, err := runCommand(t, string(encoded), "channel-id")
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(id[:])+"\n", out)
+
+	file := filepath.Join(t.TempDir(), "params.json")
+	require.NoError(t, os.WriteFile(file, encoded, 0o600))
+	out, err = runCommand(t, "", "channel-id", file)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(id[:])+"\n", out)
+
+	_, err = runCommand(t, "{}", "channel-id")
+	require.Error(t, err)
+}
+
+func TestDecodeDatum(t *testing.T) {
+	params, state := makeParams(t, 2)
+	datum := types.ChannelDatum{
+		ChannelParameters: params,
+		ChannelToken:      types.ChannelToken{TokenSymbol: "abcd", TokenName: "01", TxOutRef: types.TxOutRef{TxID: "ef", Index: 2}},
+		ChannelState:      state,
+		Time:              time.UnixMilli(1_700_000_000_000),
+		FundingBalances:   make([]types.Balance, len(state.Balances)),
+	}
+	encoded, err := json.Marshal(wire.MakeChannelDatum(datum))
+	require.NoError(t, err)
+
+	out, err := runCommand(t, string(encoded), "decode-datum")
+	require.NoError(t, err)
+	require.Contains(t, out, hex.EncodeToString(state.ID[:]))
+	require.Contains(t, out, "abcd.01 (ef#2)")
+	require.Contains(t, out, hex.EncodeToString(params.Parties[1].GetPubKeySlice()))
+	require.Contains(t, out, "2023-11-14T22:13:20Z")
+}
+
+func TestDecodeEvent(t *testing.T) {
+	params, state := makeParams(t, 2)
+	datum := types.ChannelDatum{
+		ChannelParameters: params,
+		ChannelState:      state,
+		Time:              time.UnixMilli(1_700_000_000_000),
+		FundingBalances:   make([]types.Balance, len(state.Balances)),
+	}
+	funded := datum
+	funded.FundingBalances = []types.Balance{state.Balances[0], 0}
+	events := []wire.Event{
+		{Tag: channel.CreatedTag, DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(datum)}},
+		{Tag: channel.DepositedTag, DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(datum), wire.MakeChannelDatum(funded)}},
+	}
+	encoded, err := json.Marshal(events)
+	require.NoError(t, err)
+
+	out, err := runCommand(t, string(encoded), "decode-event")
+	require.NoError(t, err)
+	require.Contains(t, out, "Created "+hex.EncodeToString(state.ID[:]))
+	require.Contains(t, out, "Deposited "+hex.EncodeToString(state.ID[:]))
+
+	message, err := json.Marshal(wire.SubscriptionMessage{Contents: encoded, Tag: wire.EventMessageTag})
+	require.NoError(t, err)
+	fromMessage, err := runCommand(t, string(message), "decode-event")
+	require.NoError(t, err)
+	require.Equal(t, out, fromMessage)
+
+	single, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	out, err = runCommand(t, string(single), "decode-event")
+	require.NoError(t, err)
+	require.Contains(t, out, "Created ")
+	require.NotContains(t, out, "Deposited ")
+}
+
+func TestWatch(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	mock := chtest.NewMockPAB(rng)
+	defer mock.Close()
+	params, state := makeParams(t, 2)
+	_, err := mock.Start(params, state)
+	require.NoError(t, err)
+	require.NoError(t, mock.Deposit(state.ID, 1, state.Balances[1]))
+
+	out, err := runCommand(t, "", "watch", "-pab", mock.URL(), "-channel", hex.EncodeToString(state.ID[:]), "-count", "2")
+	require.NoError(t, err)
+	require.Contains(t, out, "Created "+hex.EncodeToString(state.ID[:]))
+	require.Contains(t, out, "Deposited "+hex.EncodeToString(state.ID[:]))
+
+	_, err = runCommand(t, "", "watch", "-pab", mock.URL())
+	require.Error(t, err)
+}
+
+func TestUnknownCommand(t *testing.T) {
+	_, err := runCommand(t, "", "frobnicate")
+	require.Error(t, err)
+}
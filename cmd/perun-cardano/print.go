@@ -0,0 +1,87 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"text/tabwriter"
+	"time"
+)
+
+// printEvent prints the name of the given event followed by the datums it carries.
+func printEvent(w io.Writer, event channel.InternalEvent) error {
+	var err error
+	switch e := event.(type) {
+	case channel.Created:
+		_, err = fmt.Fprintf(w, "Created %x\n", e.ChannelID)
+		if err == nil {
+			err = printDatum(w, "new ", e.NewDatum)
+		}
+	case channel.Deposited:
+		_, err = fmt.Fprintf(w, "Deposited %x\n", e.ChannelID)
+		if err == nil {
+			err = printDatum(w, "old ", e.OldDatum)
+		}
+		if err == nil {
+			err = printDatum(w, "new ", e.NewDatum)
+		}
+	case channel.Disputed:
+		_, err = fmt.Fprintf(w, "Disputed %x (%d signatures)\n", e.ChannelID, len(e.Signatures))
+		if err == nil {
+			err = printDatum(w, "old ", e.OldDatum)
+		}
+		if err == nil {
+			err = printDatum(w, "new ", e.NewDatum)
+		}
+	case channel.Concluded:
+		_, err = fmt.Fprintf(w, "Concluded %x\n", e.ChannelID)
+		if err == nil {
+			err = printDatum(w, "old ", e.OldDatum)
+		}
+	default:
+		_, err = fmt.Fprintf(w, "%T %x\n", event, event.ID())
+	}
+	return err
+}
+
+// printDatum prints the fields of the given datum as a table, each key prefixed with the given prefix.
+func printDatum(w io.Writer, prefix string, datum types.ChannelDatum) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	row := func(key string, format string, args ...interface{}) {
+		fmt.Fprintf(tw, "%s%s:\t%s\n", prefix, key, fmt.Sprintf(format, args...))
+	}
+	params := datum.ChannelParameters
+	token := datum.ChannelToken
+	state := datum.ChannelState
+	row("channel id", "%x", state.ID)
+	row("token", "%s.%s (%s#%d)", token.TokenSymbol, token.TokenName, token.TxOutRef.TxID, token.TxOutRef.Index)
+	for i, party := range params.Parties {
+		row(fmt.Sprintf("party %d", i), "%s", hex.EncodeToString(party.GetPubKeySlice()))
+	}
+	row("nonce", "%s", params.Nonce)
+	row("timeout", "%s", params.Timeout)
+	row("version", "%d", state.Version)
+	row("final", "%t", state.Final)
+	row("balances", "%v", state.Balances)
+	row("funding", "%v", datum.FundingBalances)
+	row("funded", "%t", datum.Funded)
+	row("disputed", "%t", datum.Disputed)
+	row("time", "%s", datum.Time.UTC().Format(time.RFC3339))
+	return tw.Flush()
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSlotLength is the slot length of the Cardano mainnet.
+const DefaultSlotLength = time.Second
+
+// SlotClock is the simulated slot clock of the emulated ledger. It only advances when told to, so tests can run into
+// timeouts without waiting for them.
+type SlotClock struct {
+	mu         sync.Mutex
+	genesis    time.Time
+	slotLength time.Duration
+	slot       uint64
+}
+
+// NewSlotClock returns a new SlotClock at slot 0, which starts at the given genesis time. The genesis time is truncated
+// to the millisecond precision of on-chain times.
+func NewSlotClock(genesis time.Time, slotLength time.Duration) *SlotClock {
+	return &SlotClock{
+		genesis:    time.UnixMilli(genesis.UnixMilli()),
+		slotLength: slotLength,
+	}
+}
+
+// Slot returns the current slot.
+func (c *SlotClock) Slot() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.slot
+}
+
+// Now returns the start time of the current slot.
+func (c *SlotClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.genesis.Add(time.Duration(c.slot) * c.slotLength)
+}
+
+// AdvanceSlots advances the clock by the given number of slots.
+func (c *SlotClock) AdvanceSlots(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slot += n
+}
+
+// AdvanceTime advances the clock by the smallest number of slots that covers the given duration.
+func (c *SlotClock) AdvanceTime(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.AdvanceSlots(uint64((d + c.slotLength - 1) / c.slotLength))
+}
@@ -0,0 +1,468 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emulator provides a simulated Cardano ledger behind a PAB-compatible HTTP and websocket API. The emulator
+// keeps a UTxO set, wallet balances and a simulated slot clock and applies the rules of the Perun channel validator
+// for opening, funding, disputing, closing, force-closing and aborting channels. channel.PAB clients can connect to it
+// like to a real PAB, which allows running go-perun clients end-to-end without a Cardano node.
+package emulator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/http/httptest"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the endpoints of the perun contract instances hosted by the Emulator.
+const (
+	StartEndpoint      = "start"
+	FundEndpoint       = "fund"
+	DisputeEndpoint    = "dispute"
+	CloseEndpoint      = "close"
+	ForceCloseEndpoint = "forceClose"
+	AbortEndpoint      = "abort"
+)
+
+var contractEndpoints = []string{
+	StartEndpoint, FundEndpoint, DisputeEndpoint, CloseEndpoint, ForceCloseEndpoint, AbortEndpoint,
+}
+
+// Emulator is an emulated Cardano ledger served through a PAB-compatible API. Perun contract instances act on behalf
+// of the wallet they were activated with (see AddWallet). Adjudicator subscriptions stream the NewObservableState
+// events of their channel and replay all past events first.
+// Emulator should only be instantiated using NewEmulator.
+type Emulator struct {
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+	clock    *SlotClock
+
+	mu     sync.Mutex
+	ledger *ledger
+	// instances holds the perun contract instances by instance id.
+	instances map[string]*contractInstance
+	// subscriptions holds the channel id of every adjudicator subscription instance by instance id.
+	subscriptions map[string]types.ID
+	// events holds all events emitted for every channel.
+	events       map[types.ID][]wire.Event
+	subscribers  map[types.ID][]*subscriber
+	nextInstance uint64
+	backend      types.ExtendedWalletBackend
+	genesis      time.Time
+	slotLength   time.Duration
+	anyOrder     bool
+}
+
+// Option configures an Emulator.
+type Option func(*Emulator)
+
+// WithBackend makes the emulator verify channel ids and channel state signatures with the given backend. Without a
+// backend, the emulator accepts any channel id and any signatures.
+func WithBackend(backend types.ExtendedWalletBackend) Option {
+	return func(e *Emulator) {
+		e.backend = backend
+	}
+}
+
+// WithSlotClock sets the genesis time and slot length of the slot clock. The default is the current time and
+// DefaultSlotLength.
+func WithSlotClock(genesis time.Time, slotLength time.Duration) Option {
+	return func(e *Emulator) {
+		e.genesis = genesis
+		e.slotLength = slotLength
+	}
+}
+
+// WithFundingInAnyOrder makes the emulator accept the deposits of the parties of a channel in any order, like a
+// contract that supports channel.ParallelFunding (see channel.PAB.SetDepositsInAnyOrder). By default, parties must
+// deposit in the order of their index, like with the deployed channel validator.
+func WithFundingInAnyOrder() Option {
+	return func(e *Emulator) {
+		e.anyOrder = true
+	}
+}
+
+// contractInstance is a perun contract instance hosted by the Emulator.
+type contractInstance struct {
+	id        string
+	walletID  string
+	requestID int
+	logs      []wire.LogMessage
+}
+
+// subscriber streams the events of a channel to a websocket connection.
+type subscriber struct {
+	conn   *websocket.Conn
+	mu     sync.Mutex
+	queue  []wire.Event
+	notify chan struct{}
+	done   chan struct{}
+}
+
+// NewEmulator starts a new Emulator with an empty ledger. It must be closed using Close.
+func NewEmulator(opts ...Option) *Emulator {
+	e := &Emulator{
+		instances:     make(map[string]*contractInstance),
+		subscriptions: make(map[string]types.ID),
+		events:        make(map[types.ID][]wire.Event),
+		subscribers:   make(map[types.ID][]*subscriber),
+		genesis:       time.Now(),
+		slotLength:    DefaultSlotLength,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.clock = NewSlotClock(e.genesis, e.slotLength)
+	e.ledger = newLedger(e.clock, e.backend, e.anyOrder)
+	e.server = httptest.NewServer(e)
+	return e
+}
+
+// URL returns the url of the Emulator, which can be passed to channel.NewPAB.
+func (e *Emulator) URL() string {
+	return e.server.URL
+}
+
+// Close shuts down the Emulator and closes all subscriptions.
+func (e *Emulator) Close() {
+	e.mu.Lock()
+	for _, subs := range e.subscribers {
+		for _, sub := range subs {
+			_ = sub.conn.Close()
+		}
+	}
+	e.mu.Unlock()
+	e.server.Close()
+}
+
+// Clock returns the slot clock of the emulated ledger.
+func (e *Emulator) Clock() *SlotClock {
+	return e.clock
+}
+
+// AddWallet registers the wallet with the given id, which pays from the outputs owned by the payment credential of the
+// given address and receives change at its payout address, and pays the given funds to it.
+func (e *Emulator) AddWallet(walletID string, addr address.Address, funds uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ledger.addWallet(walletID, addr, funds)
+}
+
+// Balance returns the value of all outputs paying to the payout address (payment and stake credential) of the given
+// address.
+func (e *Emulator) Balance(addr address.Address) uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.ledger.balance(addr)
+}
+
+// Channel returns the current datum of the open channel with the given id, the value locked in the channel and true,
+// iff the channel is open.
+func (e *Emulator) Channel(id types.ID) (types.ChannelDatum, uint64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.ledger.datum(id)
+}
+
+// ServeHTTP implements http.Handler.
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && path == channel.ActivateEndpoint:
+		e.handleActivate(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, channel.WebSocketEndpoint+"/"):
+		e.handleSubscription(w, r, strings.TrimPrefix(path, channel.WebSocketEndpoint+"/"))
+	case strings.HasPrefix(path, channel.InstanceEndpoint+"/"):
+		parts := strings.Split(strings.TrimPrefix(path, channel.InstanceEndpoint+"/"), "/")
+		switch {
+		case r.Method == http.MethodGet && len(parts) == 2 && parts[1] == "status":
+			e.handleStatus(w, parts[0])
+		case r.Method == http.MethodPost && len(parts) == 3 && parts[1] == "endpoint":
+			e.handleEndpoint(w, r, parts[0], parts[2])
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (e *Emulator) handleActivate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		CaID struct {
+			Tag       string          `json:"tag"`
+			ChannelID *wire.ChannelID `json:"contents"`
+		} `json:"caID"`
+		Wallet wire.ContractActivationWallet `json:"caWallet"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := fmt.Sprintf("%08x-emulator", e.nextInstance)
+	switch body.CaID.Tag {
+	case wire.PerunContractTag:
+		e.instances[id] = &contractInstance{id: id, walletID: body.Wallet.WalletID}
+	case wire.AdjudicatorTag:
+		if body.CaID.ChannelID == nil {
+			http.Error(w, "missing channel id", http.StatusBadRequest)
+			return
+		}
+		e.subscriptions[id] = types.ID(*body.CaID.ChannelID)
+	default:
+		http.Error(w, "unknown contract: "+body.CaID.Tag, http.StatusBadRequest)
+		return
+	}
+	e.nextInstance++
+	writeJSON(w, wire.ContractInstanceID{ID: id})
+}
+
+func (e *Emulator) handleStatus(w http.ResponseWriter, instanceID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	instance, ok := e.instances[instanceID]
+	if !ok {
+		http.Error(w, "unknown contract instance", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, instance.status())
+}
+
+func (e *Emulator) handleEndpoint(w http.ResponseWriter, r *http.Request, instanceID string, endpoint string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	instance, ok := e.instances[instanceID]
+	if !ok {
+		http.Error(w, "unknown contract instance", http.StatusNotFound)
+		return
+	}
+	var id wire.ChannelID
+	var event wire.Event
+	var err error
+	decoder := json.NewDecoder(r.Body)
+	switch endpoint {
+	case StartEndpoint:
+		var params wire.OpenParams
+		if err = decoder.Decode(&params); err == nil {
+			id = params.ChannelID
+			event, err = e.start(instance, params)
+		}
+	case FundEndpoint:
+		var params wire.FundParams
+		if err = decoder.Decode(&params); err == nil {
+			id = params.ChannelID
+			event, err = e.ledger.fund(instance.walletID, types.ID(id), params.ChannelToken, int(params.Index))
+		}
+	case DisputeEndpoint:
+		var params wire.DisputeParams
+		if err = decoder.Decode(&params); err == nil {
+			id = params.ChannelID
+			var sigs []wallet.Sig
+			if sigs, err = decodeSignatures(params.SignedState.Signatures); err == nil {
+				state := params.SignedState.ChannelState.Decode()
+				event, err = e.ledger.dispute(types.ID(id), params.ChannelToken, state, sigs)
+			}
+		}
+	case CloseEndpoint:
+		var params wire.CloseParams
+		if err = decoder.Decode(&params); err == nil {
+			id = params.ChannelID
+			var sigs []wallet.Sig
+			if sigs, err = decodeSignatures(params.SignedState.Signatures); err == nil {
+				state := params.SignedState.ChannelState.Decode()
+				event, err = e.ledger.close(types.ID(id), params.ChannelToken, state, sigs)
+			}
+		}
+	case ForceCloseEndpoint:
+		var params wire.ForceCloseParams
+		if err = decoder.Decode(&params); err == nil {
+			id = params.ChannelID
+			event, err = e.ledger.forceClose(types.ID(id), params.ChannelToken)
+		}
+	case AbortEndpoint:
+		var params wire.AbortParams
+		if err = decoder.Decode(&params); err == nil {
+			id = params.ChannelID
+			event, err = e.ledger.abort(instance.walletID, types.ID(id), params.ChannelToken)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		instance.log(wire.LogLevelError, err.Error())
+	} else {
+		e.emit(types.ID(id), event)
+		instance.log("Info", fmt.Sprintf("%s: submitted transaction", endpoint))
+		// The contract instance exposes its endpoints with new request ids after handling the call.
+		instance.requestID++
+	}
+	writeJSON(w, []interface{}{})
+}
+
+func (e *Emulator) start(instance *contractInstance, params wire.OpenParams) (wire.Event, error) {
+	p, err := params.ChannelParameters().Decode()
+	if err != nil {
+		return wire.Event{}, fmt.Errorf("invalid channel parameters: %w", err)
+	}
+	state := types.MakeChannelState(types.ID(params.ChannelID), params.Balances, 0, false)
+	return e.ledger.open(instance.walletID, p, state)
+}
+
+func (e *Emulator) handleSubscription(w http.ResponseWriter, r *http.Request, instanceID string) {
+	e.mu.Lock()
+	id, ok := e.subscriptions[instanceID]
+	e.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown contract instance", http.StatusNotFound)
+		return
+	}
+	conn, err := e.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	sub := &subscriber{conn: conn, notify: make(chan struct{}, 1), done: make(chan struct{})}
+	e.mu.Lock()
+	e.subscribers[id] = append(e.subscribers[id], sub)
+	sub.push(e.events[id]...)
+	e.mu.Unlock()
+	go sub.receive()
+	sub.send()
+	e.mu.Lock()
+	e.removeSubscriber(id, sub)
+	e.mu.Unlock()
+}
+
+// removeSubscriber removes the given subscriber of the channel with the given id. The caller must hold e.mu.
+func (e *Emulator) removeSubscriber(id types.ID, sub *subscriber) {
+	subs := e.subscribers[id]
+	for i := range subs {
+		if subs[i] == sub {
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(e.subscribers, id)
+		return
+	}
+	e.subscribers[id] = subs
+}
+
+// emit records the given event for the channel with the given id and pushes it to all of its subscribers.
+func (e *Emulator) emit(id types.ID, event wire.Event) {
+	e.events[id] = append(e.events[id], event)
+	for _, sub := range e.subscribers[id] {
+		sub.push(event)
+	}
+}
+
+// status returns the status of the contract instance, which always exposes all of its endpoints.
+func (i *contractInstance) status() wire.ContractInstanceStatus {
+	var status wire.ContractInstanceStatus
+	status.ContractID.ID = i.id
+	status.Status = wire.ContractInstanceStatusActive
+	status.CurrentState.Logs = append([]wire.LogMessage(nil), i.logs...)
+	for _, endpoint := range contractEndpoints {
+		var hook wire.ContractHook
+		hook.RequestID = i.requestID
+		hook.Request.Description.Endpoint = endpoint
+		status.CurrentState.Hooks = append(status.CurrentState.Hooks, hook)
+	}
+	return status
+}
+
+func (i *contractInstance) log(level string, message string) {
+	content, _ := json.Marshal(message)
+	i.logs = append(i.logs, wire.LogMessage{Level: level, Content: content})
+}
+
+// push queues the given events to be sent to the subscriber.
+func (s *subscriber) push(events ...wire.Event) {
+	if len(events) == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.queue = append(s.queue, events...)
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// send sends queued events to the subscriber, until the connection is closed.
+func (s *subscriber) send() {
+	defer s.conn.Close()
+	for {
+		select {
+		case <-s.notify:
+		case <-s.done:
+			return
+		}
+		s.mu.Lock()
+		events := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		for _, event := range events {
+			contents, err := json.Marshal([]wire.Event{event})
+			if err != nil {
+				return
+			}
+			err = s.conn.WriteJSON(wire.SubscriptionMessage{Tag: wire.EventMessageTag, Contents: contents})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// receive discards all messages of the subscriber and closes done, once the connection is closed.
+func (s *subscriber) receive() {
+	defer close(s.done)
+	for {
+		if _, _, err := s.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func decodeSignatures(signatures []wire.Signature) ([]wallet.Sig, error) {
+	sigs := make([]wallet.Sig, len(signatures))
+	for i, s := range signatures {
+		sig, err := hex.DecodeString(s.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode signature %d: %w", i, err)
+		}
+		sigs[i] = sig
+	}
+	return sigs, nil
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,353 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"math/rand"
+	"net/http"
+	gpchannel "perun.network/go-perun/channel"
+	gpwallet "perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/emulator"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	initialFunds      = 100_000_000
+	challengeDuration = 60 // in seconds
+)
+
+var (
+	testRemote  *test.GenericRemote
+	testBackend wallet.RemoteBackend
+)
+
+type main struct{}
+
+func (main) Name() string {
+	return "TestMain"
+}
+
+func TestMain(m *testing.M) {
+	rng := pkgtest.Prng(main{})
+	testRemote = test.NewGenericRemote(
+		[]address.Address{test.MakeRandomAddress(rng), test.MakeRandomAddress(rng), test.MakeRandomAddress(rng)},
+		rng,
+	)
+	testBackend = wallet.MakeRemoteBackend(testRemote)
+	gpwallet.SetBackend(testBackend)
+	gpchannel.SetBackend(channel.NewBackend(testBackend))
+
+	m.Run()
+}
+
+// party is a channel participant using the emulator through its own PAB client.
+type party struct {
+	acc wallet.RemoteAccount
+	pab *channel.PAB
+}
+
+// setupEmulator starts an emulator with two funded parties.
+func setupEmulator(t *testing.T) (*emulator.Emulator, []party) {
+	return setupNPartyEmulator(t, 2)
+}
+
+// setupNPartyEmulator starts an emulator with the given options and the given number of funded parties (at most three).
+func setupNPartyEmulator(t *testing.T, numParties int, opts ...emulator.Option) (*emulator.Emulator, []party) {
+	emu := emulator.NewEmulator(append([]emulator.Option{emulator.WithBackend(testBackend)}, opts...)...)
+	t.Cleanup(emu.Close)
+	parties := make([]party, numParties)
+	for i, addr := range testRemote.AvailableAddresses[:numParties] {
+		walletID := fmt.Sprintf("wallet-%d", i)
+		emu.AddWallet(walletID, addr, initialFunds)
+		acc := wallet.MakeRemoteAccount(addr, testRemote, walletID)
+		pab, err := channel.NewPAB(emu.URL(), acc)
+		require.NoError(t, err)
+		pab.SetStatusTracking(time.Millisecond, 5*time.Second)
+		parties[i] = party{acc: acc, pab: pab}
+	}
+	return emu, parties
+}
+
+func makeParamsAndState(rng *rand.Rand, parties []party) (*gpchannel.Params, *gpchannel.State) {
+	parts := make([]gpwallet.Address, len(parties))
+	alloc := gpchannel.NewAllocation(len(parties), types.Asset)
+	for i, p := range parties {
+		parts[i] = p.acc.Address()
+		alloc.SetBalance(gpchannel.Index(i), types.Asset, big.NewInt(int64(rng.Intn(initialFunds/2)+1)))
+	}
+	params := gpchannel.NewParamsUnsafe(
+		challengeDuration, parts, gpchannel.NoApp(), new(big.Int).SetUint64(rng.Uint64()), true, false,
+	)
+	state := &gpchannel.State{
+		ID:         params.ID(),
+		App:        gpchannel.NoApp(),
+		Allocation: *alloc,
+		Data:       gpchannel.NoData(),
+	}
+	return params, state
+}
+
+// fund funds the channel with all parties concurrently in sequential funding mode.
+func fund(t *testing.T, parties []party, params *gpchannel.Params, state *gpchannel.State) []error {
+	return fundWithMode(t, parties, params, state, channel.SequentialFunding)
+}
+
+// fundWithMode funds the channel with all parties concurrently in the given funding mode.
+func fundWithMode(
+	t *testing.T,
+	parties []party,
+	params *gpchannel.Params,
+	state *gpchannel.State,
+	mode channel.FundingMode,
+) []error {
+	errs := make([]error, len(parties))
+	var wg sync.WaitGroup
+	for i, p := range parties {
+		funder := channel.NewFunder(p.pab)
+		funder.SetChainIndexDelay(0)
+		require.NoError(t, funder.SetFundingMode(mode))
+		wg.Add(1)
+		go func(i int, funder *channel.Funder) {
+			defer wg.Done()
+			req := gpchannel.FundingReq{Params: params, State: state, Idx: gpchannel.Index(i)}
+			errs[i] = funder.Fund(context.Background(), req)
+		}(i, funder)
+	}
+	wg.Wait()
+	return errs
+}
+
+// signState sets the version and final flag of the given state and returns it as cardano channel state together with
+// the signatures of all parties.
+func signState(t *testing.T, parties []party, state *gpchannel.State, version uint64, final bool) (types.ChannelState, []gpwallet.Sig) {
+	state.Version = version
+	state.IsFinal = final
+	s, err := types.ConvertChannelState(*state)
+	require.NoError(t, err)
+	sigs := make([]gpwallet.Sig, len(parties))
+	for i, p := range parties {
+		sigs[i], err = p.acc.SignChannelState(s)
+		require.NoError(t, err)
+	}
+	return s, sigs
+}
+
+// callEndpoint calls the given endpoint of the perun contract instance of the given party directly.
+func callEndpoint(t *testing.T, emu *emulator.Emulator, p party, format string, body interface{}) {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	url := emu.URL() + fmt.Sprintf(format, p.pab.GetContractInstanceID())
+	response, err := http.Post(url, "application/json", bytes.NewReader(data))
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	require.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestEmulator_ParallelFundingRequiresAnyOrder(t *testing.T) {
+	_, parties := setupEmulator(t)
+	funder := channel.NewFunder(parties[0].pab)
+	require.Error(t, funder.SetFundingMode(channel.ParallelFunding),
+		"parallel funding was enabled for a contract that requires deposits in index order")
+	require.NoError(t, funder.SetFundingMode(channel.SequentialFunding))
+}
+
+func TestEmulator_ParallelFundingInAnyOrder(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupNPartyEmulator(t, 3, emulator.WithFundingInAnyOrder())
+	for _, p := range parties {
+		p.pab.SetDepositsInAnyOrder(true)
+	}
+	params, state := makeParamsAndState(rng, parties)
+
+	for _, err := range fundWithMode(t, parties, params, state, channel.ParallelFunding) {
+		require.NoError(t, err)
+	}
+	datum, value, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+	require.Equal(t, state.Allocation.Sum()[0].Uint64(), value)
+}
+
+func TestEmulator_FundingWithZeroBalance(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupNPartyEmulator(t, 3)
+	params, state := makeParamsAndState(rng, parties)
+	state.Allocation.SetBalance(1, types.Asset, big.NewInt(0))
+
+	// Party 1 never deposits, so party 2 deposits right after the channel was started.
+	for _, err := range fund(t, parties, params, state) {
+		require.NoError(t, err)
+	}
+	datum, value, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+	require.Equal(t, state.Allocation.Sum()[0].Uint64(), value)
+}
+
+func TestEmulator_FundAndClose(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupEmulator(t)
+	params, state := makeParamsAndState(rng, parties)
+
+	for _, err := range fund(t, parties, params, state) {
+		require.NoError(t, err)
+	}
+	datum, value, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Funded)
+	require.Equal(t, state.Allocation.Sum()[0].Uint64(), value)
+
+	// The parties swap their balances and close the channel.
+	final := state.Clone()
+	final.Allocation.Balances[0][0], final.Allocation.Balances[0][1] = state.Balance(1, types.Asset), state.Balance(0, types.Asset)
+	s, sigs := signState(t, parties, final, 1, true)
+	sub, err := channel.NewAdjudicator(parties[0].pab).Subscribe(context.Background(), params.ID())
+	require.NoError(t, err)
+	defer sub.Close()
+	req := gpchannel.AdjudicatorReq{Params: params, Tx: gpchannel.Transaction{State: final, Sigs: sigs}}
+	require.NoError(t, channel.NewAdjudicator(parties[0].pab).Withdraw(context.Background(), req, nil))
+
+	_, ok = sub.Next().(*gpchannel.ConcludedEvent)
+	require.True(t, ok)
+	_, _, ok = emu.Channel(params.ID())
+	require.False(t, ok)
+	for i := range parties {
+		balance := initialFunds - state.Balance(gpchannel.Index(i), types.Asset).Uint64() + s.Balances[i]
+		require.Equal(t, balance, emu.Balance(testRemote.AvailableAddresses[i]), "party %d", i)
+	}
+}
+
+func TestEmulator_CloseInvalidSignature(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupEmulator(t)
+	params, state := makeParamsAndState(rng, parties)
+	for _, err := range fund(t, parties, params, state) {
+		require.NoError(t, err)
+	}
+
+	final := state.Clone()
+	_, sigs := signState(t, parties, final, 1, true)
+	// The signatures are valid for version 1, but not for version 2.
+	final.Version = 2
+	req := gpchannel.AdjudicatorReq{Params: params, Tx: gpchannel.Transaction{State: final, Sigs: sigs}}
+	err := channel.NewAdjudicator(parties[0].pab).Withdraw(context.Background(), req, nil)
+	var contractErr *channel.ContractError
+	require.True(t, errors.As(err, &contractErr), "unexpected error: %v", err)
+	require.Contains(t, contractErr.Message, "invalid signature")
+	_, _, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+}
+
+func TestEmulator_DisputeAndForceClose(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupEmulator(t)
+	params, state := makeParamsAndState(rng, parties)
+	for _, err := range fund(t, parties, params, state) {
+		require.NoError(t, err)
+	}
+	p, err := types.MakeChannelParameters(*params)
+	require.NoError(t, err)
+	token, err := parties[0].pab.GetChannelToken(params.ID())
+	require.NoError(t, err)
+	forceClose := wire.MakeForceCloseParams(params.ID(), token)
+
+	s, sigs := signState(t, parties, state.Clone(), 1, false)
+	callEndpoint(t, emu, parties[0], channel.DisputeEndpointFormat, wire.MakeDisputeParams(params.ID(), token, p, s, sigs))
+	datum, _, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+	require.True(t, datum.Disputed)
+	require.Equal(t, uint64(1), datum.ChannelState.Version)
+
+	// Force-closing fails before the challenge duration expired.
+	emu.Clock().AdvanceTime(p.Timeout - time.Second)
+	callEndpoint(t, emu, parties[0], channel.ForceCloseEndpointFormat, forceClose)
+	_, _, ok = emu.Channel(params.ID())
+	require.True(t, ok)
+
+	// An outdated state can not be registered.
+	old, oldSigs := signState(t, parties, state.Clone(), 0, false)
+	callEndpoint(t, emu, parties[1], channel.DisputeEndpointFormat, wire.MakeDisputeParams(params.ID(), token, p, old, oldSigs))
+	datum, _, _ = emu.Channel(params.ID())
+	require.Equal(t, uint64(1), datum.ChannelState.Version)
+
+	emu.Clock().AdvanceTime(time.Second)
+	callEndpoint(t, emu, parties[0], channel.ForceCloseEndpointFormat, forceClose)
+	_, _, ok = emu.Channel(params.ID())
+	require.False(t, ok)
+	for i := range parties {
+		require.Equal(t, uint64(initialFunds), emu.Balance(testRemote.AvailableAddresses[i]), "party %d", i)
+	}
+}
+
+func TestEmulator_Abort(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupEmulator(t)
+	params, state := makeParamsAndState(rng, parties)
+	p, s := convert(t, params, state)
+
+	require.NoError(t, parties[0].pab.Start(params.ID(), p, s))
+	datum, value, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+	require.False(t, datum.Funded)
+	require.Equal(t, s.Balances[0], value)
+	require.Equal(t, initialFunds-s.Balances[0], emu.Balance(testRemote.AvailableAddresses[0]))
+
+	callEndpoint(t, emu, parties[0], channel.AbortEndpointFormat, wire.MakeAbortParams(params.ID(), datum.ChannelToken))
+	_, _, ok = emu.Channel(params.ID())
+	require.False(t, ok)
+	require.Equal(t, uint64(initialFunds), emu.Balance(testRemote.AvailableAddresses[0]))
+}
+
+func TestEmulator_InsufficientFunds(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	emu, parties := setupEmulator(t)
+	params, state := makeParamsAndState(rng, parties)
+	state.Allocation.SetBalance(1, types.Asset, big.NewInt(initialFunds+1))
+	p, s := convert(t, params, state)
+	require.NoError(t, parties[0].pab.Start(params.ID(), p, s))
+	datum, _, ok := emu.Channel(params.ID())
+	require.True(t, ok)
+	require.NoError(t, parties[1].pab.SetChannelToken(params.ID(), datum.ChannelToken))
+
+	err := parties[1].pab.Fund(params.ID(), 1)
+	var contractErr *channel.ContractError
+	require.True(t, errors.As(err, &contractErr), "unexpected error: %v", err)
+	require.Contains(t, contractErr.Message, "insufficient funds")
+	require.Equal(t, uint64(initialFunds), emu.Balance(testRemote.AvailableAddresses[1]))
+}
+
+func convert(t *testing.T, params *gpchannel.Params, state *gpchannel.State) (types.ChannelParameters, types.ChannelState) {
+	p, err := types.MakeChannelParameters(*params)
+	require.NoError(t, err)
+	s, err := types.ConvertChannelState(*state)
+	require.NoError(t, err)
+	return p, s
+}
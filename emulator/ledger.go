@@ -0,0 +1,394 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/blake2b224"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+	"sort"
+)
+
+// ChannelScriptCredential is the payment credential of the channel validator, which locks the funds of all channels.
+var ChannelScriptCredential = address.MakeScriptHashCredential(mustHash("perun-cardano-channel"))
+
+// Output is an unspent transaction output of the emulated ledger.
+type Output struct {
+	Owner address.Credential
+	// Stake is the stake credential of the address the output is paid to, if it has one.
+	Stake *address.Credential
+	Value uint64
+	// Datum is the datum of channel outputs, which are owned by ChannelScriptCredential and carry the channel token.
+	Datum *types.ChannelDatum
+}
+
+// InsufficientFundsError is returned, if a wallet can not pay for a transaction.
+type InsufficientFundsError struct {
+	WalletID  string
+	Required  uint64
+	Available uint64
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient funds in wallet %s: required: %d, available: %d",
+		e.WalletID, e.Required, e.Available)
+}
+
+// ledger is the UTxO set of the emulator. Every channel operation is applied as a single transaction, which consumes
+// the current channel output (if any) and wallet outputs and creates the new channel output, payouts and change.
+// Transactions have no fees. ledger is not safe for concurrent use.
+type ledger struct {
+	clock   *SlotClock
+	backend types.ExtendedWalletBackend
+	utxos   map[types.TxOutRef]Output
+	wallets map[string]address.Address
+	// channels holds the reference of the current output of every open channel.
+	channels map[types.ID]types.TxOutRef
+	txCount  uint64
+	// anyOrder lets the parties of a channel deposit in any order instead of the order of their index.
+	anyOrder bool
+}
+
+func newLedger(clock *SlotClock, backend types.ExtendedWalletBackend, anyOrder bool) *ledger {
+	return &ledger{
+		clock:    clock,
+		backend:  backend,
+		anyOrder: anyOrder,
+		utxos:    make(map[types.TxOutRef]Output),
+		wallets:  make(map[string]address.Address),
+		channels: make(map[types.ID]types.TxOutRef),
+	}
+}
+
+// transaction is a transaction of the emulated ledger.
+type transaction struct {
+	inputs  []types.TxOutRef
+	outputs []Output
+}
+
+// addWallet registers the wallet with the given id for the given address and pays the given funds to it.
+func (l *ledger) addWallet(walletID string, addr address.Address, funds uint64) {
+	l.wallets[walletID] = addr
+	if funds > 0 {
+		l.apply(transaction{outputs: []Output{makeOutput(addr, funds)}})
+	}
+}
+
+// balance returns the value of all outputs paid to the payout address (payment and stake credential) of the given
+// address.
+func (l *ledger) balance(addr address.Address) uint64 {
+	var total uint64
+	for _, o := range l.utxos {
+		if isPaidTo(o, addr) {
+			total += o.Value
+		}
+	}
+	return total
+}
+
+// open opens the channel with the given parameters and initial state. The wallet deposits the balance of the first
+// party and mints the channel token, whose currency symbol is derived from the first consumed wallet output.
+func (l *ledger) open(walletID string, params types.ChannelParameters, state types.ChannelState) (wire.Event, error) {
+	if _, ok := l.channels[state.ID]; ok {
+		return wire.Event{}, reject(RedeemerOpen, "channel already exists")
+	}
+	if err := validateOpen(params, state, l.backend); err != nil {
+		return wire.Event{}, err
+	}
+	tx, change, err := l.pay(walletID, state.Balances[0])
+	if err != nil {
+		return wire.Event{}, err
+	}
+	seed := tx.inputs[0]
+	symbol, err := blake2b224.Sum224([]byte(fmt.Sprintf("%s#%d", seed.TxID, seed.Index)))
+	if err != nil {
+		return wire.Event{}, err
+	}
+	funding := make([]types.Balance, len(state.Balances))
+	funding[0] = state.Balances[0]
+	datum := types.ChannelDatum{
+		ChannelParameters: params,
+		ChannelToken: types.ChannelToken{
+			TokenSymbol: hex.EncodeToString(symbol[:]),
+			TokenName:   hex.EncodeToString(state.ID[:]),
+			TxOutRef:    seed,
+		},
+		ChannelState:    state,
+		Time:            l.clock.Now(),
+		FundingBalances: funding,
+	}
+	datum.Funded = datum.FundingComplete()
+	tx.outputs = append(tx.outputs, Output{
+		Owner: ChannelScriptCredential,
+		Value: funding[0],
+		Datum: &datum,
+	}, change)
+	l.apply(tx)
+	return wire.Event{Tag: channel.CreatedTag, DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(datum)}}, nil
+}
+
+// fund deposits the balance of the party with the given index into the channel from the given wallet.
+func (l *ledger) fund(walletID string, id types.ID, token wire.AssetClass, index int) (wire.Event, error) {
+	ref, old, err := l.channelOutput(id, token)
+	if err != nil {
+		return wire.Event{}, err
+	}
+	if err = validateFund(*old.Datum, index, l.anyOrder); err != nil {
+		return wire.Event{}, err
+	}
+	amount := old.Datum.ChannelState.Balances[index]
+	tx, change, err := l.pay(walletID, amount)
+	if err != nil {
+		return wire.Event{}, err
+	}
+	datum := copyDatum(*old.Datum)
+	datum.FundingBalances[index] = amount
+	datum.Funded = datum.FundingComplete()
+	datum.Time = l.clock.Now()
+	tx.inputs = append(tx.inputs, ref)
+	tx.outputs = append(tx.outputs, Output{
+		Owner: ChannelScriptCredential,
+		Value: old.Value + amount,
+		Datum: &datum,
+	}, change)
+	l.apply(tx)
+	return wire.Event{
+		Tag:       channel.DepositedTag,
+		DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(*old.Datum), wire.MakeChannelDatum(datum)},
+	}, nil
+}
+
+// dispute registers the given signed state for the channel.
+func (l *ledger) dispute(id types.ID, token wire.AssetClass, state types.ChannelState, sigs []wallet.Sig) (wire.Event, error) {
+	ref, old, err := l.channelOutput(id, token)
+	if err != nil {
+		return wire.Event{}, err
+	}
+	now := l.clock.Now()
+	if err = validateDispute(*old.Datum, state, sigs, now, l.backend); err != nil {
+		return wire.Event{}, err
+	}
+	datum := copyDatum(*old.Datum)
+	datum.ChannelState = state
+	datum.Disputed = true
+	datum.Time = now
+	l.apply(transaction{
+		inputs:  []types.TxOutRef{ref},
+		outputs: []Output{{Owner: ChannelScriptCredential, Value: old.Value, Datum: &datum}},
+	})
+	signatures := make([]wire.Signature, len(sigs))
+	for i, sig := range sigs {
+		signatures[i] = wire.MakeSignature(sig)
+	}
+	return wire.Event{
+		Tag:        channel.DisputedTag,
+		DatumList:  []wire.ChannelDatum{wire.MakeChannelDatum(*old.Datum), wire.MakeChannelDatum(datum)},
+		Signatures: signatures,
+	}, nil
+}
+
+// close settles the channel with the given signed final state.
+func (l *ledger) close(id types.ID, token wire.AssetClass, state types.ChannelState, sigs []wallet.Sig) (wire.Event, error) {
+	ref, old, err := l.channelOutput(id, token)
+	if err != nil {
+		return wire.Event{}, err
+	}
+	if err = validateClose(*old.Datum, state, sigs, l.backend); err != nil {
+		return wire.Event{}, err
+	}
+	return l.payout(id, ref, old, state.Balances), nil
+}
+
+// forceClose settles the channel in its disputed state after the challenge duration expired.
+func (l *ledger) forceClose(id types.ID, token wire.AssetClass) (wire.Event, error) {
+	ref, old, err := l.channelOutput(id, token)
+	if err != nil {
+		return wire.Event{}, err
+	}
+	if err = validateForceClose(*old.Datum, l.clock.Now()); err != nil {
+		return wire.Event{}, err
+	}
+	return l.payout(id, ref, old, old.Datum.ChannelState.Balances), nil
+}
+
+// abort closes the channel, which is not fully funded, on behalf of the given wallet and refunds all deposits.
+func (l *ledger) abort(walletID string, id types.ID, token wire.AssetClass) (wire.Event, error) {
+	ref, old, err := l.channelOutput(id, token)
+	if err != nil {
+		return wire.Event{}, err
+	}
+	owner, ok := l.wallets[walletID]
+	if !ok {
+		return wire.Event{}, fmt.Errorf("unknown wallet: %s", walletID)
+	}
+	index := -1
+	for i, party := range old.Datum.ChannelParameters.Parties {
+		if party.GetPaymentCredential() == owner.GetPaymentCredential() {
+			index = i
+			break
+		}
+	}
+	if err = validateAbort(*old.Datum, index); err != nil {
+		return wire.Event{}, err
+	}
+	return l.payout(id, ref, old, old.Datum.FundingBalances), nil
+}
+
+// payout spends the channel output, burns the channel token and pays the given balances to the payout addresses of
+// the parties.
+func (l *ledger) payout(id types.ID, ref types.TxOutRef, old Output, balances []types.Balance) wire.Event {
+	tx := transaction{inputs: []types.TxOutRef{ref}}
+	for i, party := range old.Datum.ChannelParameters.Parties {
+		if balances[i] > 0 {
+			tx.outputs = append(tx.outputs, makeOutput(party, balances[i]))
+		}
+	}
+	l.apply(tx)
+	delete(l.channels, id)
+	return wire.Event{Tag: channel.ConcludedTag, DatumList: []wire.ChannelDatum{wire.MakeChannelDatum(*old.Datum)}}
+}
+
+// channelOutput returns the current output of the open channel with the given id, iff it carries the given token.
+func (l *ledger) channelOutput(id types.ID, token wire.AssetClass) (types.TxOutRef, Output, error) {
+	ref, ok := l.channels[id]
+	if !ok {
+		return ref, Output{}, fmt.Errorf("unknown channel: %x", id)
+	}
+	o := l.utxos[ref]
+	if !isToken(token, o.Datum.ChannelToken) {
+		return ref, Output{}, fmt.Errorf("channel token does not match channel: %x", id)
+	}
+	return ref, o, nil
+}
+
+// datum returns the current datum of the open channel with the given id.
+func (l *ledger) datum(id types.ID) (types.ChannelDatum, uint64, bool) {
+	ref, ok := l.channels[id]
+	if !ok {
+		return types.ChannelDatum{}, 0, false
+	}
+	o := l.utxos[ref]
+	return copyDatum(*o.Datum), o.Value, true
+}
+
+// pay returns a transaction that consumes outputs of the given wallet worth at least the given amount and the change
+// output of the transaction. At least one output is consumed, even if the amount is zero. The wallet spends all
+// outputs owned by its payment credential.
+func (l *ledger) pay(walletID string, amount uint64) (transaction, Output, error) {
+	addr, ok := l.wallets[walletID]
+	if !ok {
+		return transaction{}, Output{}, fmt.Errorf("unknown wallet: %s", walletID)
+	}
+	var refs []types.TxOutRef
+	for ref, o := range l.utxos {
+		if o.Owner == addr.GetPaymentCredential() {
+			refs = append(refs, ref)
+		}
+	}
+	// Outputs are selected in a deterministic order.
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].TxID != refs[j].TxID {
+			return refs[i].TxID < refs[j].TxID
+		}
+		return refs[i].Index < refs[j].Index
+	})
+	var tx transaction
+	var total uint64
+	for _, ref := range refs {
+		tx.inputs = append(tx.inputs, ref)
+		total += l.utxos[ref].Value
+		if total >= amount {
+			return tx, makeOutput(addr, total-amount), nil
+		}
+	}
+	return transaction{}, Output{}, &InsufficientFundsError{WalletID: walletID, Required: amount, Available: total}
+}
+
+// apply removes the inputs of the given transaction from the UTxO set and adds its outputs with a value or datum.
+func (l *ledger) apply(tx transaction) {
+	h := sha256.New()
+	var count [8]byte
+	binary.BigEndian.PutUint64(count[:], l.txCount)
+	h.Write(count[:])
+	for _, in := range tx.inputs {
+		h.Write([]byte(fmt.Sprintf("%s#%d", in.TxID, in.Index)))
+		delete(l.utxos, in)
+	}
+	l.txCount++
+	txID := hex.EncodeToString(h.Sum(nil))
+	for i, o := range tx.outputs {
+		if o.Value == 0 && o.Datum == nil {
+			continue
+		}
+		ref := types.TxOutRef{TxID: txID, Index: i}
+		l.utxos[ref] = o
+		if o.Datum != nil {
+			l.channels[o.Datum.ChannelState.ID] = ref
+		}
+	}
+}
+
+func copyDatum(datum types.ChannelDatum) types.ChannelDatum {
+	datum.FundingBalances = append([]types.Balance(nil), datum.FundingBalances...)
+	datum.ChannelState.Balances = append([]types.Balance(nil), datum.ChannelState.Balances...)
+	return datum
+}
+
+// makeOutput returns an output of the given value paid to the payout address of the given address.
+func makeOutput(addr address.Address, value uint64) Output {
+	o := Output{Owner: addr.GetPaymentCredential(), Value: value}
+	if stake, ok := addr.GetStakeCredential(); ok {
+		o.Stake = &stake
+	}
+	return o
+}
+
+// isPaidTo returns true, iff the given output is paid to the payout address of the given address.
+func isPaidTo(o Output, addr address.Address) bool {
+	if o.Owner != addr.GetPaymentCredential() {
+		return false
+	}
+	stake, ok := addr.GetStakeCredential()
+	if o.Stake == nil || !ok {
+		return o.Stake == nil && !ok
+	}
+	return *o.Stake == stake
+}
+
+// isToken returns true, iff the given asset class identifies the given channel token.
+func isToken(assetClass wire.AssetClass, token types.ChannelToken) bool {
+	actual, err := json.Marshal(assetClass)
+	if err != nil {
+		return false
+	}
+	expected, err := json.Marshal(wire.MakeAssetClass(token))
+	return err == nil && string(actual) == string(expected)
+}
+
+func mustHash(name string) [address.CredentialHashLength]byte {
+	hash, err := blake2b224.Sum224([]byte(name))
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"math/rand"
+	gpwallet "perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+	"time"
+)
+
+// openTestChannel opens a channel of the given balances between one new wallet per balance on a new ledger without
+// backend, which accepts deposits in any order, iff anyOrder is set. The second party is paid out to an address with a
+// stake credential.
+func openTestChannel(t *testing.T, rng *rand.Rand, anyOrder bool, balances ...types.Balance) (*ledger, []address.Address, types.ChannelState, wire.AssetClass) {
+	l := newLedger(NewSlotClock(time.Now(), DefaultSlotLength), nil, anyOrder)
+	parties := make([]address.Address, len(balances))
+	for i := range parties {
+		parties[i] = test.MakeRandomAddress(rng)
+		if i == 1 {
+			parties[i] = test.MakeRandomAddressWithStakeCredential(rng)
+		}
+		l.addWallet(fmt.Sprintf("wallet-%d", i), parties[i], 1000)
+	}
+	var id types.ID
+	rng.Read(id[:])
+	params := types.ChannelParameters{Parties: parties, Nonce: new(big.Int).SetUint64(rng.Uint64()), Timeout: time.Minute}
+	state := types.MakeChannelState(id, balances, 0, false)
+	_, err := l.open("wallet-0", params, state)
+	require.NoError(t, err)
+	datum, _, ok := l.datum(id)
+	require.True(t, ok)
+	return l, parties, state, wire.MakeAssetClass(datum.ChannelToken)
+}
+
+func TestLedger_FundInOrder(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	l, _, state, token := openTestChannel(t, rng, false, 5, 4, 0, 3)
+	datum, _, _ := l.datum(state.ID)
+	require.False(t, datum.Funded, "channel must not be funded before all parties funded their balance")
+
+	_, err := l.fund("wallet-3", state.ID, token, 3)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "party 3 deposited before party 1")
+	require.Contains(t, validationErr.Reason, "party 1 must deposit before party 3")
+
+	_, err = l.fund("wallet-1", state.ID, token, 1)
+	require.NoError(t, err)
+	datum, _, _ = l.datum(state.ID)
+	require.False(t, datum.Funded)
+	_, err = l.fund("wallet-1", state.ID, token, 1)
+	require.True(t, errors.As(err, &validationErr), "party 1 deposited twice")
+
+	// Party 2 has a zero balance, so it never deposits and party 3 deposits right after party 1.
+	_, err = l.fund("wallet-2", state.ID, token, 2)
+	require.True(t, errors.As(err, &validationErr), "party with zero balance deposited")
+	_, err = l.fund("wallet-3", state.ID, token, 3)
+	require.NoError(t, err)
+	datum, value, _ := l.datum(state.ID)
+	require.True(t, datum.Funded)
+	require.Equal(t, []types.Balance{5, 4, 0, 3}, datum.FundingBalances)
+	require.Equal(t, uint64(12), value)
+}
+
+func TestLedger_FundedOnCreation(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	l, _, state, token := openTestChannel(t, rng, false, 5, 0, 0)
+	datum, _, _ := l.datum(state.ID)
+	require.True(t, datum.Funded, "channel must be funded once every party funded its balance")
+	_, err := l.fund("wallet-1", state.ID, token, 1)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "party deposited into funded channel")
+}
+
+func TestLedger_FundInAnyOrder(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	l, _, state, token := openTestChannel(t, rng, true, 5, 4, 3)
+
+	_, err := l.fund("wallet-2", state.ID, token, 2)
+	require.NoError(t, err, "party 2 must be able to deposit before party 1")
+	datum, _, _ := l.datum(state.ID)
+	require.False(t, datum.Funded)
+	_, err = l.fund("wallet-2", state.ID, token, 2)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "party 2 deposited twice")
+
+	_, err = l.fund("wallet-1", state.ID, token, 1)
+	require.NoError(t, err)
+	datum, value, _ := l.datum(state.ID)
+	require.True(t, datum.Funded)
+	require.Equal(t, []types.Balance{5, 4, 3}, datum.FundingBalances)
+	require.Equal(t, uint64(12), value)
+}
+
+func TestLedger_PayoutToStakeCredential(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	l, parties, state, token := openTestChannel(t, rng, false, 5, 3)
+	_, err := l.fund("wallet-1", state.ID, token, 1)
+	require.NoError(t, err)
+
+	final := types.MakeChannelState(state.ID, []types.Balance{2, 6}, 1, true)
+	_, err = l.close(state.ID, token, final, make([]gpwallet.Sig, len(parties)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000-3+6), l.balance(parties[1]), "payout must go to the payout address of the party")
+	enterprise := parties[1]
+	enterprise.RemoveStakeCredential()
+	require.Zero(t, l.balance(enterprise), "payout must not go to the address without stake credential")
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"github.com/stretchr/testify/require"
+	"perun.network/perun-cardano-backend/channel"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+	"time"
+)
+
+func TestEmulator_RemoveClosedSubscribers(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	e := NewEmulator()
+	t.Cleanup(e.Close)
+	pab, err := channel.NewPAB(e.URL(), wallet.MakeRemoteAccount(test.MakeRandomAddress(rng), nil, "wallet"))
+	require.NoError(t, err)
+	var id types.ID
+	rng.Read(id[:])
+	subscribers := func() int {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return len(e.subscribers[id])
+	}
+
+	subs := make([]*channel.AdjudicatorSub, 2)
+	for i := range subs {
+		subs[i], err = pab.NewInternalSubscription(id)
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { return subscribers() == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, subs[0].Close())
+	require.Eventually(t, func() bool { return subscribers() == 1 }, time.Second, time.Millisecond,
+		"closed subscriber was not removed")
+	require.NoError(t, subs[1].Close())
+	require.Eventually(t, func() bool {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		_, ok := e.subscribers[id]
+		return !ok
+	}, time.Second, time.Millisecond, "channel without subscribers was not removed")
+}
@@ -0,0 +1,237 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"fmt"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/errclass"
+	"time"
+)
+
+// Redeemers of the channel validator.
+const (
+	RedeemerOpen       = "open"
+	RedeemerFund       = "fund"
+	RedeemerDispute    = "dispute"
+	RedeemerClose      = "close"
+	RedeemerForceClose = "forceClose"
+	RedeemerAbort      = "abort"
+)
+
+// ValidationError is returned, if the channel validator (or the minting policy of the channel token) rejects a
+// transaction.
+type ValidationError struct {
+	Redeemer string
+	Reason   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("channel validator rejected %s: %s", e.Redeemer, e.Reason)
+}
+
+// Class returns errclass.Contract.
+func (e *ValidationError) Class() errclass.Class {
+	return errclass.Contract
+}
+
+func reject(redeemer string, format string, args ...interface{}) error {
+	return &ValidationError{Redeemer: redeemer, Reason: fmt.Sprintf(format, args...)}
+}
+
+// validateOpen checks the parameters and initial state of a channel that is opened. If a backend is given, the channel
+// id must be the one calculated by the backend.
+func validateOpen(params types.ChannelParameters, state types.ChannelState, backend types.ExtendedWalletBackend) error {
+	if len(params.Parties) < 2 {
+		return reject(RedeemerOpen, "channel has %d parties, expected at least 2", len(params.Parties))
+	}
+	if len(state.Balances) != len(params.Parties) {
+		return reject(RedeemerOpen, "state has %d balances for %d parties", len(state.Balances), len(params.Parties))
+	}
+	if _, ok := sum(state.Balances); !ok {
+		return reject(RedeemerOpen, "total balance overflows")
+	}
+	if params.Timeout <= 0 {
+		return reject(RedeemerOpen, "challenge duration must be positive")
+	}
+	if state.Version != 0 || state.Final {
+		return reject(RedeemerOpen, "initial state must have version 0 and must not be final")
+	}
+	if backend != nil {
+		id, err := backend.CalculateChannelID(params)
+		if err != nil {
+			return fmt.Errorf("unable to calculate channel id: %w", err)
+		}
+		if id != state.ID {
+			return reject(RedeemerOpen, "channel id does not match the channel parameters")
+		}
+	}
+	return nil
+}
+
+// validateFund checks that the party with the given index may deposit into the channel with the given datum. Every
+// party deposits its balance once, so parties with a zero balance never deposit. Unless anyOrder is set, parties
+// deposit in the order of their index, skipping parties with a zero balance.
+func validateFund(datum types.ChannelDatum, index int, anyOrder bool) error {
+	if datum.Funded {
+		return reject(RedeemerFund, "channel is already funded")
+	}
+	if datum.Disputed {
+		return reject(RedeemerFund, "channel is disputed")
+	}
+	if index < 0 || index >= len(datum.FundingBalances) {
+		return reject(RedeemerFund, "invalid party index: %d", index)
+	}
+	if datum.FundingBalances[index] >= datum.ChannelState.Balances[index] {
+		return reject(RedeemerFund, "party %d already funded its balance", index)
+	}
+	for i := 0; i < index && !anyOrder; i++ {
+		if datum.FundingBalances[i] < datum.ChannelState.Balances[i] {
+			return reject(RedeemerFund, "party %d must deposit before party %d", i, index)
+		}
+	}
+	return nil
+}
+
+// validateDispute checks that the given signed state may be registered for the channel with the given datum at the
+// given time.
+func validateDispute(
+	datum types.ChannelDatum,
+	state types.ChannelState,
+	sigs []wallet.Sig,
+	now time.Time,
+	backend types.ExtendedWalletBackend,
+) error {
+	if !datum.Funded {
+		return reject(RedeemerDispute, "channel is not funded")
+	}
+	if err := validateState(RedeemerDispute, datum, state); err != nil {
+		return err
+	}
+	if datum.Disputed {
+		if state.Version <= datum.ChannelState.Version {
+			return reject(RedeemerDispute, "state version %d is not newer than the disputed version %d",
+				state.Version, datum.ChannelState.Version)
+		}
+		if !now.Before(datum.Time.Add(datum.ChannelParameters.Timeout)) {
+			return reject(RedeemerDispute, "challenge duration expired")
+		}
+	}
+	return validateSignatures(RedeemerDispute, datum, state, sigs, backend)
+}
+
+// validateClose checks that the channel with the given datum may be closed with the given signed state.
+func validateClose(
+	datum types.ChannelDatum,
+	state types.ChannelState,
+	sigs []wallet.Sig,
+	backend types.ExtendedWalletBackend,
+) error {
+	if !datum.Funded {
+		return reject(RedeemerClose, "channel is not funded")
+	}
+	if !state.Final {
+		return reject(RedeemerClose, "state is not final")
+	}
+	if err := validateState(RedeemerClose, datum, state); err != nil {
+		return err
+	}
+	return validateSignatures(RedeemerClose, datum, state, sigs, backend)
+}
+
+// validateForceClose checks that the channel with the given datum may be settled in its disputed state at the given
+// time.
+func validateForceClose(datum types.ChannelDatum, now time.Time) error {
+	if !datum.Disputed {
+		return reject(RedeemerForceClose, "channel is not disputed")
+	}
+	if now.Before(datum.Time.Add(datum.ChannelParameters.Timeout)) {
+		return reject(RedeemerForceClose, "challenge duration has not expired")
+	}
+	return nil
+}
+
+// validateAbort checks that the party with the given index may abort the channel with the given datum.
+func validateAbort(datum types.ChannelDatum, index int) error {
+	if datum.Funded {
+		return reject(RedeemerAbort, "channel is funded")
+	}
+	if index < 0 {
+		return reject(RedeemerAbort, "only channel parties may abort the channel")
+	}
+	return nil
+}
+
+// validateState checks that the given state is a valid successor of the state in the given datum, which does not
+// change the total value locked in the channel.
+func validateState(redeemer string, datum types.ChannelDatum, state types.ChannelState) error {
+	if state.ID != datum.ChannelState.ID {
+		return reject(redeemer, "state belongs to another channel")
+	}
+	if len(state.Balances) != len(datum.ChannelState.Balances) {
+		return reject(redeemer, "state has %d balances, expected %d",
+			len(state.Balances), len(datum.ChannelState.Balances))
+	}
+	if state.Version < datum.ChannelState.Version {
+		return reject(redeemer, "state version %d is older than the on-chain version %d",
+			state.Version, datum.ChannelState.Version)
+	}
+	total, ok := sum(state.Balances)
+	if locked, _ := sum(datum.FundingBalances); !ok || total != locked {
+		return reject(redeemer, "state balances do not match the locked funds")
+	}
+	return nil
+}
+
+// validateSignatures checks that the given state is signed by all parties of the channel. Signatures are only checked,
+// if a backend is given.
+func validateSignatures(
+	redeemer string,
+	datum types.ChannelDatum,
+	state types.ChannelState,
+	sigs []wallet.Sig,
+	backend types.ExtendedWalletBackend,
+) error {
+	parties := datum.ChannelParameters.Parties
+	if len(sigs) != len(parties) {
+		return reject(redeemer, "got %d signatures for %d parties", len(sigs), len(parties))
+	}
+	if backend == nil {
+		return nil
+	}
+	for i := range parties {
+		valid, err := backend.VerifyChannelStateSignature(state, sigs[i], &parties[i])
+		if err != nil {
+			return fmt.Errorf("unable to verify signature of party %d: %w", i, err)
+		}
+		if !valid {
+			return reject(redeemer, "invalid signature of party %d", i)
+		}
+	}
+	return nil
+}
+
+// sum returns the sum of the given balances and false, iff the sum overflows.
+func sum(balances []types.Balance) (uint64, bool) {
+	var total uint64
+	for _, b := range balances {
+		if total+b < total {
+			return 0, false
+		}
+		total += b
+	}
+	return total, true
+}
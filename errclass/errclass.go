@@ -0,0 +1,90 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errclass classifies the errors of this backend, so that callers can branch on the kind of failure (e.g. to
+// decide whether to retry or to alert) without knowing every concrete error type. The concrete error types are
+// defined in the packages that return them and can be inspected with errors.As.
+package errclass
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Class is the kind of failure an error represents.
+type Class int
+
+const (
+	// Unknown is the Class of errors that are not classified.
+	Unknown Class = iota
+	// Network errors occur, if a server is unreachable or does not respond in time.
+	Network
+	// Client errors occur, if a server rejects a request as invalid (HTTP 4xx).
+	Client
+	// Server errors occur, if a server fails to process a request (HTTP 5xx).
+	Server
+	// Ambiguous errors occur, if a request failed after it may have been processed by the server.
+	Ambiguous
+	// KeyUnavailable errors occur, if the wallet server does not hold the keys needed to sign for an address.
+	KeyUnavailable
+	// Protocol errors occur, if a server sends unexpected or malformed data (e.g. an unexpected event).
+	Protocol
+	// Funding errors occur, if the on-chain funding of a channel does not match the funding request.
+	Funding
+	// Contract errors occur, if a contract endpoint fails on-chain (e.g. the transaction does not balance or the
+	// validator rejects it).
+	Contract
+)
+
+// String returns the name of the Class.
+func (c Class) String() string {
+	switch c {
+	case Unknown:
+		return "Unknown"
+	case Network:
+		return "Network"
+	case Client:
+		return "Client"
+	case Server:
+		return "Server"
+	case Ambiguous:
+		return "Ambiguous"
+	case KeyUnavailable:
+		return "KeyUnavailable"
+	case Protocol:
+		return "Protocol"
+	case Funding:
+		return "Funding"
+	case Contract:
+		return "Contract"
+	default:
+		return fmt.Sprintf("Class(%d)", int(c))
+	}
+}
+
+// Classified is implemented by all classified errors.
+type Classified interface {
+	error
+	Class() Class
+}
+
+// Of returns the Class of the outermost classified error in the chain of the given error. It returns Unknown, if the
+// chain contains no classified error.
+func Of(err error) Class {
+	var classified Classified
+	if errors.As(err, &classified) {
+		return classified.Class()
+	}
+	return Unknown
+}
@@ -0,0 +1,43 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errclass_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"perun.network/perun-cardano-backend/errclass"
+	"testing"
+)
+
+type classifiedError errclass.Class
+
+func (e classifiedError) Error() string {
+	return errclass.Class(e).String()
+}
+
+func (e classifiedError) Class() errclass.Class {
+	return errclass.Class(e)
+}
+
+func TestOf(t *testing.T) {
+	require.Equal(t, errclass.Unknown, errclass.Of(nil))
+	require.Equal(t, errclass.Unknown, errclass.Of(errors.New("plain error")))
+	require.Equal(t, errclass.Funding, errclass.Of(classifiedError(errclass.Funding)))
+	wrapped := fmt.Errorf("outer: %w", classifiedError(errclass.Network))
+	require.Equal(t, errclass.Network, errclass.Of(wrapped), "wrapped error should be classified")
+	require.Equal(t, "Network", errclass.Of(wrapped).String())
+	require.Equal(t, "Class(42)", errclass.Class(42).String())
+}
@@ -0,0 +1,122 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Authenticator authenticates requests to the wallet server and the PAB server.
+type Authenticator interface {
+	// Authenticate adds the credentials to the given request with the given body.
+	Authenticate(request *http.Request, body []byte) error
+}
+
+// BearerToken authenticates requests with an `Authorization: Bearer <token>` header.
+type BearerToken string
+
+// Authenticate sets the Authorization header of the given request.
+func (t BearerToken) Authenticate(request *http.Request, _ []byte) error {
+	request.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+const (
+	// HMACTimestampHeader is the header holding the unix time (in seconds) at which a request was signed.
+	HMACTimestampHeader = "X-Perun-Timestamp"
+	hmacScheme          = "HMAC-SHA256"
+)
+
+// HMACAuth authenticates requests with an HMAC-SHA256 signature under a shared secret. The signed message is the
+// method, the request URI, the timestamp and the hex encoded SHA-256 hash of the body, separated by newlines. The
+// signature is sent as `Authorization: HMAC-SHA256 KeyId=<id>, Signature=<hex>` and the timestamp in the
+// HMACTimestampHeader.
+type HMACAuth struct {
+	KeyID  string
+	Secret []byte
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// Authenticate signs the given request with the given body.
+func (a HMACAuth) Authenticate(request *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(a.now().Unix(), 10)
+	request.Header.Set(HMACTimestampHeader, timestamp)
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"%s KeyId=%s, Signature=%s",
+		hmacScheme,
+		a.KeyID,
+		hex.EncodeToString(a.sign(request, timestamp, body)),
+	))
+	return nil
+}
+
+// Verify returns nil, iff the given request with the given body carries a valid signature of this key that was created
+// at most maxAge ago.
+func (a HMACAuth) Verify(request *http.Request, body []byte, maxAge time.Duration) error {
+	fields := strings.TrimPrefix(request.Header.Get("Authorization"), hmacScheme+" ")
+	var keyID, signature string
+	for _, field := range strings.Split(fields, ", ") {
+		if v := strings.TrimPrefix(field, "KeyId="); v != field {
+			keyID = v
+		} else if v := strings.TrimPrefix(field, "Signature="); v != field {
+			signature = v
+		}
+	}
+	if keyID != a.KeyID {
+		return errors.New("request is not signed with the expected key")
+	}
+	timestamp := request.Header.Get(HMACTimestampHeader)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp: %w", err)
+	}
+	if age := a.now().Sub(time.Unix(signedAt, 0)); age > maxAge || age < -maxAge {
+		return errors.New("request timestamp is out of range")
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, a.sign(request, timestamp, body)) {
+		return errors.New("invalid request signature")
+	}
+	return nil
+}
+
+func (a HMACAuth) sign(request *http.Request, timestamp string, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, a.Secret)
+	mac.Write([]byte(request.Method + "\n" + request.URL.RequestURI() + "\n" + timestamp + "\n" +
+		hex.EncodeToString(bodyHash[:])))
+	return mac.Sum(nil)
+}
+
+func (a HMACAuth) now() time.Time {
+	if a.Now != nil {
+		return a.Now()
+	}
+	return time.Now()
+}
+
+var (
+	_ Authenticator = BearerToken("")
+	_ Authenticator = HMACAuth{}
+)
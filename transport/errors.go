@@ -0,0 +1,94 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"fmt"
+	"perun.network/perun-cardano-backend/errclass"
+)
+
+// NetworkError is returned, if a request to an endpoint failed without a response of the server (e.g. the server is
+// unreachable or the request timed out).
+type NetworkError struct {
+	Endpoint string
+	URL      string
+	Err      error
+}
+
+// Error returns the cause of the failure.
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("request to %s failed: %v", e.URL, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Class returns errclass.Network.
+func (e *NetworkError) Class() errclass.Class {
+	return errclass.Network
+}
+
+// StatusError is returned for responses with a status code other than 200 OK.
+type StatusError struct {
+	// Server names the server that responded (e.g. "wallet server").
+	Server     string
+	Endpoint   string
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// Error returns the status and body of the response.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to interact with %s: %s with error: %s", e.Server, e.Status, string(e.Body))
+}
+
+// Class returns errclass.Client for 4xx status codes and errclass.Server otherwise.
+func (e *StatusError) Class() errclass.Class {
+	if e.StatusCode >= 400 && e.StatusCode < 500 {
+		return errclass.Client
+	}
+	return errclass.Server
+}
+
+// AmbiguousError is returned for requests to endpoints that are not idempotent, if the request failed after it may
+// have reached the server. The request was not repeated to prevent double submission and it is unknown whether the
+// server processed it.
+type AmbiguousError struct {
+	Err error
+}
+
+// Error returns the error of the request.
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("request may have been processed: %v", e.Err)
+}
+
+// Unwrap returns the error of the request.
+func (e *AmbiguousError) Unwrap() error {
+	return e.Err
+}
+
+// Class returns errclass.Ambiguous.
+func (e *AmbiguousError) Class() errclass.Class {
+	return errclass.Ambiguous
+}
+
+var (
+	_ errclass.Classified = &NetworkError{}
+	_ errclass.Classified = &StatusError{}
+	_ errclass.Classified = &AmbiguousError{}
+)
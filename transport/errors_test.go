@@ -0,0 +1,51 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/transport"
+	"testing"
+)
+
+func TestErrors_Class(t *testing.T) {
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	config := transport.NewConfig(transport.WithRetryPolicy(transport.NoRetry))
+	_, err := config.Call("test server", []string{unreachable.URL}, "/verify", true, nil)
+	var netErr *transport.NetworkError
+	require.True(t, errors.As(err, &netErr), "failure to connect should be a NetworkError")
+	require.Equal(t, "/verify", netErr.Endpoint)
+	require.Equal(t, errclass.Network, errclass.Of(err))
+	require.True(t, transport.IsUndelivered(err), "failure to connect should be undelivered")
+
+	server, _ := flakyServer(http.StatusNotFound, 1)
+	defer server.Close()
+	_, err = config.Call("test server", []string{server.URL}, "/missing", true, nil)
+	var statusErr *transport.StatusError
+	require.True(t, errors.As(err, &statusErr), "error response should be a StatusError")
+	require.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	require.Equal(t, "/missing", statusErr.Endpoint)
+	require.Equal(t, "failure\n", string(statusErr.Body))
+	require.Equal(t, errclass.Client, errclass.Of(err))
+
+	require.Equal(t, errclass.Server, errclass.Of(&transport.StatusError{StatusCode: http.StatusInternalServerError}))
+	require.Equal(t, errclass.Ambiguous, errclass.Of(&transport.AmbiguousError{Err: statusErr}),
+		"ambiguous errors should be classified as ambiguous regardless of their cause")
+}
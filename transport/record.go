@@ -0,0 +1,251 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ExchangeKind is the kind of an Exchange in a recording.
+type ExchangeKind string
+
+const (
+	// ExchangeRequest is an http request and its response.
+	ExchangeRequest ExchangeKind = "request"
+	// ExchangeDial is a websocket handshake.
+	ExchangeDial ExchangeKind = "dial"
+	// ExchangeMessage is a message received on a websocket connection.
+	ExchangeMessage ExchangeKind = "message"
+	// ExchangeEnd is the end of a websocket connection.
+	ExchangeEnd ExchangeKind = "end"
+)
+
+// Exchange is a single entry of a recording. A recording is a file holding one json encoded Exchange per line in the
+// order in which the exchanges completed.
+type Exchange struct {
+	Kind ExchangeKind `json:"kind"`
+	// Method is the http method of a request.
+	Method string `json:"method,omitempty"`
+	// Endpoint is the endpoint of a request or websocket handshake.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Request is the body of a request.
+	Request string `json:"request,omitempty"`
+	// StatusCode and Status are the status of the response to a request or a rejected websocket handshake.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Status     string `json:"status,omitempty"`
+	// Response is the body of the response to a request or a rejected websocket handshake, or a websocket message.
+	Response string `json:"response,omitempty"`
+	// Connection identifies the websocket connection of a handshake, message or end.
+	Connection int `json:"connection,omitempty"`
+	// MessageType is the websocket.TextMessage or websocket.BinaryMessage type of a message.
+	MessageType int `json:"messageType,omitempty"`
+	// ClosedByClient is true, iff a websocket connection ended because the client closed it.
+	ClosedByClient bool `json:"closedByClient,omitempty"`
+	// Error is the failure of a request or websocket handshake without a response, or the failure that ended a
+	// websocket connection.
+	Error *RecordedError `json:"error,omitempty"`
+}
+
+// RecordedError is a failure in a recording. It keeps the properties that decide whether a request is retried, so
+// that a Replayer repeats requests like the recorded session did.
+type RecordedError struct {
+	Message string `json:"message"`
+	// Network is true, iff the failure was a NetworkError.
+	Network bool `json:"network,omitempty"`
+	// Transient and Undelivered are the results of IsTransient and IsUndelivered for the failure.
+	Transient   bool `json:"transient,omitempty"`
+	Undelivered bool `json:"undelivered,omitempty"`
+}
+
+// makeRecordedError returns the RecordedError of the given failure.
+func makeRecordedError(err error) *RecordedError {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		err = netErr.Err
+	}
+	return &RecordedError{
+		Message:     err.Error(),
+		Network:     netErr != nil,
+		Transient:   IsTransient(err),
+		Undelivered: IsUndelivered(err),
+	}
+}
+
+// Recorder writes every request, response, websocket handshake and websocket message of the Configs it is passed to
+// (see WithRecorder) to a recording, which can be served back by a Replayer. A Recorder may be shared by several
+// Configs (e.g. of the PAB and the wallet server) to record a whole session into one file.
+type Recorder struct {
+	mu          sync.Mutex
+	encoder     *json.Encoder
+	closer      io.Closer
+	connections int
+	closed      bool
+	err         error
+}
+
+// NewRecorder returns a new Recorder writing to the given writer.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w)}
+}
+
+// CreateRecording creates (or truncates) the given file and returns a Recorder writing to it. The Recorder must be
+// closed using Close.
+func CreateRecording(file string) (*Recorder, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create recording: %w", err)
+	}
+	r := NewRecorder(f)
+	r.closer = f
+	return r, nil
+}
+
+// WithRecorder records all requests and websocket connections of the Config with the given Recorder.
+func WithRecorder(recorder *Recorder) Option {
+	return func(c *Config) {
+		c.recorder = recorder
+	}
+}
+
+// Err returns the first error that occurred while writing the recording or nil, if there was none.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close stops recording, closes the file of the Recorder, if it was created by CreateRecording, and returns the first
+// error that occurred while writing the recording. Exchanges completing after Close (e.g. the end of a websocket
+// connection that is still open) are not recorded.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = fmt.Errorf("unable to close recording: %w", err)
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+// record writes the given exchange to the recording. Failures are kept for Err, so that they do not affect the
+// recorded session.
+func (r *Recorder) record(exchange Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	if err := r.encoder.Encode(exchange); err != nil && r.err == nil {
+		r.err = fmt.Errorf("unable to write recording: %w", err)
+	}
+}
+
+// recordRequest records a request and its response or failure.
+func (r *Recorder) recordRequest(method string, endpoint string, jsonBody []byte, response Response, err error) {
+	exchange := Exchange{Kind: ExchangeRequest, Method: method, Endpoint: endpoint, Request: string(jsonBody)}
+	if err != nil {
+		exchange.Error = makeRecordedError(err)
+	} else {
+		exchange.StatusCode = response.StatusCode
+		exchange.Status = response.Status
+		exchange.Response = string(response.Body)
+	}
+	r.record(exchange)
+}
+
+// recordDial records a websocket handshake and returns a Conn recording all messages of the established connection.
+func (r *Recorder) recordDial(endpoint string, conn Conn, err error) (Conn, error) {
+	exchange := Exchange{Kind: ExchangeDial, Endpoint: endpoint}
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr):
+		exchange.StatusCode = statusErr.StatusCode
+		exchange.Status = statusErr.Status
+		exchange.Response = string(statusErr.Body)
+	case err != nil:
+		exchange.Error = makeRecordedError(err)
+	default:
+		r.mu.Lock()
+		r.connections++
+		exchange.Connection = r.connections
+		r.mu.Unlock()
+	}
+	r.record(exchange)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingConn{Conn: conn, recorder: r, id: exchange.Connection}, nil
+}
+
+// recordingConn is a Conn recording all messages it reads.
+type recordingConn struct {
+	Conn
+	recorder *Recorder
+	id       int
+
+	mu     sync.Mutex
+	closed bool
+	ended  bool
+}
+
+// ReadMessage reads the next message and records it.
+func (c *recordingConn) ReadMessage() (int, []byte, error) {
+	messageType, message, err := c.Conn.ReadMessage()
+	if err != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if !c.ended {
+			c.ended = true
+			exchange := Exchange{Kind: ExchangeEnd, Connection: c.id, ClosedByClient: c.closed}
+			if !c.closed {
+				exchange.Error = makeRecordedError(err)
+			}
+			c.recorder.record(exchange)
+		}
+		return messageType, message, err
+	}
+	c.recorder.record(Exchange{
+		Kind:        ExchangeMessage,
+		Connection:  c.id,
+		MessageType: messageType,
+		Response:    string(message),
+	})
+	return messageType, message, nil
+}
+
+// ReadJSON reads the next message, records it and decodes it into v.
+func (c *recordingConn) ReadJSON(v interface{}) error {
+	_, message, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(message, v)
+}
+
+// Close closes the connection.
+func (c *recordingConn) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
@@ -0,0 +1,138 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport_test
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/transport"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// session issues a fixed sequence of requests and websocket reads with the given Config and returns their results.
+func session(t *testing.T, config *transport.Config, baseUrl string) []string {
+	var results []string
+	response, err := config.Call("test server", []string{baseUrl}, "/echo", false, []byte(`"hello"`))
+	require.NoError(t, err)
+	results = append(results, string(response))
+	for i := 0; i < 2; i++ {
+		response, err = config.Query("test server", []string{baseUrl}, "/status")
+		require.NoError(t, err)
+		results = append(results, string(response))
+	}
+	response, err = config.Call("test server", []string{baseUrl}, "/flaky", true, nil)
+	require.NoError(t, err, "flaky request was not retried")
+	results = append(results, string(response))
+	_, err = config.Call("test server", []string{baseUrl}, "/missing", true, nil)
+	require.Equal(t, errclass.Client, errclass.Of(err))
+	results = append(results, err.Error())
+
+	conn, err := config.Dial("test server", "ws"+strings.TrimPrefix(baseUrl, "http")+"/ws/1", "/ws/1")
+	require.NoError(t, err)
+	defer conn.Close()
+	for {
+		var message string
+		if err := conn.ReadJSON(&message); err != nil {
+			break
+		}
+		results = append(results, message)
+	}
+	return results
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	var statusQueries, flakyRequests int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			_, _ = w.Write([]byte(`"echo"`))
+		case "/status":
+			_, _ = w.Write([]byte(strconv.Itoa(int(atomic.AddInt32(&statusQueries, 1)))))
+		case "/flaky":
+			if atomic.AddInt32(&flakyRequests, 1) == 1 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`"recovered"`))
+		case "/ws/1":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			_ = conn.WriteJSON("first")
+			_ = conn.WriteJSON("second")
+			_ = conn.Close()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	file := filepath.Join(t.TempDir(), "session.jsonl")
+
+	recorder, err := transport.CreateRecording(file)
+	require.NoError(t, err)
+	recorded := session(t, transport.NewConfig(transport.WithRecorder(recorder), transport.WithRetryPolicy(fastRetries)), server.URL)
+	require.NoError(t, recorder.Close())
+	require.Equal(t, []string{`"echo"`, "1", "2", `"recovered"`}, recorded[:4])
+	require.Equal(t, []string{"first", "second"}, recorded[5:])
+	server.Close()
+
+	replayer, err := transport.LoadRecording(file)
+	require.NoError(t, err)
+	config := transport.NewConfig(transport.WithReplayer(replayer), transport.WithRetryPolicy(fastRetries))
+	require.Equal(t, recorded, session(t, config, "http://replayed.example.com"), "replayed session differs")
+	require.Zero(t, replayer.Unconsumed(), "recorded exchanges were not replayed")
+
+	response, err := config.Query("test server", []string{server.URL}, "/status")
+	require.NoError(t, err, "queries should repeat their last response")
+	require.Equal(t, "2", string(response))
+	_, err = config.Call("test server", []string{server.URL}, "/echo", false, []byte(`"hello"`))
+	require.Error(t, err, "non-query requests should not be repeated")
+	_, err = config.Dial("test server", "ws://replayed.example.com/ws/1", "/ws/1")
+	require.Error(t, err, "websocket connections should not be repeated")
+}
+
+func TestReplayer_ClosedByClient(t *testing.T) {
+	replayer := transport.NewReplayer([]transport.Exchange{
+		{Kind: transport.ExchangeDial, Endpoint: "/ws/1", Connection: 1},
+		{Kind: transport.ExchangeMessage, Connection: 1, MessageType: websocket.TextMessage, Response: `"event"`},
+		{Kind: transport.ExchangeEnd, Connection: 1, ClosedByClient: true},
+	})
+	conn, err := transport.NewConfig(transport.WithReplayer(replayer)).Dial("test server", "ws://replayed/ws/1", "/ws/1")
+	require.NoError(t, err)
+	var message string
+	require.NoError(t, conn.ReadJSON(&message))
+	require.Equal(t, "event", message)
+
+	done := make(chan error)
+	go func() {
+		_, _, err := conn.ReadMessage()
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("connection closed by the client should block until it is closed")
+	default:
+	}
+	require.NoError(t, conn.Close())
+	require.Error(t, <-done)
+}
@@ -0,0 +1,242 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Replayer serves a recording of a Recorder back to the Configs it is passed to (see WithReplayer), without contacting
+// any server. Requests are answered with the recorded responses to requests with the same method, endpoint and body
+// in the recorded order, independent of the host they are sent to. Queries (GET requests) are answered with their
+// last recorded response, once their recorded responses are exhausted, because the number of status queries depends
+// on timing. Websocket handshakes are answered with the recorded connections to the same endpoint in the recorded
+// order, which yield the recorded messages. A replayed connection ends like the recorded one, or blocks until it is
+// closed, if the client closed the recorded connection.
+type Replayer struct {
+	mu         sync.Mutex
+	requests   map[string][]Exchange
+	dials      map[string][]Exchange
+	messages   map[int][]Exchange
+	ends       map[int]Exchange
+	next       map[string]int
+	unconsumed int
+}
+
+// NewReplayer returns a new Replayer serving the given recorded exchanges.
+func NewReplayer(exchanges []Exchange) *Replayer {
+	r := &Replayer{
+		requests: make(map[string][]Exchange),
+		dials:    make(map[string][]Exchange),
+		messages: make(map[int][]Exchange),
+		ends:     make(map[int]Exchange),
+		next:     make(map[string]int),
+	}
+	for _, e := range exchanges {
+		switch e.Kind {
+		case ExchangeRequest:
+			key := requestKey(e.Method, e.Endpoint, []byte(e.Request))
+			r.requests[key] = append(r.requests[key], e)
+			r.unconsumed++
+		case ExchangeDial:
+			key := "dial " + e.Endpoint
+			r.dials[key] = append(r.dials[key], e)
+			r.unconsumed++
+		case ExchangeMessage:
+			r.messages[e.Connection] = append(r.messages[e.Connection], e)
+		case ExchangeEnd:
+			r.ends[e.Connection] = e
+		}
+	}
+	return r
+}
+
+// ReadRecording reads a recording of a Recorder from the given reader and returns a Replayer serving it.
+func ReadRecording(reader io.Reader) (*Replayer, error) {
+	var exchanges []Exchange
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("malformed recording entry %d: %w", len(exchanges)+1, err)
+		}
+		exchanges = append(exchanges, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read recording: %w", err)
+	}
+	return NewReplayer(exchanges), nil
+}
+
+// LoadRecording reads the given recording file and returns a Replayer serving it.
+func LoadRecording(file string) (*Replayer, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recording: %w", err)
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
+
+// WithReplayer answers all requests and websocket handshakes of the Config with the given Replayer instead of sending
+// them to a server.
+func WithReplayer(replayer *Replayer) Option {
+	return func(c *Config) {
+		c.replayer = replayer
+	}
+}
+
+// Unconsumed returns the number of recorded requests and websocket handshakes that were not replayed yet. Replaying a
+// whole session consumes all of them.
+func (r *Replayer) Unconsumed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.unconsumed
+}
+
+func requestKey(method string, endpoint string, jsonBody []byte) string {
+	return method + " " + endpoint + " " + string(jsonBody)
+}
+
+// nextExchange returns the next recorded exchange of the given queue. If repeatLast is true, the last exchange is
+// returned once the queue is exhausted.
+func (r *Replayer) nextExchange(queues map[string][]Exchange, key string, repeatLast bool) (Exchange, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queue := queues[key]
+	next := r.next[key]
+	if next < len(queue) {
+		r.next[key]++
+		r.unconsumed--
+		return queue[next], true
+	}
+	if repeatLast && len(queue) > 0 {
+		return queue[len(queue)-1], true
+	}
+	return Exchange{}, false
+}
+
+// replayRequest returns the recorded response to the given request.
+func (r *Replayer) replayRequest(method string, baseUrl string, endpoint string, jsonBody []byte) (Response, error) {
+	e, ok := r.nextExchange(r.requests, requestKey(method, endpoint, jsonBody), method == http.MethodGet)
+	if !ok {
+		return Response{}, fmt.Errorf("no recorded response to %s %s", method, endpoint)
+	}
+	if e.Error != nil {
+		return Response{}, replayError(e.Error, endpoint, baseUrl+endpoint)
+	}
+	return Response{StatusCode: e.StatusCode, Status: e.Status, Body: []byte(e.Response)}, nil
+}
+
+// replayDial returns the recorded connection to the given websocket endpoint.
+func (r *Replayer) replayDial(server string, url string, endpoint string) (Conn, error) {
+	e, ok := r.nextExchange(r.dials, "dial "+endpoint, false)
+	if !ok {
+		return nil, fmt.Errorf("no recorded websocket connection to %s", endpoint)
+	}
+	switch {
+	case e.Error != nil:
+		return nil, replayError(e.Error, endpoint, url)
+	case e.StatusCode != 0:
+		return nil, &StatusError{
+			Server:     server,
+			Endpoint:   endpoint,
+			StatusCode: e.StatusCode,
+			Status:     e.Status,
+			Body:       []byte(e.Response),
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conn := &replayConn{messages: r.messages[e.Connection], closed: make(chan struct{})}
+	if end, ok := r.ends[e.Connection]; ok && !end.ClosedByClient {
+		conn.end = end.Error
+	}
+	return conn, nil
+}
+
+// replayError returns an error with the properties of the given recorded failure.
+func replayError(recorded *RecordedError, endpoint string, url string) error {
+	err := errors.New(recorded.Message)
+	switch {
+	case recorded.Undelivered:
+		err = &net.OpError{Op: "dial", Net: "tcp", Err: err}
+	case recorded.Transient:
+		err = &net.OpError{Op: "read", Net: "tcp", Err: err}
+	}
+	if recorded.Network {
+		return &NetworkError{Endpoint: endpoint, URL: url, Err: err}
+	}
+	return err
+}
+
+// replayConn is a Conn yielding recorded messages.
+type replayConn struct {
+	mu       sync.Mutex
+	messages []Exchange
+	end      *RecordedError
+	closed   chan struct{}
+	once     sync.Once
+}
+
+// ReadMessage returns the next recorded message. Once all messages are read, it returns the recorded failure that
+// ended the connection or blocks until the connection is closed.
+func (c *replayConn) ReadMessage() (int, []byte, error) {
+	select {
+	case <-c.closed:
+		return 0, nil, net.ErrClosed
+	default:
+	}
+	c.mu.Lock()
+	if len(c.messages) > 0 {
+		e := c.messages[0]
+		c.messages = c.messages[1:]
+		c.mu.Unlock()
+		return e.MessageType, []byte(e.Response), nil
+	}
+	c.mu.Unlock()
+	if c.end != nil {
+		return 0, nil, replayError(c.end, "", "")
+	}
+	<-c.closed
+	return 0, nil, net.ErrClosed
+}
+
+// ReadJSON decodes the next recorded message into v.
+func (c *replayConn) ReadJSON(v interface{}) error {
+	_, message, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(message, v)
+}
+
+// Close closes the connection.
+func (c *replayConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
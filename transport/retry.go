@@ -0,0 +1,208 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures how often and after which delay failed requests are retried. The delay before retry n
+// (starting at 1) is InitialBackoff * Multiplier^(n-1), capped at MaxBackoff and randomized by +-Jitter (a fraction of
+// the delay).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request including the first one. If failover hosts are
+	// configured, every host is tried at least once.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+}
+
+// DefaultRetryPolicy is the RetryPolicy used, if none is configured.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// NoRetry is a RetryPolicy that never retries requests on the same host.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// Backoff returns the delay before the given retry (starting at 1) using the given source of randomness for jitter.
+func (p RetryPolicy) Backoff(retry int, rng *rand.Rand) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		delay *= p.Multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rng.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// WithRetryPolicy sets the RetryPolicy of requests. It defaults to DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Config) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithFailoverHosts adds servers that are used, if the primary server is unreachable or keeps failing. The addresses
+// have the same format as the primary address. All hosts must serve the same state (e.g. PAB replicas sharing
+// contract instances). After a failover, requests stick to the new host until it fails.
+func WithFailoverHosts(addrs ...string) Option {
+	return func(c *Config) {
+		c.failoverHosts = append(c.failoverHosts, addrs...)
+	}
+}
+
+// IsTransient returns true, iff the given error may disappear when the request is repeated: the server was
+// unreachable, the request timed out or the server (or a gateway) responded with 429, 502, 503 or 504. Errors caused by
+// the configuration (an untrusted or invalid certificate, a server without TLS or an unsupported url scheme) are never
+// transient, even though they are reported as network errors.
+func IsTransient(err error) bool {
+	if isMisconfigured(err) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) || IsUndelivered(err)
+}
+
+// isMisconfigured returns true, iff the given error is caused by the configuration of the client or the server: the
+// server certificate is not trusted or invalid, the server does not speak TLS or the url has an unsupported scheme.
+func isMisconfigured(err error) bool {
+	var (
+		unknownAuthorityErr x509.UnknownAuthorityError
+		invalidErr          x509.CertificateInvalidError
+		hostnameErr         x509.HostnameError
+		rootsErr            x509.SystemRootsError
+		recordHeaderErr     tls.RecordHeaderError
+	)
+	if errors.As(err, &unknownAuthorityErr) || errors.As(err, &invalidErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &rootsErr) || errors.As(err, &recordHeaderErr) {
+		return true
+	}
+	// net/http reports these failures as plain errors.
+	return err != nil && (strings.Contains(err.Error(), "unsupported protocol scheme") ||
+		strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"))
+}
+
+// IsUndelivered returns true, iff the given error proves that the request did not reach the server (i.e. the
+// connection could not be established). Only such requests are safe to repeat for endpoints that are not idempotent.
+func IsUndelivered(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// failover keeps track of the host requests are sent to and repeats failed requests.
+type failover struct {
+	policy RetryPolicy
+	hosts  int
+	sleep  func(time.Duration)
+
+	mu      sync.Mutex
+	current int
+	rng     *rand.Rand
+}
+
+func newFailover(policy RetryPolicy, hosts int) *failover {
+	return &failover{
+		policy: policy,
+		hosts:  hosts,
+		sleep:  time.Sleep,
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Current returns the index of the host requests are currently sent to.
+func (f *failover) Current() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.current
+}
+
+// fail switches to the next host, if the given host is still the current one.
+func (f *failover) fail(host int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.current == host {
+		f.current = (host + 1) % f.hosts
+	}
+}
+
+func (f *failover) backoff(retry int) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.policy.Backoff(retry, f.rng)
+}
+
+// Do calls the given function with the index of the current host until it succeeds or the error is permanent. Errors
+// of idempotent requests are retried, if they are transient. Errors of other requests are only retried, if the request
+// was undelivered, and wrapped in an AmbiguousError, if they are transient otherwise.
+func (f *failover) Do(idempotent bool, call func(host int) error) error {
+	attempts := f.policy.MaxAttempts
+	if attempts < f.hosts {
+		attempts = f.hosts
+	}
+	for attempt := 1; ; attempt++ {
+		host := f.Current()
+		err := call(host)
+		if err == nil {
+			return nil
+		}
+		switch {
+		case IsUndelivered(err), idempotent && IsTransient(err):
+		case IsTransient(err):
+			return &AmbiguousError{Err: err}
+		default:
+			return err
+		}
+		if attempt >= attempts {
+			return err
+		}
+		f.fail(host)
+		f.sleep(f.backoff(attempt))
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"perun.network/perun-cardano-backend/transport"
+	pkgtest "polycry.pt/poly-go/test"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetries = transport.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Multiplier:     2,
+}
+
+// flakyServer returns a server that responds with the given status code to the first `failures` requests and with
+// 200 OK afterwards. The returned counter holds the number of received requests.
+func flakyServer(status int, failures int32) (*httptest.Server, *int32) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= failures {
+			http.Error(w, "failure", status)
+			return
+		}
+		_, _ = w.Write([]byte("true"))
+	}))
+	return server, &count
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	policy := transport.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	require.Equal(t, 100*time.Millisecond, policy.Backoff(1, rng))
+	require.Equal(t, 300*time.Millisecond, policy.Backoff(2, rng))
+	require.Equal(t, 900*time.Millisecond, policy.Backoff(3, rng))
+	require.Equal(t, time.Second, policy.Backoff(4, rng), "backoff should be capped")
+	require.Equal(t, time.Second, policy.Backoff(100, rng), "backoff should be capped")
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff := policy.Backoff(1, rng)
+		require.GreaterOrEqual(t, backoff, 50*time.Millisecond, "jitter is too large")
+		require.LessOrEqual(t, backoff, 150*time.Millisecond, "jitter is too large")
+	}
+}
+
+func TestConfig_Call_Retry(t *testing.T) {
+	server, count := flakyServer(http.StatusBadGateway, 2)
+	defer server.Close()
+	config := transport.NewConfig(transport.WithRetryPolicy(fastRetries))
+	body, err := config.Call("test server", []string{server.URL}, "/verify", true, nil)
+	require.NoError(t, err, "idempotent request was not retried")
+	require.Equal(t, "true", string(body))
+	require.EqualValues(t, 3, atomic.LoadInt32(count), "unexpected number of attempts")
+
+	server, count = flakyServer(http.StatusBadGateway, 3)
+	defer server.Close()
+	_, err = config.Call("test server", []string{server.URL}, "/verify", true, nil)
+	require.Error(t, err, "request succeeded after MaxAttempts")
+	require.EqualValues(t, 3, atomic.LoadInt32(count), "request was attempted more than MaxAttempts times")
+}
+
+func TestConfig_Call_NoDoubleSubmission(t *testing.T) {
+	server, count := flakyServer(http.StatusGatewayTimeout, 1)
+	defer server.Close()
+	config := transport.NewConfig(transport.WithRetryPolicy(fastRetries))
+	_, err := config.Call("test server", []string{server.URL}, "/fund", false, nil)
+	var ambiguous *transport.AmbiguousError
+	require.True(t, errors.As(err, &ambiguous), "ambiguous failure is not an AmbiguousError")
+	var statusErr *transport.StatusError
+	require.True(t, errors.As(err, &statusErr), "ambiguous failure does not wrap the StatusError")
+	require.Equal(t, http.StatusGatewayTimeout, statusErr.StatusCode)
+	require.Equal(t, "/fund", statusErr.Endpoint)
+	require.EqualValues(t, 1, atomic.LoadInt32(count), "request to non-idempotent endpoint was repeated")
+
+	server, count = flakyServer(http.StatusBadRequest, 1)
+	defer server.Close()
+	_, err = config.Call("test server", []string{server.URL}, "/verify", true, nil)
+	require.True(t, errors.As(err, &statusErr), "permanent failure is not a StatusError")
+	require.False(t, transport.IsTransient(err), "400 Bad Request should not be transient")
+	require.EqualValues(t, 1, atomic.LoadInt32(count), "request with permanent failure was repeated")
+}
+
+func TestConfig_Call_Failover(t *testing.T) {
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	server, count := flakyServer(http.StatusOK, 0)
+	defer server.Close()
+	config := transport.NewConfig(
+		transport.WithRetryPolicy(transport.NoRetry),
+		transport.WithFailoverHosts(server.URL),
+	)
+	hosts := append([]string{unreachable.URL}, config.FailoverHosts()...)
+	_, err := config.Call("test server", hosts, "/fund", false, nil)
+	require.NoError(t, err, "undelivered request was not failed over")
+	require.Equal(t, 1, config.CurrentHost(), "failover did not switch the current host")
+	_, err = config.Call("test server", hosts, "/fund", false, nil)
+	require.NoError(t, err, "request to failover host failed")
+	require.EqualValues(t, 2, atomic.LoadInt32(count), "requests should stick to the failover host")
+}
+
+func TestIsTransient_Misconfiguration(t *testing.T) {
+	var requests int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	})
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+	config := transport.NewConfig(transport.WithRetryPolicy(fastRetries))
+
+	for name, baseURL := range map[string]string{
+		"untrusted certificate":       tlsServer.URL,
+		"server without tls":          "https" + strings.TrimPrefix(plainServer.URL, "http"),
+		"unsupported protocol scheme": "ftp" + strings.TrimPrefix(plainServer.URL, "http"),
+	} {
+		_, err := config.Call("test server", []string{baseURL}, "/fund", false, nil)
+		require.Errorf(t, err, "request to server with %s succeeded", name)
+		require.Falsef(t, transport.IsTransient(err), "%s should not be transient", name)
+		var ambiguous *transport.AmbiguousError
+		require.Falsef(t, errors.As(err, &ambiguous), "%s should not be ambiguous", name)
+	}
+	require.Zero(t, atomic.LoadInt32(&requests), "misconfigured request reached the server")
+
+	for _, err := range []error{
+		x509.UnknownAuthorityError{},
+		x509.CertificateInvalidError{Reason: x509.Expired},
+		x509.HostnameError{Host: "pab.example.com"},
+		tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"},
+	} {
+		require.Falsef(t, transport.IsTransient(&url.Error{Op: "Post", URL: "https://pab.example.com", Err: err}),
+			"%T should not be transient", err)
+	}
+	require.True(t, transport.IsTransient(&url.Error{Op: "Post", URL: "https://pab.example.com", Err: timeoutError{}}),
+		"timeout should be transient")
+}
+
+// timeoutError is a net.Error reporting a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,397 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the timeout of requests to endpoints without a configured timeout.
+const DefaultTimeout = 30 * time.Second
+
+// Config configures how the wallet server and the PAB server are reached. It holds a single http.Client, so all
+// requests made with the same Config share one connection pool.
+type Config struct {
+	tlsConfig        *tls.Config
+	auth             Authenticator
+	timeout          time.Duration
+	endpointTimeouts []endpointTimeout
+	client           *http.Client
+	retryPolicy      RetryPolicy
+	failoverHosts    []string
+	failover         *failover
+	recorder         *Recorder
+	replayer         *Replayer
+}
+
+type endpointTimeout struct {
+	pattern string
+	timeout time.Duration
+}
+
+// Option configures a Config.
+type Option func(*Config)
+
+// WithTLSConfig sets the TLS configuration used for https and wss connections. It is cloned before use.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Config) {
+		c.tlsConfig = tlsConfig.Clone()
+	}
+}
+
+// WithRootCAs sets the certificate authorities that are trusted for server certificates (e.g. the CA of a private
+// gateway). See LoadCertPool.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Config) {
+		c.ensureTLSConfig().RootCAs = pool
+	}
+}
+
+// WithClientCertificate sets the certificate presented to the server for mutual TLS. See tls.LoadX509KeyPair.
+func WithClientCertificate(certificate tls.Certificate) Option {
+	return func(c *Config) {
+		tlsConfig := c.ensureTLSConfig()
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+}
+
+// WithAuthenticator sets the Authenticator that authenticates every request.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *Config) {
+		c.auth = auth
+	}
+}
+
+// WithBearerToken authenticates every request with the given bearer token.
+func WithBearerToken(token string) Option {
+	return WithAuthenticator(BearerToken(token))
+}
+
+// WithHMAC authenticates every request with an HMAC-SHA256 signature of the request under the given secret.
+func WithHMAC(keyID string, secret []byte) Option {
+	return WithAuthenticator(HMACAuth{KeyID: keyID, Secret: secret})
+}
+
+// WithTimeout sets the timeout of requests to endpoints without an endpoint specific timeout. A timeout of zero
+// disables the timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithEndpointTimeout sets the timeout of requests to endpoints matching the given pattern (see path.Match). This
+// allows to configure timeouts for endpoints with parameters (e.g. "/api/contract/instance/*/endpoint/fund").
+// Patterns are matched in the order they were configured.
+func WithEndpointTimeout(pattern string, timeout time.Duration) Option {
+	return func(c *Config) {
+		c.endpointTimeouts = append(c.endpointTimeouts, endpointTimeout{pattern: pattern, timeout: timeout})
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests. TLS options are ignored for requests, if a client is set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.client = client
+	}
+}
+
+// NewConfig returns a new Config with the given options applied.
+func NewConfig(opts ...Option) *Config {
+	c := &Config{timeout: DefaultTimeout, retryPolicy: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.failover = newFailover(c.retryPolicy, 1+len(c.failoverHosts))
+	if c.client == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = c.tlsConfig
+		t.MaxIdleConnsPerHost = t.MaxIdleConns
+		c.client = &http.Client{Transport: t}
+	}
+	return c
+}
+
+// LoadCertPool returns a certificate pool holding the PEM encoded certificates of the given files.
+func LoadCertPool(files ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read certificate file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", file)
+		}
+	}
+	return pool, nil
+}
+
+func (c *Config) ensureTLSConfig() *tls.Config {
+	if c.tlsConfig == nil {
+		c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return c.tlsConfig
+}
+
+// UsesTLS returns true, iff TLS options were configured.
+func (c *Config) UsesTLS() bool {
+	return c.tlsConfig != nil
+}
+
+// TLSConfig returns a clone of the TLS configuration or nil, if no TLS options were configured. This allows to secure
+// connections of other protocols (e.g. gRPC) with the same options.
+func (c *Config) TLSConfig() *tls.Config {
+	return c.tlsConfig.Clone()
+}
+
+// Authenticator returns the Authenticator of requests or nil, if requests are not authenticated.
+func (c *Config) Authenticator() Authenticator {
+	return c.auth
+}
+
+// Timeout returns the timeout of requests to the given endpoint.
+func (c *Config) Timeout(endpoint string) time.Duration {
+	for _, t := range c.endpointTimeouts {
+		if ok, _ := path.Match(t.pattern, endpoint); ok {
+			return t.timeout
+		}
+	}
+	return c.timeout
+}
+
+// Response is the response of the server to a request.
+type Response struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// Post sends the given json body to the given endpoint of the server with the given base url. Reading the response is
+// subject to the timeout of the endpoint as well. A non-2xx status code is not an error. Failures to send the request
+// or read the response are returned as NetworkError.
+func (c *Config) Post(baseUrl string, endpoint string, jsonBody []byte) (Response, error) {
+	return c.send(http.MethodPost, baseUrl, endpoint, jsonBody)
+}
+
+// Get queries the given endpoint of the server with the given base url. Like Post, a non-2xx status code is not an
+// error.
+func (c *Config) Get(baseUrl string, endpoint string) (Response, error) {
+	return c.send(http.MethodGet, baseUrl, endpoint, nil)
+}
+
+// send issues a request with the given method and json body (if any) to the given endpoint. The request is recorded,
+// if a Recorder is set, and answered by the Replayer instead, if one is set.
+func (c *Config) send(method string, baseUrl string, endpoint string, jsonBody []byte) (Response, error) {
+	if c.replayer != nil {
+		return c.replayer.replayRequest(method, baseUrl, endpoint, jsonBody)
+	}
+	response, err := c.do(method, baseUrl, endpoint, jsonBody)
+	if c.recorder != nil {
+		c.recorder.recordRequest(method, endpoint, jsonBody, response, err)
+	}
+	return response, err
+}
+
+// do issues a request with the given method and json body (if any) to the given endpoint of the server.
+func (c *Config) do(method string, baseUrl string, endpoint string, jsonBody []byte) (Response, error) {
+	ctx := context.Background()
+	if timeout := c.Timeout(endpoint); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var reader io.Reader
+	if jsonBody != nil {
+		reader = bytes.NewReader(jsonBody)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, baseUrl+endpoint, reader)
+	if err != nil {
+		return Response{}, fmt.Errorf("unable to prepare http request: %w", err)
+	}
+	if jsonBody != nil {
+		request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	}
+	if err = c.authenticate(request, jsonBody); err != nil {
+		return Response{}, err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return Response{}, &NetworkError{Endpoint: endpoint, URL: baseUrl + endpoint, Err: err}
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return Response{}, &NetworkError{
+			Endpoint: endpoint,
+			URL:      baseUrl + endpoint,
+			Err:      fmt.Errorf("unable to read server response: %w", err),
+		}
+	}
+	return Response{StatusCode: response.StatusCode, Status: response.Status, Body: body}, nil
+}
+
+// Call sends the given json body to the given endpoint of the current one of the given base urls (the primary address
+// followed by the FailoverHosts) and returns the body of the 200 OK response. Failed requests are retried and failed
+// over to the next base url according to the RetryPolicy. Requests to endpoints that are not idempotent are only
+// repeated, if they did not reach the server (see AmbiguousError). Other status codes are returned as StatusError
+// with the given server name.
+func (c *Config) Call(server string, baseUrls []string, endpoint string, idempotent bool, jsonBody []byte) ([]byte, error) {
+	return c.call(server, http.MethodPost, baseUrls, endpoint, idempotent, jsonBody)
+}
+
+// Query is like Call, but issues a GET request to the given endpoint. Queries are always considered idempotent.
+func (c *Config) Query(server string, baseUrls []string, endpoint string) ([]byte, error) {
+	return c.call(server, http.MethodGet, baseUrls, endpoint, true, nil)
+}
+
+func (c *Config) call(server string, method string, baseUrls []string, endpoint string, idempotent bool, jsonBody []byte) ([]byte, error) {
+	var body []byte
+	err := c.failover.Do(idempotent, func(host int) error {
+		response, err := c.send(method, baseUrls[host], endpoint, jsonBody)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusOK {
+			return &StatusError{
+				Server:     server,
+				Endpoint:   endpoint,
+				StatusCode: response.StatusCode,
+				Status:     response.Status,
+				Body:       response.Body,
+			}
+		}
+		body = response.Body
+		return nil
+	})
+	return body, err
+}
+
+// FailoverHosts returns the addresses of the failover hosts (see WithFailoverHosts).
+func (c *Config) FailoverHosts() []string {
+	return append([]string{}, c.failoverHosts...)
+}
+
+// CurrentHost returns the index of the base url requests are currently sent to (see Call).
+func (c *Config) CurrentHost() int {
+	return c.failover.Current()
+}
+
+// Conn is a websocket connection to a server. It is implemented by *websocket.Conn and by the connections of a
+// Recorder and a Replayer.
+type Conn interface {
+	// ReadMessage returns the type and content of the next message.
+	ReadMessage() (messageType int, p []byte, err error)
+	// ReadJSON decodes the next message into v.
+	ReadJSON(v interface{}) error
+	// Close closes the connection.
+	Close() error
+}
+
+// Dial opens a websocket connection to the given url of the given server. The handshake is authenticated like any
+// other request and subject to the timeout of the given endpoint. Rejected handshakes are returned as StatusError and
+// other failures as NetworkError. Like requests, connections are recorded or replayed, if a Recorder or Replayer is
+// set.
+func (c *Config) Dial(server string, url string, endpoint string) (Conn, error) {
+	if c.replayer != nil {
+		return c.replayer.replayDial(server, url, endpoint)
+	}
+	conn, err := c.dial(server, url, endpoint)
+	if c.recorder != nil {
+		return c.recorder.recordDial(endpoint, conn, err)
+	}
+	return conn, err
+}
+
+// dial opens a websocket connection to the given url of the given server.
+func (c *Config) dial(server string, url string, endpoint string) (Conn, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare websocket handshake: %w", err)
+	}
+	if err = c.authenticate(request, nil); err != nil {
+		return nil, err
+	}
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: c.Timeout(endpoint),
+		TLSClientConfig:  c.tlsConfig,
+	}
+	conn, response, err := dialer.Dial(url, request.Header)
+	if err != nil {
+		if response != nil {
+			body, _ := io.ReadAll(response.Body)
+			return nil, &StatusError{
+				Server:     server,
+				Endpoint:   endpoint,
+				StatusCode: response.StatusCode,
+				Status:     response.Status,
+				Body:       body,
+			}
+		}
+		return nil, &NetworkError{Endpoint: endpoint, URL: url, Err: err}
+	}
+	return conn, nil
+}
+
+func (c *Config) authenticate(request *http.Request, body []byte) error {
+	if c.auth == nil {
+		return nil
+	}
+	if err := c.auth.Authenticate(request, body); err != nil {
+		return fmt.Errorf("unable to authenticate request: %w", err)
+	}
+	return nil
+}
+
+// BaseURLs returns the http(s) and the corresponding ws(s) base url of the server at the given address. The address is
+// either a url with scheme http or https, or of the form "host:port", in which case https is used, iff the Config
+// uses TLS.
+func (c *Config) BaseURLs(addr string) (*url.URL, *url.URL, error) {
+	if !strings.Contains(addr, "://") {
+		scheme := "http://"
+		if c.UsesTLS() {
+			scheme = "https://"
+		}
+		addr = scheme + addr
+	}
+	httpUrl, err := url.Parse(strings.TrimSuffix(addr, "/"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse url: %w", err)
+	}
+	wsUrl := *httpUrl
+	switch httpUrl.Scheme {
+	case "http":
+		wsUrl.Scheme = "ws"
+	case "https":
+		wsUrl.Scheme = "wss"
+	default:
+		return nil, nil, errors.New("url scheme must be http or https")
+	}
+	return httpUrl, &wsUrl, nil
+}
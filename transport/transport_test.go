@@ -0,0 +1,187 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"perun.network/perun-cardano-backend/transport"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeClientCertificate returns a self-signed client certificate and a pool holding it.
+func makeClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "this should not fail!")
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "perun client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err, "this should not fail!")
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "this should not fail!")
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	_, _ = w.Write(body)
+}
+
+func TestConfig_TLS(t *testing.T) {
+	clientCert, clientCAs := makeClientCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(echoHandler))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	config := transport.NewConfig(transport.WithRootCAs(rootCAs), transport.WithClientCertificate(clientCert))
+	response, err := config.Post(server.URL, "/echo", []byte(`"hello"`))
+	require.NoError(t, err, "unable to reach server with mutual TLS")
+	require.Equal(t, http.StatusOK, response.StatusCode, "unexpected status code")
+	require.Equal(t, `"hello"`, string(response.Body), "unexpected response body")
+
+	_, err = transport.NewConfig(transport.WithRootCAs(rootCAs)).Post(server.URL, "/echo", nil)
+	require.Error(t, err, "reached mutual TLS server without client certificate")
+	_, err = transport.NewConfig(transport.WithClientCertificate(clientCert)).Post(server.URL, "/echo", nil)
+	require.Error(t, err, "trusted server certificate of unknown CA")
+}
+
+func TestConfig_Authentication(t *testing.T) {
+	secret := []byte("secret")
+	auth := transport.HMACAuth{KeyID: "ops", Secret: secret}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") == "Bearer token" {
+			return
+		}
+		if err := auth.Verify(r, body, time.Minute); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	response, err := transport.NewConfig().Post(server.URL, "/", nil)
+	require.NoError(t, err, "unable to send request")
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode, "unauthenticated request was accepted")
+
+	response, err = transport.NewConfig(transport.WithBearerToken("token")).Post(server.URL, "/", nil)
+	require.NoError(t, err, "unable to send request")
+	require.Equal(t, http.StatusOK, response.StatusCode, "request with bearer token was rejected")
+
+	response, err = transport.NewConfig(transport.WithHMAC("ops", secret)).Post(server.URL, "/sign", []byte("{}"))
+	require.NoError(t, err, "unable to send request")
+	require.Equal(t, http.StatusOK, response.StatusCode, "request with valid HMAC was rejected")
+
+	response, err = transport.NewConfig(transport.WithHMAC("ops", []byte("wrong"))).Post(server.URL, "/sign", nil)
+	require.NoError(t, err, "unable to send request")
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode, "request with invalid HMAC was accepted")
+
+	stale := transport.HMACAuth{KeyID: "ops", Secret: secret, Now: func() time.Time {
+		return time.Now().Add(-time.Hour)
+	}}
+	response, err = transport.NewConfig(transport.WithAuthenticator(stale)).Post(server.URL, "/sign", nil)
+	require.NoError(t, err, "unable to send request")
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode, "request with stale HMAC was accepted")
+}
+
+func TestConfig_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/slow") {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+	config := transport.NewConfig(
+		transport.WithTimeout(time.Second),
+		transport.WithEndpointTimeout("/slow/*", 20*time.Millisecond),
+	)
+	require.Equal(t, time.Second, config.Timeout("/fast"), "unexpected default timeout")
+	require.Equal(t, 20*time.Millisecond, config.Timeout("/slow/endpoint"), "unexpected endpoint timeout")
+	_, err := config.Post(server.URL, "/slow/endpoint", nil)
+	require.Error(t, err, "failed to time out")
+	_, err = config.Post(server.URL, "/fast", nil)
+	require.NoError(t, err, "request without endpoint timeout timed out")
+}
+
+func TestConfig_BaseURLs(t *testing.T) {
+	httpUrl, wsUrl, err := transport.NewConfig().BaseURLs("localhost:9080")
+	require.NoError(t, err, "unable to parse host")
+	require.Equal(t, "http://localhost:9080", httpUrl.String())
+	require.Equal(t, "ws://localhost:9080", wsUrl.String())
+
+	httpUrl, wsUrl, err = transport.NewConfig(transport.WithRootCAs(x509.NewCertPool())).BaseURLs("localhost:9080")
+	require.NoError(t, err, "unable to parse host")
+	require.Equal(t, "https://localhost:9080", httpUrl.String())
+	require.Equal(t, "wss://localhost:9080", wsUrl.String())
+
+	httpUrl, wsUrl, err = transport.NewConfig().BaseURLs("https://pab.example.com/")
+	require.NoError(t, err, "unable to parse url")
+	require.Equal(t, "https://pab.example.com", httpUrl.String())
+	require.Equal(t, "wss://pab.example.com", wsUrl.String())
+
+	_, _, err = transport.NewConfig().BaseURLs("ftp://pab.example.com")
+	require.Error(t, err, "failed to error on invalid scheme")
+}
+
+func TestConfig_Dial(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("hello"))
+	}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/instance"
+
+	_, err := transport.NewConfig().Dial("test server", url, "/ws/instance")
+	require.Error(t, err, "unauthenticated websocket handshake was accepted")
+	conn, err := transport.NewConfig(transport.WithBearerToken("token")).Dial("test server", url, "/ws/instance")
+	require.NoError(t, err, "unable to establish authenticated websocket connection")
+	defer conn.Close()
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err, "unable to read from websocket connection")
+	require.Equal(t, "hello", string(msg))
+}
@@ -27,9 +27,13 @@ type RemoteAccount struct {
 	AccountAddress  address.Address
 	walletServer    Remote
 	cardanoWalletID string
+	// lock is set for accounts unlocked by a RemoteWallet and refuses signing once the account is locked.
+	lock *accountLock
+	// schemes decodes the signatures returned by the wallet server.
+	schemes SignatureSchemes
 }
 
-// MakeRemoteAccount returns a new RemoteAccount instance.
+// MakeRemoteAccount returns a new RemoteAccount instance. The returned account is never locked.
 func MakeRemoteAccount(addr address.Address, r Remote, id string) RemoteAccount {
 	return RemoteAccount{
 		AccountAddress:  addr,
@@ -38,45 +42,127 @@ func MakeRemoteAccount(addr address.Address, r Remote, id string) RemoteAccount
 	}
 }
 
+// WithSignatureSchemes returns a copy of this RemoteAccount that decodes signatures with the given signature schemes.
+// By default, only the built-in schemes are supported (see SignatureSchemes).
+func (a RemoteAccount) WithSignatureSchemes(schemes SignatureSchemes) RemoteAccount {
+	a.schemes = schemes
+	return a
+}
+
 func (a RemoteAccount) GetCardanoWalletID() string {
 	return a.cardanoWalletID
 }
 
+// GetWalletServer returns the wallet server that holds the secrets of this account.
+func (a RemoteAccount) GetWalletServer() Remote {
+	return a.walletServer
+}
+
 // Address returns the Address associated with this account.
 func (a RemoteAccount) Address() wallet.Address {
 	return &a.AccountAddress
 }
 
-// SignData signs arbitrary data with this account.
+// SignData signs arbitrary data with this account. For multi-signature accounts, the returned signature is a
+// multi-signature of the threshold of signing keys available in the wallet server.
 func (a RemoteAccount) SignData(data []byte) (wallet.Sig, error) {
-	request := wire.MakeSigningRequest(a.AccountAddress, data)
+	if a.lock.isLocked() {
+		return nil, &AccountLockedError{Address: a.AccountAddress}
+	}
+	if a.AccountAddress.IsMultiSig() {
+		return a.signMultiSig(func(signer address.Address) (wallet.Sig, error) {
+			return a.signData(signer, data)
+		})
+	}
+	return a.signData(a.AccountAddress, data)
+}
+
+func (a RemoteAccount) signData(signer address.Address, data []byte) (wallet.Sig, error) {
+	request := wire.MakeSigningRequest(signer, data)
 	var response wire.SigningResponse
 	err := a.walletServer.CallEndpoint(EndpointSignData, request, &response)
 	if err != nil {
 		return nil, fmt.Errorf("wallet server could not sign message: %w", err)
 	}
-	// Extract and decode the signature from SigningResponse.
-	sig, err := response.Decode()
-	if err != nil {
-		return nil, fmt.Errorf("unable to decode signature from SignatureResponse: %w", err)
-	}
-	return sig, nil
+	return decodeSigningResponse(a.schemes, signer, response)
 }
 
-// SignChannelState signs the given channel state with this account.
+// SignChannelState signs the given channel state with this account. For multi-signature accounts, the returned
+// signature is a multi-signature of the threshold of signing keys available in the wallet server.
 func (a RemoteAccount) SignChannelState(channelState types.ChannelState) (wallet.Sig, error) {
-	request := wire.MakeChannelStateSigningRequest(a.AccountAddress, channelState)
+	if a.lock.isLocked() {
+		return nil, &AccountLockedError{Address: a.AccountAddress}
+	}
+	if a.AccountAddress.IsMultiSig() {
+		return a.signMultiSig(func(signer address.Address) (wallet.Sig, error) {
+			return a.signChannelState(signer, channelState)
+		})
+	}
+	return a.signChannelState(a.AccountAddress, channelState)
+}
+
+func (a RemoteAccount) signChannelState(signer address.Address, channelState types.ChannelState) (wallet.Sig, error) {
+	request := wire.MakeChannelStateSigningRequest(signer, channelState)
 	var response wire.SigningResponse
 	err := a.walletServer.CallEndpoint(EndpointSignChannelState, request, &response)
 	if err != nil {
 		return nil, fmt.Errorf("wallet server could not sign channel state: %w", err)
 	}
-	// Extract and decode the signature from SigningResponse.
-	sig, err := response.Decode()
+	return decodeSigningResponse(a.schemes, signer, response)
+}
+
+// decodeSigningResponse extracts and decodes the signature of the given signer from the given SigningResponse with the
+// given signature schemes. Signatures of signature schemes other than address.Ed25519 are wrapped (see MakeSchemeSig).
+func decodeSigningResponse(
+	schemes SignatureSchemes,
+	signer address.Address,
+	response wire.SigningResponse,
+) (wallet.Sig, error) {
+	scheme, err := schemes.Get(signer.GetSignatureScheme())
+	if err != nil {
+		return nil, err
+	}
+	sig, err := response.DecodeWithLength(scheme.SignatureLength())
 	if err != nil {
 		return nil, fmt.Errorf("unable to decode signature from SignatureResponse: %w", err)
 	}
-	return sig, nil
+	if scheme.ID() == address.Ed25519 {
+		return sig, nil
+	}
+	return MakeSchemeSig(scheme.ID(), sig)
+}
+
+// signMultiSig calls sign for the signing keys of this multi-signature account that are available in the wallet
+// server until the threshold is reached and combines the resulting signatures into a multi-signature.
+func (a RemoteAccount) signMultiSig(sign func(signer address.Address) (wallet.Sig, error)) (wallet.Sig, error) {
+	threshold := a.AccountAddress.GetSignatureThreshold()
+	components := make([]MultiSigComponent, 0, threshold)
+	for i, key := range a.AccountAddress.GetSigningPubKeys() {
+		if len(components) == threshold {
+			break
+		}
+		signer := address.MakeAddressFromPubKeyByteArray(key)
+		available, err := keyAvailable(a.walletServer, signer)
+		if err != nil {
+			return nil, err
+		}
+		if !available {
+			continue
+		}
+		sig, err := sign(signer)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, MultiSigComponent{KeyIndex: uint8(i), Signature: sig})
+	}
+	if len(components) < threshold {
+		return nil, &KeyUnavailableError{
+			Address:   a.AccountAddress,
+			Available: len(components),
+			Required:  threshold,
+		}
+	}
+	return MakeMultiSig(components)
 }
 
 var _ wallet.Account = RemoteAccount{}
@@ -15,7 +15,12 @@
 package wallet_test
 
 import (
+	"crypto/ed25519"
 	"github.com/stretchr/testify/require"
+	gpwallet "perun.network/go-perun/wallet"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wallet/test"
 	pkgtest "polycry.pt/poly-go/test"
 	"testing"
@@ -46,3 +51,65 @@ func TestRemoteAccount_SignChannelState(t *testing.T) {
 	require.NoError(t, err, "unable to sign valid channel state")
 	require.Equal(t, r.MockSignature, actualSignature, "signature is wrong")
 }
+
+// dataOnlyAccount hides all methods of the wrapped account except those of wallet.Account.
+type dataOnlyAccount struct {
+	gpwallet.Account
+}
+
+func TestDataSigningAccount(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	r := test.NewGenericRemote([]address.Address{addr}, rng)
+	backend := wallet.MakeRemoteBackend(r)
+	state := ctest.MakeRandomChannelState(rng)
+
+	_, err := backend.ToChannelStateSigningAccount(nil)
+	require.Error(t, err, "converted nil account")
+	remoteAccount := test.MakeRemoteAccount(addr, r)
+	acc, err := backend.ToChannelStateSigningAccount(remoteAccount)
+	require.NoError(t, err)
+	require.Equal(t, remoteAccount, acc, "channel state signing account must not be wrapped")
+
+	acc, err = backend.ToChannelStateSigningAccount(dataOnlyAccount{remoteAccount})
+	require.NoError(t, err)
+	require.IsType(t, wallet.DataSigningAccount{}, acc)
+	sig, err := acc.SignChannelState(state)
+	require.NoError(t, err)
+	valid, err := backend.VerifyChannelStateSignature(state, sig, &addr)
+	require.NoError(t, err)
+	require.True(t, valid, "signature of data signing account is invalid")
+	valid, err = backend.VerifyChannelStateSignature(ctest.MakeRandomChannelState(rng), sig, &addr)
+	require.NoError(t, err)
+	require.False(t, valid, "signature of data signing account is valid for another channel state")
+}
+
+// ed25519Account is a wallet.Account holding an Ed25519 key locally.
+type ed25519Account struct {
+	addr address.Address
+	key  ed25519.PrivateKey
+}
+
+func (a ed25519Account) Address() gpwallet.Address {
+	return &a.addr
+}
+
+func (a ed25519Account) SignData(data []byte) (gpwallet.Sig, error) {
+	return ed25519.Sign(a.key, data), nil
+}
+
+func TestDataSigningAccount_WalletServer(t *testing.T) {
+	backend := wallet.MakeRemoteBackend(wallet.NewPerunCardanoWallet(test.WalletServerURL(t)))
+	rng := pkgtest.Prng(t)
+	pub, priv, err := ed25519.GenerateKey(rng)
+	require.NoError(t, err)
+	addr, err := address.MakeAddressFromPubKeyByteSlice(pub)
+	require.NoError(t, err)
+	acc := wallet.MakeDataSigningAccount(ed25519Account{addr: addr, key: priv})
+	state := ctest.MakeRandomChannelState(rng)
+	sig, err := acc.SignChannelState(state)
+	require.NoError(t, err)
+	valid, err := backend.VerifyChannelStateSignature(state, sig, &addr)
+	require.NoError(t, err)
+	require.True(t, valid, "wallet server does not verify channel state signature of data signing account")
+}
@@ -16,8 +16,9 @@ package address
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/btcsuite/btcutil/bech32"
 	"perun.network/go-perun/wallet"
 	"perun.network/perun-cardano-backend/blake2b224"
 )
@@ -30,10 +31,27 @@ const PubKeyHashLength = blake2b224.Size224
 
 const AddressLength = PubKeyLength + PubKeyHashLength
 
+// AddressWithStakeCredentialLength is the length of the byte representation of an Address with a public key hash
+// payment credential, a single signing key and a stake credential.
+const AddressWithStakeCredentialLength = AddressLength + 1 + CredentialHashLength
+
+// MaxMultiSigKeys is the maximum number of signing keys of a multi-signature Address.
+const MaxMultiSigKeys = 0xff
+
+// Flags of the extended binary encoding of an Address (see MarshalBinary).
+const (
+	flagStakeCredential byte = 1 << iota
+	flagStakeScript
+	flagPaymentScript
+	flagMultiSig
+	flagSignatureScheme
+	flagNetwork
+	flagsAll = flagStakeCredential | flagStakeScript | flagPaymentScript | flagMultiSig | flagSignatureScheme |
+		flagNetwork
+)
+
 const MainnetIdentifier = "addr"
 const TestnetIdentifier = "addr_test"
-const mainnetIdentifierByte byte = 0x61
-const testnetIdentifierByte byte = 0x60
 
 // Address carries a public key that represents the public verification key part of a Cardano `ed25519` keypair.
 type Address struct {
@@ -42,6 +60,28 @@ type Address struct {
 	// this address is supposed to receive payments).
 	pubKey            [PubKeyLength]byte
 	paymentPubKeyHash [PubKeyHashLength]byte
+	// paymentCredentialType specifies whether paymentPubKeyHash is a public key hash or a script hash (e.g. of a
+	// native multi-signature script).
+	paymentCredentialType CredentialType
+	// The optional stake credential of the payout address. If it is set, the payout address is the base address of
+	// paymentPubKeyHash and stakeCredential, otherwise the enterprise address of paymentPubKeyHash. Channel payouts
+	// only go to the base address with a contract that supports payout addresses; the deployed contract pays out to
+	// the enterprise address.
+	stakeCredential    Credential
+	hasStakeCredential bool
+	// multiSigPubKeys holds the signing keys of a participant whose signatures are valid, iff at least
+	// multiSigThreshold of these keys signed. It is nil for single key addresses. For multi-signature addresses,
+	// pubKey is always the first of these keys.
+	multiSigPubKeys   [][PubKeyLength]byte
+	multiSigThreshold int
+	// signatureScheme is the scheme under which signatures are verified with pubKey. pubKeyPrefix is the first byte of
+	// 33 byte public keys (e.g. compressed secp256k1 keys) and zero for 32 byte public keys.
+	signatureScheme SignatureScheme
+	pubKeyPrefix    byte
+	// network is the network of the payout address in the text encoding of this address (see String). It defaults to
+	// Mainnet, iff hasNetwork is false.
+	network    Network
+	hasNetwork bool
 }
 
 // MakeAddressFromSinglePubKey creates an Address from a single public key. This means the payment and signing public
@@ -59,8 +99,69 @@ func MakeAddressFromSinglePubKey(pubKey []byte) (Address, error) {
 	return a, nil
 }
 
+// MakeMultiSigAddress creates an Address for a participant whose signatures are valid, iff at least threshold of the
+// given Ed25519 public keys signed. The payment credential is not set, use SetPaymentCredential to set it (usually to the hash
+// of the native script of the participant's multi-signature wallet).
+func MakeMultiSigAddress(threshold int, pubKeys [][PubKeyLength]byte) (Address, error) {
+	if len(pubKeys) == 0 || len(pubKeys) > MaxMultiSigKeys {
+		return Address{}, fmt.Errorf("invalid number of multi-signature keys: %d", len(pubKeys))
+	}
+	if threshold < 1 || threshold > len(pubKeys) {
+		return Address{}, fmt.Errorf("invalid multi-signature threshold %d for %d keys", threshold, len(pubKeys))
+	}
+	for i := range pubKeys {
+		for j := i + 1; j < len(pubKeys); j++ {
+			if pubKeys[i] == pubKeys[j] {
+				return Address{}, fmt.Errorf("duplicate multi-signature key at index %d and %d", i, j)
+			}
+		}
+	}
+	a := Address{
+		pubKey:            pubKeys[0],
+		multiSigPubKeys:   append([][PubKeyLength]byte{}, pubKeys...),
+		multiSigThreshold: threshold,
+	}
+	return a, nil
+}
+
+// SetPaymentPubKeyHash sets the payment credential to the given public key hash.
 func (a *Address) SetPaymentPubKeyHash(paymentPubKeyHash [PubKeyHashLength]byte) {
 	copy(a.paymentPubKeyHash[:], paymentPubKeyHash[:])
+	a.paymentCredentialType = KeyHashCredential
+}
+
+// SetPaymentCredential sets the payment credential to the given public key hash or script hash credential.
+func (a *Address) SetPaymentCredential(paymentCredential Credential) {
+	a.paymentPubKeyHash = paymentCredential.Hash
+	a.paymentCredentialType = paymentCredential.Type
+}
+
+// GetPaymentCredential returns the payment credential of this address.
+func (a Address) GetPaymentCredential() Credential {
+	return Credential{Type: a.paymentCredentialType, Hash: a.paymentPubKeyHash}
+}
+
+// IsMultiSig returns true, iff signatures of this address are multi-signatures of its signing keys.
+func (a Address) IsMultiSig() bool {
+	return a.multiSigPubKeys != nil
+}
+
+// GetSigningPubKeys returns the public keys under which signatures of this address are verified. This is the single
+// public key for single key addresses.
+func (a Address) GetSigningPubKeys() [][PubKeyLength]byte {
+	if !a.IsMultiSig() {
+		return [][PubKeyLength]byte{a.pubKey}
+	}
+	return append([][PubKeyLength]byte{}, a.multiSigPubKeys...)
+}
+
+// GetSignatureThreshold returns the number of signing keys that need to sign for a signature of this address to be
+// valid. This is 1 for single key addresses.
+func (a Address) GetSignatureThreshold() int {
+	if !a.IsMultiSig() {
+		return 1
+	}
+	return a.multiSigThreshold
 }
 
 func (a *Address) SetPaymentPubKeyHashFromSlice(paymentPubKeyHash []byte) error {
@@ -72,6 +173,7 @@ func (a *Address) SetPaymentPubKeyHashFromSlice(paymentPubKeyHash []byte) error
 		)
 	}
 	copy(a.paymentPubKeyHash[:], paymentPubKeyHash)
+	a.paymentCredentialType = KeyHashCredential
 	return nil
 }
 
@@ -83,6 +185,56 @@ func (a *Address) SetPaymentPubKeyHashFromHexString(paymentPubKeyHash string) er
 	return a.SetPaymentPubKeyHashFromSlice(paymentPubKeyHashBytes)
 }
 
+// SetPaymentPubKeyHashFromBech32String sets the payment public key hash to the payment credential of the given bech32
+// encoded address (e.g. `addr1...`). The address must carry a payment credential which is a public key hash.
+func (a *Address) SetPaymentPubKeyHashFromBech32String(paymentAddress string) error {
+	decoded, err := ParseCardanoAddress(paymentAddress)
+	if err != nil {
+		return err
+	}
+	if decoded.Kind == RewardAddress {
+		return errors.New("reward addresses can not receive payments")
+	}
+	if decoded.PaymentCredential.Type != KeyHashCredential {
+		return errors.New("payment credential of address is not a public key hash")
+	}
+	a.SetPaymentPubKeyHash(decoded.PaymentCredential.Hash)
+	return nil
+}
+
+// SetPaymentAddress sets the payout address of this Address to the given CardanoAddress. The address must be a base
+// or enterprise address. The stake credential of a base address is kept, an enterprise address removes any previously
+// set stake credential.
+func (a *Address) SetPaymentAddress(paymentAddress CardanoAddress) error {
+	if paymentAddress.Kind != BaseAddress && paymentAddress.Kind != EnterpriseAddress {
+		return errors.New("payout address must be a base or enterprise address")
+	}
+	a.SetPaymentCredential(paymentAddress.PaymentCredential)
+	if paymentAddress.Kind == BaseAddress {
+		a.SetStakeCredential(paymentAddress.StakeCredential)
+	} else {
+		a.RemoveStakeCredential()
+	}
+	return nil
+}
+
+// SetStakeCredential sets the stake credential of the payout address.
+func (a *Address) SetStakeCredential(stakeCredential Credential) {
+	a.stakeCredential = stakeCredential
+	a.hasStakeCredential = true
+}
+
+// RemoveStakeCredential removes the stake credential of the payout address.
+func (a *Address) RemoveStakeCredential() {
+	a.stakeCredential = Credential{}
+	a.hasStakeCredential = false
+}
+
+// GetStakeCredential returns the stake credential of the payout address and true, or false if there is none.
+func (a Address) GetStakeCredential() (Credential, bool) {
+	return a.stakeCredential, a.hasStakeCredential
+}
+
 // MakeAddressFromPubKeyByteArray returns a new Address for the given public key bytes.
 // Note: This does not set the public key hash!
 func MakeAddressFromPubKeyByteArray(pubKey [PubKeyLength]byte) Address {
@@ -102,7 +254,16 @@ func MakeAddressFromPubKeyByteSlice(pubKey []byte) (Address, error) {
 	return addr, nil
 }
 
-// GetPubKey returns the public key of this address.
+// schemePubKey returns the public key of this address with the given prefix, if it is non-zero.
+func (a Address) schemePubKey(prefix byte) []byte {
+	if prefix != 0 {
+		return append([]byte{prefix}, a.pubKey[:]...)
+	}
+	return a.pubKey[:]
+}
+
+// GetPubKey returns the public key of this address. For 33 byte public keys (see GetSchemePubKey), this omits the
+// first byte.
 func (a Address) GetPubKey() [PubKeyLength]byte {
 	return a.pubKey
 }
@@ -113,27 +274,212 @@ func (a Address) GetPubKeySlice() []byte {
 }
 
 // MarshalBinary decodes this address into its byte representation.
-// The returned byte slice has length PubKeyLength + PubKeyHashLength.
+// Mainnet addresses with a single signing key, a public key hash payment credential and no stake credential are
+// encoded as the public key followed by the public key hash (AddressLength bytes). All other addresses append a flags
+// byte followed by the network (if not Mainnet), the stake credential hash (if any) and the multi-signature threshold,
+// key count and keys (if any).
 func (a Address) MarshalBinary() ([]byte, error) {
-	return append(a.pubKey[:], a.paymentPubKeyHash[:]...), nil
+	data := append(a.pubKey[:], a.paymentPubKeyHash[:]...)
+	var flags byte
+	if a.hasStakeCredential {
+		flags |= flagStakeCredential
+		if a.stakeCredential.Type == ScriptHashCredential {
+			flags |= flagStakeScript
+		}
+	}
+	if a.paymentCredentialType == ScriptHashCredential {
+		flags |= flagPaymentScript
+	}
+	if a.IsMultiSig() {
+		flags |= flagMultiSig
+	}
+	if a.signatureScheme != Ed25519 || a.pubKeyPrefix != 0 {
+		flags |= flagSignatureScheme
+	}
+	if a.GetNetwork() != Mainnet {
+		flags |= flagNetwork
+	}
+	if flags == 0 {
+		return data, nil
+	}
+	data = append(data, flags)
+	if flags&flagNetwork != 0 {
+		data = append(data, byte(a.network))
+	}
+	if a.hasStakeCredential {
+		data = append(data, a.stakeCredential.Hash[:]...)
+	}
+	if a.IsMultiSig() {
+		data = append(data, byte(a.multiSigThreshold), byte(len(a.multiSigPubKeys)))
+		for _, k := range a.multiSigPubKeys {
+			data = append(data, k[:]...)
+		}
+	}
+	if flags&flagSignatureScheme != 0 {
+		data = append(data, byte(a.signatureScheme), a.pubKeyPrefix)
+	}
+	return data, nil
 }
 
-// UnmarshalBinary expects a byte slice of length PubKeyLength + PubKeyHashLength and decodes it into the
-// receiver Address.
+// UnmarshalBinary decodes the byte representation of an address as returned by MarshalBinary into the receiver
+// Address. Extended encodings end with the signature scheme and public key prefix, iff the address does not use a
+// 32 byte Ed25519 public key.
 func (a *Address) UnmarshalBinary(data []byte) error {
-	if len(data) != AddressLength {
-		return fmt.Errorf("public key has incorrect length. expected: %d bytes actual: %d bytes",
+	if len(data) < AddressLength {
+		return fmt.Errorf("address has incorrect length. expected at least: %d bytes actual: %d bytes",
 			AddressLength,
 			len(data))
 	}
-	copy(a.pubKey[:], data[:PubKeyLength])
-	copy(a.paymentPubKeyHash[:], data[PubKeyLength:])
+	decoded := Address{}
+	copy(decoded.pubKey[:], data[:PubKeyLength])
+	copy(decoded.paymentPubKeyHash[:], data[PubKeyLength:AddressLength])
+	if len(data) == AddressLength {
+		*a = decoded
+		return nil
+	}
+	flags := data[AddressLength]
+	rest := data[AddressLength+1:]
+	if flags == 0 || flags&^flagsAll != 0 || (flags&flagStakeScript != 0 && flags&flagStakeCredential == 0) ||
+		(flags&flagMultiSig != 0 && flags&flagSignatureScheme != 0) {
+		return fmt.Errorf("invalid address flags: %05b", flags)
+	}
+	if flags&flagSignatureScheme != 0 {
+		if len(rest) < 2 {
+			return errors.New("address is too short for signature scheme")
+		}
+		schemeData := rest[len(rest)-2:]
+		rest = rest[:len(rest)-2]
+		schemeAddr, err := MakeAddressFromSchemePubKey(
+			SignatureScheme(schemeData[0]),
+			decoded.schemePubKey(schemeData[1]),
+		)
+		if err != nil {
+			return err
+		}
+		if schemeAddr.signatureScheme == Ed25519 && schemeAddr.pubKeyPrefix == 0 {
+			return errors.New("non-canonical address encoding of Ed25519 public key")
+		}
+		decoded.signatureScheme = schemeAddr.signatureScheme
+		decoded.pubKeyPrefix = schemeAddr.pubKeyPrefix
+	}
+	if flags&flagNetwork != 0 {
+		if len(rest) < 1 {
+			return errors.New("address is too short for network")
+		}
+		network := Network(rest[0])
+		if network == Mainnet || network > 0x0f {
+			return fmt.Errorf("invalid network in address encoding: %d", network)
+		}
+		decoded.SetNetwork(network)
+		rest = rest[1:]
+	}
+	if flags&flagPaymentScript != 0 {
+		decoded.paymentCredentialType = ScriptHashCredential
+	}
+	if flags&flagStakeCredential != 0 {
+		if len(rest) < CredentialHashLength {
+			return errors.New("address is too short for stake credential")
+		}
+		decoded.SetStakeCredential(makeCredential(flags&flagStakeScript != 0, rest[:CredentialHashLength]))
+		rest = rest[CredentialHashLength:]
+	}
+	if flags&flagMultiSig != 0 {
+		if len(rest) < 2 {
+			return errors.New("address is too short for multi-signature policy")
+		}
+		threshold, n := int(rest[0]), int(rest[1])
+		rest = rest[2:]
+		if len(rest) != n*PubKeyLength {
+			return fmt.Errorf("multi-signature keys have incorrect length. expected: %d bytes actual: %d bytes",
+				n*PubKeyLength,
+				len(rest))
+		}
+		keys := make([][PubKeyLength]byte, n)
+		for i := range keys {
+			copy(keys[i][:], rest[i*PubKeyLength:])
+		}
+		multiSig, err := MakeMultiSigAddress(threshold, keys)
+		if err != nil {
+			return err
+		}
+		if multiSig.pubKey != decoded.pubKey {
+			return errors.New("public key of multi-signature address is not its first signing key")
+		}
+		decoded.multiSigPubKeys = multiSig.multiSigPubKeys
+		decoded.multiSigThreshold = multiSig.multiSigThreshold
+		rest = nil
+	}
+	if len(rest) != 0 {
+		return fmt.Errorf("address has %d trailing bytes", len(rest))
+	}
+	*a = decoded
 	return nil
 }
 
-// String returns the public key as hex string.
+// SetNetwork sets the network of the payout address in the text encoding of this address.
+func (a *Address) SetNetwork(network Network) {
+	a.network = network
+	a.hasNetwork = true
+}
+
+// GetNetwork returns the network of the payout address in the text encoding of this address. This is Mainnet, unless
+// it was set by SetNetwork or by decoding a testnet address.
+func (a Address) GetNetwork() Network {
+	if !a.hasNetwork {
+		return Mainnet
+	}
+	return a.network
+}
+
+// String returns the bech32 encoding of the payout address (see GetPaymentAddress) on the network of this address
+// (i.e. `addr1...` or `addr_test1...`).
 func (a Address) String() string {
-	return hex.EncodeToString(a.pubKey[:])
+	return a.GetPaymentAddress(a.GetNetwork()).String()
+}
+
+// MarshalText returns the bech32 encoding of the payout address of this address on its network. The text encoding
+// does not carry the public key under which signatures are verified, use MarshalBinary to transmit the full address.
+func (a Address) MarshalText() ([]byte, error) {
+	return a.GetPaymentAddress(a.GetNetwork()).MarshalText()
+}
+
+// UnmarshalText decodes a bech32 encoded base or enterprise address into the payout address and network of the
+// receiver Address. The public key of the receiver is kept, because it can not be derived from the payout address. An
+// error is returned, if the receiver has no public key (e.g. it is the zero Address), because the decoded address
+// could not verify signatures.
+func (a *Address) UnmarshalText(text []byte) error {
+	if a.pubKey == ([PubKeyLength]byte{}) {
+		return errors.New("text encoding of address does not carry a public key, decode into an address with a key")
+	}
+	decoded, err := ParseCardanoAddress(string(text))
+	if err != nil {
+		return err
+	}
+	if err = a.SetPaymentAddress(decoded); err != nil {
+		return err
+	}
+	a.SetNetwork(decoded.Network)
+	return nil
+}
+
+// MarshalJSON encodes this address as a json string holding the bech32 encoding of its payout address (see
+// MarshalText).
+func (a Address) MarshalJSON() ([]byte, error) {
+	text, err := a.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes a json string holding a bech32 encoded base or enterprise address into the payout address of
+// the receiver Address. Like UnmarshalText, it requires the receiver to have a public key.
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return a.UnmarshalText([]byte(text))
 }
 
 // GetTestnetAddressOfPubKey returns the testnet address string representation of this addresses' public key
@@ -143,7 +489,7 @@ func (a Address) GetTestnetAddressOfPubKey() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("unable to compute blake2b hash of public key: %w", err)
 	}
-	return convertToAddress(hash, testnetIdentifierByte, TestnetIdentifier)
+	return MakeEnterpriseAddress(Testnet, MakeKeyHashCredential(hash)).Encode()
 }
 
 // GetMainnetAddressOfPubKey returns the mainnet address string representation of this addresses' public key
@@ -153,37 +499,32 @@ func (a Address) GetMainnetAddressOfPubKey() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("unable to compute blake2b hash of public key: %w", err)
 	}
-	return convertToAddress(hash, mainnetIdentifierByte, MainnetIdentifier)
+	return MakeEnterpriseAddress(Mainnet, MakeKeyHashCredential(hash)).Encode()
 }
 
 // GetTestnetAddressOfPubKeyHash returns the testnet address string representation of this addresses' payment public key
-// hash (i.e. `addr1...`).
+// hash and stake credential, if any (i.e. `addr_test1...`).
 func (a Address) GetTestnetAddressOfPubKeyHash() (string, error) {
-	return convertToAddress(a.paymentPubKeyHash, testnetIdentifierByte, TestnetIdentifier)
+	return a.GetPaymentAddress(Testnet).Encode()
 }
 
 // GetMainnetAddressOfPubKeyHash returns the mainnet address string representation of this addresses' payment public key
-// hash (i.e. `addr1...`).
+// hash and stake credential, if any (i.e. `addr1...`).
 func (a Address) GetMainnetAddressOfPubKeyHash() (string, error) {
-	return convertToAddress(a.paymentPubKeyHash, mainnetIdentifierByte, MainnetIdentifier)
+	return a.GetPaymentAddress(Mainnet).Encode()
 }
 
-// convertToAddress returns the address string for given network parameters.
-func convertToAddress(hash [PubKeyHashLength]byte, networkIdentifierByte byte, networkIdentifierString string) (string, error) {
-
-	// Bech32-encode the PubKeyHash.
-	conv, err := bech32.ConvertBits(append([]byte{networkIdentifierByte}, hash[:]...), 8, 5, true)
-	if err != nil {
-		return "", fmt.Errorf("unable to convert bits for bech32 encoding: %w", err)
-	}
-	encodedHash, err := bech32.Encode(networkIdentifierString, conv)
-	if err != nil {
-		return "", fmt.Errorf("unable bech32-encode: %w", err)
+// GetPaymentAddress returns the CardanoAddress under which this address receives payments on the given network. This is
+// a base address, iff this address carries a stake credential and an enterprise address otherwise.
+func (a Address) GetPaymentAddress(network Network) CardanoAddress {
+	if a.hasStakeCredential {
+		return MakeBaseAddress(network, a.GetPaymentCredential(), a.stakeCredential)
 	}
-	return encodedHash, nil
+	return MakeEnterpriseAddress(network, a.GetPaymentCredential())
 }
 
-// GetPubKeyHash returns the public key hash associated with payments to this address.
+// GetPubKeyHash returns the hash of the payment credential of this address. This is the public key hash associated
+// with payments to this address, or a script hash if the payment credential is a script (see GetPaymentCredential).
 func (a Address) GetPubKeyHash() [PubKeyHashLength]byte {
 	return a.paymentPubKeyHash
 }
@@ -196,13 +537,29 @@ func CalculatePubKeyHash(pubKey [PubKeyLength]byte) ([PubKeyHashLength]byte, err
 	return blake2b224.Sum224(pubKey[:])
 }
 
-// Equal returns true, iff the given address is of type Address and their public keys are equal.
+// Equal returns true, iff the given address is of type Address and their public keys, signature schemes, payment
+// credentials, stake credentials and multi-signature policies are equal. The network is not compared.
 func (a Address) Equal(other wallet.Address) bool {
 	otherAddress, ok := other.(*Address)
 	if !ok {
 		return false
 	}
-	return a.pubKey == otherAddress.pubKey && a.paymentPubKeyHash == otherAddress.paymentPubKeyHash
+	if len(a.multiSigPubKeys) != len(otherAddress.multiSigPubKeys) ||
+		a.IsMultiSig() != otherAddress.IsMultiSig() ||
+		a.multiSigThreshold != otherAddress.multiSigThreshold {
+		return false
+	}
+	for i, k := range a.multiSigPubKeys {
+		if k != otherAddress.multiSigPubKeys[i] {
+			return false
+		}
+	}
+	return a.pubKey == otherAddress.pubKey &&
+		a.signatureScheme == otherAddress.signatureScheme &&
+		a.pubKeyPrefix == otherAddress.pubKeyPrefix &&
+		a.GetPaymentCredential() == otherAddress.GetPaymentCredential() &&
+		a.hasStakeCredential == otherAddress.hasStakeCredential &&
+		a.stakeCredential == otherAddress.stakeCredential
 }
 
 var _ wallet.Address = (*Address)(nil)
@@ -16,6 +16,7 @@ package address_test
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"github.com/stretchr/testify/require"
 	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wallet/test"
@@ -76,12 +77,80 @@ func TestAddress_UnmarshalBinary(t *testing.T) {
 func TestAddress_String(t *testing.T) {
 	rng := pkgtest.Prng(t)
 	uut := test.MakeRandomAddress(rng)
-	require.Equal(
-		t,
-		hex.EncodeToString(uut.GetPubKeySlice()),
-		uut.String(),
-		"wrong string representation for public key",
-	)
+	expected, err := uut.GetMainnetAddressOfPubKeyHash()
+	require.NoError(t, err)
+	require.Equal(t, expected, uut.String(), "string representation is not the mainnet payout address")
+
+	uut.SetNetwork(address.Testnet)
+	expected, err = uut.GetTestnetAddressOfPubKeyHash()
+	require.NoError(t, err)
+	require.Equal(t, expected, uut.String(), "string representation is not the testnet payout address")
+
+	withStake := test.MakeRandomAddressWithStakeCredential(rng)
+	expected, err = withStake.GetMainnetAddressOfPubKeyHash()
+	require.NoError(t, err)
+	require.Equal(t, expected, withStake.String(), "string representation is not the base payout address")
+}
+
+func TestAddress_JSON(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		expected := test.MakeRandomAddressWithStakeCredential(rng)
+		if rng.Intn(2) == 0 {
+			expected.RemoveStakeCredential()
+		}
+		network := address.Network(rng.Intn(2))
+		expected.SetNetwork(network)
+		data, err := json.Marshal(expected)
+		require.NoError(t, err, "unable to marshal valid address to json")
+		require.Equal(t, `"`+expected.String()+`"`, string(data), "json encoding is not the bech32 string")
+
+		// The text encoding does not carry the public key, so it is decoded into an address with the same key.
+		actual := address.MakeAddressFromPubKeyByteArray(expected.GetPubKey())
+		require.NoError(t, json.Unmarshal(data, &actual), "unable to unmarshal valid address from json")
+		require.True(t, expected.Equal(&actual), "json decoded address is not as expected")
+		require.Equal(t, network, actual.GetNetwork())
+	}
+
+	var withoutKey address.Address
+	data, err := json.Marshal(test.MakeRandomAddress(rng))
+	require.NoError(t, err)
+	require.Error(t, json.Unmarshal(data, &withoutKey), "failed to reject decoding into an address without public key")
+
+	reward := test.MakeRandomAddress(rng)
+	stake := test.MakeRandomCredential(rng)
+	text, err := address.MakeRewardAddress(address.Mainnet, stake).MarshalText()
+	require.NoError(t, err)
+	require.Error(t, reward.UnmarshalText(text), "failed to reject reward address as payout address")
+}
+
+func TestAddress_Network(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		expected := test.MakeRandomAddressWithStakeCredential(rng)
+		if rng.Intn(2) == 0 {
+			expected.RemoveStakeCredential()
+		}
+		network := address.Network(rng.Intn(2))
+		expected.SetNetwork(network)
+		data, err := expected.MarshalBinary()
+		require.NoError(t, err)
+		var actual address.Address
+		require.NoError(t, actual.UnmarshalBinary(data), "unable to unmarshal valid address bytes")
+		require.True(t, expected.Equal(&actual), "binary decoded address is not as expected")
+		require.Equal(t, network, actual.GetNetwork(), "network was lost in binary encoding")
+		require.Equal(t, expected.String(), actual.String())
+	}
+
+	// The network is only encoded, if it is not Mainnet.
+	mainnet := test.MakeRandomAddress(rng)
+	mainnet.SetNetwork(address.Mainnet)
+	data, err := mainnet.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, address.AddressLength)
+	var decoded address.Address
+	require.Error(t, decoded.UnmarshalBinary(append(data, 1<<5, byte(address.Mainnet))),
+		"failed to reject non-canonical mainnet encoding")
 }
 
 func TestAddress_Equal(t *testing.T) {
@@ -153,3 +222,122 @@ func TestAddress_Calculate(t *testing.T) {
 	require.NoErrorf(t, err, "unexpected error when deriving PubKeyHash from address")
 	require.Equal(t, expected, actual[:], "PubKeyHash is not as expected")
 }
+
+func TestAddress_StakeCredential(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	uut := test.MakeRandomAddressWithStakeCredential(rng)
+	stake, ok := uut.GetStakeCredential()
+	require.True(t, ok, "address should carry a stake credential")
+
+	data, err := uut.MarshalBinary()
+	require.NoError(t, err, "unable to marshal valid address")
+	require.Len(t, data, address.AddressWithStakeCredentialLength, "marshalled address has wrong length")
+	decoded := address.Address{}
+	require.NoError(t, decoded.UnmarshalBinary(data), "unable to unmarshal valid address bytes")
+	require.Equal(t, uut, decoded, "unmarshalled address is not as expected")
+
+	paymentAddress := uut.GetPaymentAddress(address.Mainnet)
+	require.Equal(t, address.BaseAddress, paymentAddress.Kind, "payment address should be a base address")
+	require.Equal(t, stake, paymentAddress.StakeCredential, "payment address has wrong stake credential")
+	require.Equal(t, uut.GetPubKeyHash(), paymentAddress.PaymentCredential.Hash, "payment address has wrong payment credential")
+
+	withoutStake := uut
+	withoutStake.RemoveStakeCredential()
+	require.False(t, uut.Equal(&withoutStake), "addresses with different stake credentials should not be equal")
+	require.Equal(t, address.EnterpriseAddress, withoutStake.GetPaymentAddress(address.Mainnet).Kind,
+		"payment address without stake credential should be an enterprise address")
+
+	require.NoError(t, withoutStake.SetPaymentAddress(paymentAddress), "unable to set base payment address")
+	require.True(t, uut.Equal(&withoutStake), "setting a base address should restore the stake credential")
+
+	invalidType := append(data[:address.AddressLength:address.AddressLength], 0x02)
+	invalidType = append(invalidType, data[address.AddressLength+1:]...)
+	require.Error(t, decoded.UnmarshalBinary(invalidType), "failed to error on invalid stake credential type")
+}
+
+func TestAddress_MultiSig(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		uut := test.MakeRandomMultiSigAddress(rng, 10)
+		if rng.Intn(2) == 0 {
+			uut.SetStakeCredential(test.MakeRandomCredential(rng))
+		}
+		require.True(t, uut.IsMultiSig(), "multi-signature address is not recognized")
+		require.Equal(t, uut.GetSigningPubKeys()[0], uut.GetPubKey(), "public key should be the first signing key")
+		data, err := uut.MarshalBinary()
+		require.NoError(t, err, "unable to marshal valid address")
+		decoded := address.Address{}
+		require.NoError(t, decoded.UnmarshalBinary(data), "unable to unmarshal valid address bytes")
+		require.Equal(t, uut, decoded, "unmarshalled address is not as expected")
+		require.True(t, uut.Equal(&decoded), "unmarshalled address is not equal")
+		require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]), "failed to error on truncated address")
+
+		single := address.MakeAddressFromPubKeyByteArray(uut.GetPubKey())
+		single.SetPaymentCredential(uut.GetPaymentCredential())
+		if stake, ok := uut.GetStakeCredential(); ok {
+			single.SetStakeCredential(stake)
+		}
+		require.False(t, uut.Equal(&single), "multi-signature address should not equal single key address")
+	}
+
+	key := test.MakeRandomAddress(rng).GetPubKey()
+	_, err := address.MakeMultiSigAddress(1, nil)
+	require.Error(t, err, "failed to error on empty key set")
+	_, err = address.MakeMultiSigAddress(2, [][address.PubKeyLength]byte{key})
+	require.Error(t, err, "failed to error on threshold larger than key set")
+	_, err = address.MakeMultiSigAddress(0, [][address.PubKeyLength]byte{key})
+	require.Error(t, err, "failed to error on zero threshold")
+	_, err = address.MakeMultiSigAddress(1, [][address.PubKeyLength]byte{key, key})
+	require.Error(t, err, "failed to error on duplicate keys")
+}
+
+func TestAddress_SignatureScheme(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		uut := test.MakeRandomSchemeAddress(rng)
+		withStake := rng.Intn(2) == 0
+		if withStake {
+			uut.SetStakeCredential(test.MakeRandomCredential(rng))
+		}
+		data, err := uut.MarshalBinary()
+		require.NoError(t, err, "unable to marshal valid address")
+		if uut.GetSignatureScheme() == address.Ed25519 && !withStake {
+			require.Len(t, data, address.AddressLength, "Ed25519 address should use the legacy encoding")
+		}
+		decoded := address.Address{}
+		require.NoError(t, decoded.UnmarshalBinary(data), "unable to unmarshal valid address bytes")
+		require.Equal(t, uut, decoded, "unmarshalled address is not as expected")
+		require.Equal(t, uut.GetSchemePubKey(), decoded.GetSchemePubKey(), "public key of scheme is not as expected")
+	}
+
+	ecdsaKey := append([]byte{0x02}, test.GetRandomByteSlice(address.PubKeyLength, address.PubKeyLength, rng)...)
+	ecdsa, err := address.MakeAddressFromSchemePubKey(address.EcdsaSecp256k1, ecdsaKey)
+	require.NoError(t, err, "unable to create address from compressed public key")
+	require.Equal(t, ecdsaKey, ecdsa.GetSchemePubKey(), "public key of scheme is not as expected")
+	schnorr, err := address.MakeAddressFromSchemePubKey(address.SchnorrSecp256k1, ecdsaKey[1:])
+	require.NoError(t, err, "unable to create address from x-only public key")
+	require.False(t, ecdsa.Equal(&schnorr), "addresses of different signature schemes should not be equal")
+
+	_, err = address.MakeAddressFromSchemePubKey(address.EcdsaSecp256k1, ecdsaKey[1:])
+	require.Error(t, err, "failed to error on uncompressed ECDSA public key of wrong length")
+	_, err = address.MakeAddressFromSchemePubKey(address.SchnorrSecp256k1, ecdsaKey)
+	require.Error(t, err, "failed to error on Schnorr public key of wrong length")
+	_, err = address.MakeAddressFromSchemePubKey(address.EcdsaSecp256k1, append([]byte{0x04}, ecdsaKey[1:]...))
+	require.Error(t, err, "failed to error on invalid compressed public key prefix")
+
+	data, err := test.MakeRandomMultiSigAddress(rng, 3).MarshalBinary()
+	require.NoError(t, err, "this should not fail!")
+	data[address.AddressLength] |= 0x10
+	require.Error(t, (&address.Address{}).UnmarshalBinary(append(data, byte(address.EcdsaSecp256k1), 0x02)),
+		"failed to error on multi-signature address with signature scheme")
+
+	for _, s := range []address.SignatureScheme{address.Ed25519, address.EcdsaSecp256k1, address.SchnorrSecp256k1, 0x42} {
+		parsed, err := address.ParseSignatureScheme(s.String())
+		require.NoError(t, err, "unable to parse signature scheme name")
+		require.Equal(t, s, parsed, "parsed signature scheme is not as expected")
+	}
+	for _, name := range []string{"RSA", "SignatureScheme(0)", "SignatureScheme(066)", "SignatureScheme(66)x"} {
+		_, err = address.ParseSignatureScheme(name)
+		require.Error(t, err, "failed to error on unknown or non-canonical signature scheme name %s", name)
+	}
+}
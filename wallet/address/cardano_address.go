@@ -0,0 +1,412 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package address
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+// CredentialHashLength is the length of payment and stake credential hashes (key hashes and script hashes) in bytes.
+const CredentialHashLength = PubKeyHashLength
+
+const StakeMainnetIdentifier = "stake"
+const StakeTestnetIdentifier = "stake_test"
+
+// Network is the network tag encoded in the lower four bits of a CIP-19 address header.
+type Network byte
+
+const (
+	Testnet Network = 0
+	Mainnet Network = 1
+)
+
+// Kind is the kind of CIP-19 (Shelley) address.
+type Kind byte
+
+const (
+	// BaseAddress carries a payment credential and a stake credential.
+	BaseAddress Kind = iota
+	// PointerAddress carries a payment credential and a pointer to a stake registration certificate.
+	PointerAddress
+	// EnterpriseAddress carries only a payment credential.
+	EnterpriseAddress
+	// RewardAddress carries only a stake credential.
+	RewardAddress
+)
+
+// CredentialType specifies whether a Credential is the hash of a verification key or the hash of a script.
+type CredentialType byte
+
+const (
+	KeyHashCredential CredentialType = iota
+	ScriptHashCredential
+)
+
+// Credential is a payment or stake credential, i.e. a blake2b-224 key hash or script hash.
+type Credential struct {
+	Type CredentialType
+	Hash [CredentialHashLength]byte
+}
+
+// Pointer points to a stake registration certificate on-chain.
+type Pointer struct {
+	Slot      uint64
+	TxIndex   uint64
+	CertIndex uint64
+}
+
+// CardanoAddress is a Shelley address as specified in CIP-19. Depending on Kind, only some of the fields are
+// meaningful: PaymentCredential is unused for RewardAddress, StakeCredential is only used for BaseAddress and
+// RewardAddress and StakePointer is only used for PointerAddress.
+type CardanoAddress struct {
+	Kind              Kind
+	Network           Network
+	PaymentCredential Credential
+	StakeCredential   Credential
+	StakePointer      Pointer
+}
+
+const (
+	headerBase       byte = 0b0000
+	headerPointer    byte = 0b0100
+	headerEnterprise byte = 0b0110
+	headerReward     byte = 0b1110
+
+	// headerPaymentScript is set in the header type, iff the payment credential is a script hash.
+	headerPaymentScript byte = 0b0001
+	// headerStakeScript is set in the header type, iff the stake credential of a base address is a script hash.
+	headerStakeScript byte = 0b0010
+	// headerByron is the header type of legacy Byron addresses, which are not supported.
+	headerByron byte = 0b1000
+)
+
+// MakeKeyHashCredential returns a Credential for the given verification key hash.
+func MakeKeyHashCredential(hash [CredentialHashLength]byte) Credential {
+	return Credential{Type: KeyHashCredential, Hash: hash}
+}
+
+// MakeScriptHashCredential returns a Credential for the given script hash.
+func MakeScriptHashCredential(hash [CredentialHashLength]byte) Credential {
+	return Credential{Type: ScriptHashCredential, Hash: hash}
+}
+
+// MakeBaseAddress returns a new base address paying to the given payment credential and delegating to the given
+// stake credential.
+func MakeBaseAddress(network Network, payment Credential, stake Credential) CardanoAddress {
+	return CardanoAddress{
+		Kind:              BaseAddress,
+		Network:           network,
+		PaymentCredential: payment,
+		StakeCredential:   stake,
+	}
+}
+
+// MakePointerAddress returns a new pointer address paying to the given payment credential and delegating to the stake
+// credential registered in the certificate the given Pointer refers to.
+func MakePointerAddress(network Network, payment Credential, pointer Pointer) CardanoAddress {
+	return CardanoAddress{
+		Kind:              PointerAddress,
+		Network:           network,
+		PaymentCredential: payment,
+		StakePointer:      pointer,
+	}
+}
+
+// MakeEnterpriseAddress returns a new enterprise address paying to the given payment credential.
+func MakeEnterpriseAddress(network Network, payment Credential) CardanoAddress {
+	return CardanoAddress{
+		Kind:              EnterpriseAddress,
+		Network:           network,
+		PaymentCredential: payment,
+	}
+}
+
+// MakeRewardAddress returns a new reward (stake) address for the given stake credential.
+func MakeRewardAddress(network Network, stake Credential) CardanoAddress {
+	return CardanoAddress{
+		Kind:            RewardAddress,
+		Network:         network,
+		StakeCredential: stake,
+	}
+}
+
+// ParseCardanoAddress decodes a bech32 encoded CIP-19 address (i.e. `addr1...`, `addr_test1...`, `stake1...` or
+// `stake_test1...`).
+func ParseCardanoAddress(s string) (CardanoAddress, error) {
+	hrp, data, err := bech32.DecodeNoLimit(s)
+	if err != nil {
+		return CardanoAddress{}, fmt.Errorf("unable to bech32-decode address: %w", err)
+	}
+	raw, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return CardanoAddress{}, fmt.Errorf("unable to convert bits for bech32 decoding: %w", err)
+	}
+	var a CardanoAddress
+	if err = a.UnmarshalBinary(raw); err != nil {
+		return CardanoAddress{}, err
+	}
+	if expected := a.humanReadablePart(); hrp != expected {
+		return CardanoAddress{}, fmt.Errorf(
+			"address has incorrect human-readable part. expected: %s, actual: %s",
+			expected,
+			hrp,
+		)
+	}
+	return a, nil
+}
+
+// Encode returns the bech32 encoding of this address.
+func (a CardanoAddress) Encode() (string, error) {
+	raw, err := a.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	conv, err := bech32.ConvertBits(raw, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert bits for bech32 encoding: %w", err)
+	}
+	encoded, err := bech32.Encode(a.humanReadablePart(), conv)
+	if err != nil {
+		return "", fmt.Errorf("unable bech32-encode: %w", err)
+	}
+	return encoded, nil
+}
+
+// String returns the bech32 encoding of this address or the empty string if the address is malformed.
+func (a CardanoAddress) String() string {
+	s, err := a.Encode()
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// MarshalBinary returns the raw CIP-19 bytes of this address, i.e. the header byte followed by the credentials.
+func (a CardanoAddress) MarshalBinary() ([]byte, error) {
+	if a.Network > 0x0f {
+		return nil, fmt.Errorf("invalid network tag: %d", a.Network)
+	}
+	var header byte
+	data := make([]byte, 1, 1+2*CredentialHashLength)
+	switch a.Kind {
+	case BaseAddress:
+		header = headerBase | paymentScriptBit(a.PaymentCredential)
+		if a.StakeCredential.Type == ScriptHashCredential {
+			header |= headerStakeScript
+		}
+		data = append(data, a.PaymentCredential.Hash[:]...)
+		data = append(data, a.StakeCredential.Hash[:]...)
+	case PointerAddress:
+		header = headerPointer | paymentScriptBit(a.PaymentCredential)
+		data = append(data, a.PaymentCredential.Hash[:]...)
+		data = appendVarUint(data, a.StakePointer.Slot)
+		data = appendVarUint(data, a.StakePointer.TxIndex)
+		data = appendVarUint(data, a.StakePointer.CertIndex)
+	case EnterpriseAddress:
+		header = headerEnterprise | paymentScriptBit(a.PaymentCredential)
+		data = append(data, a.PaymentCredential.Hash[:]...)
+	case RewardAddress:
+		header = headerReward
+		if a.StakeCredential.Type == ScriptHashCredential {
+			header |= headerPaymentScript
+		}
+		data = append(data, a.StakeCredential.Hash[:]...)
+	default:
+		return nil, fmt.Errorf("invalid address kind: %d", a.Kind)
+	}
+	data[0] = header<<4 | byte(a.Network)
+	return data, nil
+}
+
+// UnmarshalBinary decodes the raw CIP-19 bytes of an address into the receiver CardanoAddress.
+func (a *CardanoAddress) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errors.New("address bytes must not be empty")
+	}
+	header := data[0] >> 4
+	network := Network(data[0] & 0x0f)
+	body := data[1:]
+	switch {
+	case header <= headerBase|headerPaymentScript|headerStakeScript:
+		if err := expectLength(body, 2*CredentialHashLength); err != nil {
+			return err
+		}
+		*a = MakeBaseAddress(
+			network,
+			makeCredential(header&headerPaymentScript != 0, body[:CredentialHashLength]),
+			makeCredential(header&headerStakeScript != 0, body[CredentialHashLength:]),
+		)
+	case header == headerPointer || header == headerPointer|headerPaymentScript:
+		if len(body) < CredentialHashLength {
+			return expectLength(body, CredentialHashLength)
+		}
+		pointer, err := decodePointer(body[CredentialHashLength:])
+		if err != nil {
+			return err
+		}
+		*a = MakePointerAddress(
+			network,
+			makeCredential(header&headerPaymentScript != 0, body[:CredentialHashLength]),
+			pointer,
+		)
+	case header == headerEnterprise || header == headerEnterprise|headerPaymentScript:
+		if err := expectLength(body, CredentialHashLength); err != nil {
+			return err
+		}
+		*a = MakeEnterpriseAddress(network, makeCredential(header&headerPaymentScript != 0, body))
+	case header == headerReward || header == headerReward|headerPaymentScript:
+		if err := expectLength(body, CredentialHashLength); err != nil {
+			return err
+		}
+		*a = MakeRewardAddress(network, makeCredential(header&headerPaymentScript != 0, body))
+	case header == headerByron:
+		return errors.New("byron addresses are not supported")
+	default:
+		return fmt.Errorf("invalid address header type: %04b", header)
+	}
+	return nil
+}
+
+// MarshalText returns the bech32 encoding of this address.
+func (a CardanoAddress) MarshalText() ([]byte, error) {
+	s, err := a.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText decodes a bech32 encoded address into the receiver CardanoAddress.
+func (a *CardanoAddress) UnmarshalText(text []byte) error {
+	decoded, err := ParseCardanoAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = decoded
+	return nil
+}
+
+// MarshalJSON encodes this address as a json string holding its bech32 encoding.
+func (a CardanoAddress) MarshalJSON() ([]byte, error) {
+	s, err := a.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes a json string holding a bech32 encoded address into the receiver CardanoAddress.
+func (a *CardanoAddress) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return a.UnmarshalText([]byte(s))
+}
+
+// HasStakeCredential returns true, iff this address carries a stake credential (i.e. it is a base or reward address).
+func (a CardanoAddress) HasStakeCredential() bool {
+	return a.Kind == BaseAddress || a.Kind == RewardAddress
+}
+
+// humanReadablePart returns the bech32 prefix for this address' kind and network.
+func (a CardanoAddress) humanReadablePart() string {
+	switch {
+	case a.Kind == RewardAddress && a.Network == Mainnet:
+		return StakeMainnetIdentifier
+	case a.Kind == RewardAddress:
+		return StakeTestnetIdentifier
+	case a.Network == Mainnet:
+		return MainnetIdentifier
+	default:
+		return TestnetIdentifier
+	}
+}
+
+func paymentScriptBit(c Credential) byte {
+	if c.Type == ScriptHashCredential {
+		return headerPaymentScript
+	}
+	return 0
+}
+
+func makeCredential(isScript bool, hash []byte) Credential {
+	c := Credential{Type: KeyHashCredential}
+	if isScript {
+		c.Type = ScriptHashCredential
+	}
+	copy(c.Hash[:], hash)
+	return c
+}
+
+func expectLength(body []byte, expected int) error {
+	if len(body) != expected {
+		return fmt.Errorf(
+			"address payload has incorrect length. expected: %d bytes actual: %d bytes",
+			expected,
+			len(body),
+		)
+	}
+	return nil
+}
+
+// appendVarUint appends the variable-length big-endian encoding used for pointers in CIP-19 (7 bits per byte, the
+// most significant bit marks continuation).
+func appendVarUint(data []byte, n uint64) []byte {
+	var buf [10]byte
+	i := len(buf) - 1
+	buf[i] = byte(n & 0x7f)
+	for n >>= 7; n > 0; n >>= 7 {
+		i--
+		buf[i] = byte(n&0x7f) | 0x80
+	}
+	return append(data, buf[i:]...)
+}
+
+// readVarUint reads one variable-length natural number from data and returns it together with the remaining bytes.
+func readVarUint(data []byte) (uint64, []byte, error) {
+	var n uint64
+	for i, b := range data {
+		if n > (1<<64-1)>>7 {
+			return 0, nil, errors.New("pointer value overflows uint64")
+		}
+		n = n<<7 | uint64(b&0x7f)
+		if b&0x80 == 0 {
+			return n, data[i+1:], nil
+		}
+	}
+	return 0, nil, errors.New("unexpected end of pointer")
+}
+
+func decodePointer(data []byte) (Pointer, error) {
+	var p Pointer
+	var err error
+	if p.Slot, data, err = readVarUint(data); err != nil {
+		return Pointer{}, err
+	}
+	if p.TxIndex, data, err = readVarUint(data); err != nil {
+		return Pointer{}, err
+	}
+	if p.CertIndex, data, err = readVarUint(data); err != nil {
+		return Pointer{}, err
+	}
+	if len(data) != 0 {
+		return Pointer{}, fmt.Errorf("pointer has %d trailing bytes", len(data))
+	}
+	return p, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package address_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+// Test vectors are taken from CIP-19.
+const (
+	cip19PaymentKeyHash = "9493315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+	cip19StakeKeyHash   = "337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251"
+	cip19ScriptHash     = "c37b1b5dc0669f1d3c61a6fddb2e8fde96be87b881c60bce8e8d542f"
+)
+
+func cip19Credential(t *testing.T, credentialType address.CredentialType, hexHash string) address.Credential {
+	hashBytes, err := hex.DecodeString(hexHash)
+	require.NoError(t, err, "this should not fail!")
+	var hash [address.CredentialHashLength]byte
+	copy(hash[:], hashBytes)
+	return address.Credential{Type: credentialType, Hash: hash}
+}
+
+func TestCardanoAddress_CIP19(t *testing.T) {
+	paymentKey := cip19Credential(t, address.KeyHashCredential, cip19PaymentKeyHash)
+	stakeKey := cip19Credential(t, address.KeyHashCredential, cip19StakeKeyHash)
+	script := cip19Credential(t, address.ScriptHashCredential, cip19ScriptHash)
+	pointer := address.Pointer{Slot: 2498243, TxIndex: 27, CertIndex: 3}
+
+	vectors := []struct {
+		name     string
+		address  address.CardanoAddress
+		expected string
+	}{
+		{
+			"type-0",
+			address.MakeBaseAddress(address.Mainnet, paymentKey, stakeKey),
+			"addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x",
+		},
+		{
+			"type-1",
+			address.MakeBaseAddress(address.Mainnet, script, stakeKey),
+			"addr1z8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gten0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs9yc0hh",
+		},
+		{
+			"type-2",
+			address.MakeBaseAddress(address.Mainnet, paymentKey, script),
+			"addr1yx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerkr0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shs2z78ve",
+		},
+		{
+			"type-3",
+			address.MakeBaseAddress(address.Mainnet, script, script),
+			"addr1x8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gt7r0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shskhj42g",
+		},
+		{
+			"type-4",
+			address.MakePointerAddress(address.Mainnet, paymentKey, pointer),
+			"addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k",
+		},
+		{
+			"type-5",
+			address.MakePointerAddress(address.Mainnet, script, pointer),
+			"addr128phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtupnz75xxcrtw79hu",
+		},
+		{
+			"type-6",
+			address.MakeEnterpriseAddress(address.Mainnet, paymentKey),
+			"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8",
+		},
+		{
+			"type-7",
+			address.MakeEnterpriseAddress(address.Mainnet, script),
+			"addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx",
+		},
+		{
+			"type-14",
+			address.MakeRewardAddress(address.Mainnet, stakeKey),
+			"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw",
+		},
+		{
+			"type-15",
+			address.MakeRewardAddress(address.Mainnet, script),
+			"stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5",
+		},
+		{
+			"type-0-testnet",
+			address.MakeBaseAddress(address.Testnet, paymentKey, stakeKey),
+			"addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs68faae",
+		},
+		{
+			"type-6-testnet",
+			address.MakeEnterpriseAddress(address.Testnet, paymentKey),
+			"addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz",
+		},
+		{
+			"type-14-testnet",
+			address.MakeRewardAddress(address.Testnet, stakeKey),
+			"stake_test1uqehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gssrtvn",
+		},
+	}
+	for _, v := range vectors {
+		v := v
+		t.Run(v.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := v.address.Encode()
+			require.NoError(t, err, "unexpected error when encoding address")
+			require.Equal(t, v.expected, actual, "address string is not as expected")
+
+			decoded, err := address.ParseCardanoAddress(v.expected)
+			require.NoError(t, err, "unable to parse valid address")
+			require.Equal(t, v.address, decoded, "decoded address is not as expected")
+		})
+	}
+}
+
+func TestCardanoAddress_ParseInvalid(t *testing.T) {
+	invalid := []struct {
+		name    string
+		address string
+	}{
+		{"empty", ""},
+		{"bad checksum", "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl9"},
+		{"mainnet hrp on testnet address", "addr1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz"},
+		{"payment hrp on reward address", "addr1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"},
+		// Byron address from CIP-19.
+		{"byron", "37btjrVyb4KDXBNC4haBVPCrro8AQPHwvCMp3RFhhSVWwfFmZ6wwzSK6JK1hY6wHNmtrpTf1kdbva8TCneM2YsiXT7mrzT21EacHnPpz5YyUdj64na"},
+	}
+	for _, v := range invalid {
+		_, err := address.ParseCardanoAddress(v.address)
+		require.Errorf(t, err, "failed to error when parsing invalid address: %s", v.name)
+	}
+}
+
+func TestCardanoAddress_UnmarshalBinaryInvalid(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	uut := address.CardanoAddress{}
+	require.Error(t, uut.UnmarshalBinary(nil), "failed to error on empty address bytes")
+	require.Error(
+		t,
+		uut.UnmarshalBinary(append([]byte{0x61}, test.GetRandomByteSlice(0, address.CredentialHashLength-1, rng)...)),
+		"failed to error on enterprise address with too few bytes",
+	)
+	require.Error(
+		t,
+		uut.UnmarshalBinary(append([]byte{0x01}, test.GetRandomByteSlice(0, 2*address.CredentialHashLength-1, rng)...)),
+		"failed to error on base address with too few bytes",
+	)
+	truncatedPointer := append([]byte{0x41}, test.GetRandomByteSlice(address.CredentialHashLength, address.CredentialHashLength, rng)...)
+	require.Error(
+		t,
+		uut.UnmarshalBinary(append(truncatedPointer, 0x81)),
+		"failed to error on pointer address with truncated pointer",
+	)
+}
+
+func TestCardanoAddress_MarshalBinary(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		expected := test.MakeRandomCardanoAddress(rng)
+		data, err := expected.MarshalBinary()
+		require.NoError(t, err, "unable to marshal valid address")
+		actual := address.CardanoAddress{}
+		require.NoError(t, actual.UnmarshalBinary(data), "unable to unmarshal valid address bytes")
+		require.Equal(t, expected, actual, "unmarshalled address is not as expected")
+	}
+}
+
+func TestCardanoAddress_JSON(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		expected := test.MakeRandomCardanoAddress(rng)
+		data, err := json.Marshal(expected)
+		require.NoError(t, err, "unable to marshal valid address to json")
+		require.Equal(t, `"`+expected.String()+`"`, string(data), "json encoding is not the bech32 string")
+		var actual address.CardanoAddress
+		require.NoError(t, json.Unmarshal(data, &actual), "unable to unmarshal valid address from json")
+		require.Equal(t, expected, actual, "json decoded address is not as expected")
+	}
+}
+
+func TestAddress_SetPaymentPubKeyHashFromBech32String(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	uut := test.MakeRandomAddress(rng)
+	const baseAddress = "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x"
+	require.NoError(t, uut.SetPaymentPubKeyHashFromBech32String(baseAddress), "unable to set payment address")
+	require.Equal(t, cip19PaymentKeyHash, hex.EncodeToString(uut.GetPubKeyHashSlice()), "payment public key hash is not as expected")
+
+	const scriptAddress = "addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx"
+	require.Error(t, uut.SetPaymentPubKeyHashFromBech32String(scriptAddress), "failed to error on script payment credential")
+	const rewardAddress = "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"
+	require.Error(t, uut.SetPaymentPubKeyHashFromBech32String(rewardAddress), "failed to error on reward address")
+}
@@ -0,0 +1,117 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package address
+
+import "fmt"
+
+// CompressedPubKeyLength is the length of a compressed secp256k1 public key (parity prefix followed by the
+// x-coordinate) in bytes.
+const CompressedPubKeyLength = PubKeyLength + 1
+
+// SignatureScheme identifies the signature scheme under which signatures of an Address are verified.
+type SignatureScheme byte
+
+const (
+	// Ed25519 is the native Cardano signature scheme. Public keys are 32 bytes long.
+	Ed25519 SignatureScheme = iota
+	// EcdsaSecp256k1 is ECDSA over secp256k1 as verified by the Plutus V2 builtin `verifyEcdsaSecp256k1Signature`.
+	// Public keys are 33 bytes long (compressed encoding).
+	EcdsaSecp256k1
+	// SchnorrSecp256k1 is BIP-340 Schnorr over secp256k1 as verified by the Plutus V2 builtin
+	// `verifySchnorrSecp256k1Signature`. Public keys are 32 bytes long (x-only encoding).
+	SchnorrSecp256k1
+)
+
+// String returns the name of the signature scheme.
+func (s SignatureScheme) String() string {
+	switch s {
+	case Ed25519:
+		return "Ed25519"
+	case EcdsaSecp256k1:
+		return "EcdsaSecp256k1"
+	case SchnorrSecp256k1:
+		return "SchnorrSecp256k1"
+	default:
+		return fmt.Sprintf("SignatureScheme(%d)", byte(s))
+	}
+}
+
+// ParseSignatureScheme returns the SignatureScheme with the given name as returned by SignatureScheme.String. Only
+// canonical names are accepted, e.g. "SignatureScheme(0)" is rejected in favour of "Ed25519".
+func ParseSignatureScheme(name string) (SignatureScheme, error) {
+	for s := Ed25519; s <= SchnorrSecp256k1; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	var s SignatureScheme
+	if _, err := fmt.Sscanf(name, "SignatureScheme(%d)", &s); err != nil || s.String() != name {
+		return 0, fmt.Errorf("unknown signature scheme: %s", name)
+	}
+	return s, nil
+}
+
+// MakeAddressFromSchemePubKey returns a new Address whose signatures are verified under the given public key in the
+// given signature scheme. The public key must be encoded as expected by the respective Plutus builtin: 32 bytes for
+// Ed25519 and SchnorrSecp256k1 and 33 bytes (compressed) for EcdsaSecp256k1. Other (custom) schemes may use 32 or 33
+// byte public keys.
+// Note: This does not set the public key hash!
+func MakeAddressFromSchemePubKey(scheme SignatureScheme, pubKey []byte) (Address, error) {
+	switch scheme {
+	case Ed25519, SchnorrSecp256k1:
+		if len(pubKey) != PubKeyLength {
+			return Address{}, fmt.Errorf(
+				"%s public key has incorrect length. expected: %d bytes actual: %d bytes",
+				scheme,
+				PubKeyLength,
+				len(pubKey),
+			)
+		}
+	case EcdsaSecp256k1:
+		if len(pubKey) != CompressedPubKeyLength {
+			return Address{}, fmt.Errorf(
+				"%s public key has incorrect length. expected: %d bytes actual: %d bytes",
+				scheme,
+				CompressedPubKeyLength,
+				len(pubKey),
+			)
+		}
+	}
+	addr := Address{signatureScheme: scheme}
+	switch len(pubKey) {
+	case PubKeyLength:
+		copy(addr.pubKey[:], pubKey)
+	case CompressedPubKeyLength:
+		if pubKey[0] != 0x02 && pubKey[0] != 0x03 {
+			return Address{}, fmt.Errorf("invalid compressed public key prefix: %x", pubKey[0])
+		}
+		addr.pubKeyPrefix = pubKey[0]
+		copy(addr.pubKey[:], pubKey[1:])
+	default:
+		return Address{}, fmt.Errorf("public key has incorrect length: %d bytes", len(pubKey))
+	}
+	return addr, nil
+}
+
+// GetSignatureScheme returns the signature scheme under which signatures of this address are verified.
+func (a Address) GetSignatureScheme() SignatureScheme {
+	return a.signatureScheme
+}
+
+// GetSchemePubKey returns the public key of this address in the encoding of its signature scheme (see
+// MakeAddressFromSchemePubKey).
+func (a Address) GetSchemePubKey() []byte {
+	return append([]byte{}, a.schemePubKey(a.pubKeyPrefix)...)
+}
@@ -26,13 +26,29 @@ import (
 )
 
 // RemoteBackend is a wallet.Backend implementation with a remote server for signing data and verifying signatures.
+// The results of channel state signature verifications are cached in its VerificationCache.
 type RemoteBackend struct {
 	walletServer Remote
+	cache        *VerificationCache
+	schemes      SignatureSchemes
 }
 
-// MakeRemoteBackend returns a new RemoteBackend struct.
+// MakeRemoteBackend returns a new RemoteBackend struct with a VerificationCache of DefaultVerificationCacheSize.
 func MakeRemoteBackend(remote Remote) RemoteBackend {
-	return RemoteBackend{remote}
+	return MakeRemoteBackendWithCache(remote, NewVerificationCache(DefaultVerificationCacheSize))
+}
+
+// MakeRemoteBackendWithCache returns a new RemoteBackend struct that caches verification results in the given
+// VerificationCache. A nil cache disables caching.
+func MakeRemoteBackendWithCache(remote Remote, cache *VerificationCache) RemoteBackend {
+	return RemoteBackend{walletServer: remote, cache: cache}
+}
+
+// WithSignatureSchemes returns a copy of this RemoteBackend that verifies signatures with the given signature schemes.
+// By default, only the built-in schemes are supported (see SignatureSchemes).
+func (b RemoteBackend) WithSignatureSchemes(schemes SignatureSchemes) RemoteBackend {
+	b.schemes = schemes
+	return b
 }
 
 // NewAddress returns a pointer to a new, empty address.
@@ -40,30 +56,39 @@ func (b RemoteBackend) NewAddress() wallet.Address {
 	return new(address.Address)
 }
 
-// DecodeSig reads SignatureLength bytes from the given reader and returns the read signature.
+// DecodeSig reads SignatureLength bytes from the given reader and returns the read signature. If the read bytes are
+// the header of a signature envelope (e.g. a multi-signature or a signature of another signature scheme), the
+// remaining envelope is read as well.
 func (b RemoteBackend) DecodeSig(reader io.Reader) (wallet.Sig, error) {
-	sig := make([]byte, wire.SignatureLength)
-	if _, err := io.ReadFull(reader, sig); err != nil {
-		return nil, fmt.Errorf("unable to read signature from reader: %w", err)
-	}
-	return sig, nil
+	return readSig(reader)
 }
 
 // VerifySignature returns true, iff the given signature is valid for the given message under the public key associated
-// with the given address.
+// with the given address. For multi-signature addresses, the signature must be a multi-signature of at least the
+// threshold of the address' signing keys.
 func (b RemoteBackend) VerifySignature(msg []byte, sig wallet.Sig, a wallet.Address) (bool, error) {
 	addr, ok := a.(*address.Address)
 	if !ok {
 		return false, fmt.Errorf("invalid PubKey for signature verification")
 	}
-	if len(sig) != wire.SignatureLength {
-		return false, fmt.Errorf(
-			"signature has incorrect length. expected: %d bytes actual: %d bytes",
-			wire.SignatureLength,
-			len(sig),
-		)
+	if addr.IsMultiSig() {
+		return verifyMultiSig(sig, *addr, func(sig wallet.Sig, signer address.Address) (bool, error) {
+			return b.verifySignature(msg, sig, signer)
+		})
+	}
+	return b.verifySignature(msg, sig, *addr)
+}
+
+// verifySignature verifies a single signature of a single key address. Signatures of signature schemes other than
+// address.Ed25519 are verified locally (see SignatureScheme).
+func (b RemoteBackend) verifySignature(msg []byte, sig wallet.Sig, addr address.Address) (bool, error) {
+	if addr.GetSignatureScheme() != address.Ed25519 {
+		return verifySchemeSignature(b.schemes, msg, sig, addr)
+	}
+	if _, _, err := unwrapSig(b.schemes, sig, addr); err != nil {
+		return false, err
 	}
-	request := wire.MakeVerificationRequest(sig, *addr, msg)
+	request := wire.MakeVerificationRequest(sig, addr, msg)
 	var response wire.VerificationResponse
 	err := b.walletServer.CallEndpoint(EndpointVerifyDataSignature, request, &response)
 	if err != nil {
@@ -73,22 +98,163 @@ func (b RemoteBackend) VerifySignature(msg []byte, sig wallet.Sig, a wallet.Addr
 }
 
 // VerifyChannelStateSignature returns true, iff the given signature is valid for the given ChannelState under the
-// public key associated with the given address.
+// public key associated with the given address. For multi-signature addresses, the signature must be a
+// multi-signature of at least the threshold of the address' signing keys.
 func (b RemoteBackend) VerifyChannelStateSignature(state types.ChannelState, sig wallet.Sig, a wallet.Address) (bool, error) {
 	addr, ok := a.(*address.Address)
 	if !ok {
 		return false, fmt.Errorf("invalid PubKey for signature verification")
 	}
-	if len(sig) != wire.SignatureLength {
-		return false, fmt.Errorf(
-			"signature has incorrect length. expected: %d bytes actual: %d bytes",
-			wire.SignatureLength,
-			len(sig),
+	key, cacheable := b.verificationKey(state, sig, *addr)
+	if cacheable {
+		if valid, ok := b.cache.get(key); ok {
+			return valid, nil
+		}
+	}
+	valid, err := verifyWith(sig, *addr, func(sig wallet.Sig, signer address.Address) (bool, error) {
+		return b.verifyChannelStateSignature(state, sig, signer)
+	})
+	if err == nil && cacheable {
+		b.cache.add(key, valid)
+	}
+	return valid, err
+}
+
+// VerifyChannelStateSignatures returns the result of VerifyChannelStateSignature for every given signature. All
+// signatures, whose result is not cached, are verified by the wallet server in a single call. If the wallet server
+// does not offer batch verification, they are verified one by one.
+func (b RemoteBackend) VerifyChannelStateSignatures(sigs []types.ChannelStateSignature) ([]bool, error) {
+	type pendingSig struct {
+		types.ChannelStateSignature
+		index      int
+		addr       address.Address
+		key        verificationKey
+		cacheable  bool
+		start, end int
+	}
+	results := make([]bool, len(sigs))
+	var pending []pendingSig
+	var requests []wire.ChannelStateVerificationRequest
+	for i, s := range sigs {
+		addr, ok := s.Address.(*address.Address)
+		if !ok {
+			return nil, fmt.Errorf("invalid PubKey for signature verification of signature %d", i)
+		}
+		key, cacheable := b.verificationKey(s.State, s.Sig, *addr)
+		if cacheable {
+			if valid, ok := b.cache.get(key); ok {
+				results[i] = valid
+				continue
+			}
+		}
+		p := pendingSig{ChannelStateSignature: s, index: i, addr: *addr, key: key, cacheable: cacheable, start: len(requests)}
+		// Collect the verification requests of all single key signatures. If the signature is invalid regardless of
+		// their results, it needs not be verified by the wallet server.
+		mayBeValid, err := verifyWith(s.Sig, *addr, func(sig wallet.Sig, signer address.Address) (bool, error) {
+			raw, _, err := unwrapSig(b.schemes, sig, signer)
+			if err != nil {
+				return false, err
+			}
+			requests = append(requests, wire.MakeChannelStateVerificationRequest(raw, signer, s.State))
+			return true, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify signature %d: %w", i, err)
+		}
+		if !mayBeValid {
+			requests = requests[:p.start]
+			if cacheable {
+				b.cache.add(key, false)
+			}
+			continue
+		}
+		p.end = len(requests)
+		pending = append(pending, p)
+	}
+	if len(requests) == 0 {
+		return results, nil
+	}
+	responses, err := b.verifyChannelStateBatch(requests)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pending {
+		remaining := responses[p.start:p.end]
+		valid, err := verifyWith(p.Sig, p.addr, func(wallet.Sig, address.Address) (bool, error) {
+			valid := remaining[0]
+			remaining = remaining[1:]
+			return valid, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify signature %d: %w", p.index, err)
+		}
+		results[p.index] = valid
+		if p.cacheable {
+			b.cache.add(p.key, valid)
+		}
+	}
+	return results, nil
+}
+
+// verifyChannelStateBatch calls the batch verification endpoint of the wallet server with the given requests. If the
+// wallet server does not know the endpoint, the requests are sent one by one.
+func (b RemoteBackend) verifyChannelStateBatch(requests []wire.ChannelStateVerificationRequest) ([]bool, error) {
+	var response wire.BatchVerificationResponse
+	err := b.walletServer.CallEndpoint(
+		EndpointVerifyChannelStateSignatures,
+		wire.BatchVerificationRequest{Requests: requests},
+		&response,
+	)
+	if IsUnsupportedEndpoint(err) {
+		response = make(wire.BatchVerificationResponse, len(requests))
+		for i, request := range requests {
+			err = b.walletServer.CallEndpoint(EndpointVerifyChannelStateSignature, request, &response[i])
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("wallet server could not verify channel states: %w", err)
+	}
+	if len(response) != len(requests) {
+		return nil, fmt.Errorf(
+			"wallet server returned wrong number of verification results. expected: %d, actual: %d",
+			len(requests),
+			len(response),
 		)
 	}
-	request := wire.MakeChannelStateVerificationRequest(sig, *addr, state)
+	return response, nil
+}
+
+// verificationKey returns the key of the verification of the given signature in the VerificationCache and true, iff
+// the verification is cacheable.
+func (b RemoteBackend) verificationKey(state types.ChannelState, sig wallet.Sig, addr address.Address) (verificationKey, bool) {
+	if b.cache == nil {
+		return verificationKey{}, false
+	}
+	key, err := makeVerificationKey(state, sig, addr)
+	return key, err == nil
+}
+
+// verifyWith verifies the given signature of the given address with the given verification of single key signatures.
+func verifyWith(sig wallet.Sig, addr address.Address, verify func(sig wallet.Sig, signer address.Address) (bool, error)) (bool, error) {
+	if addr.IsMultiSig() {
+		return verifyMultiSig(sig, addr, verify)
+	}
+	return verify(sig, addr)
+}
+
+// verifyChannelStateSignature verifies a single signature on a ChannelState of a single key address.
+// The raw signature is sent to the wallet server together with the signature scheme of addr.
+func (b RemoteBackend) verifyChannelStateSignature(state types.ChannelState, sig wallet.Sig, addr address.Address) (bool, error) {
+	raw, _, err := unwrapSig(b.schemes, sig, addr)
+	if err != nil {
+		return false, err
+	}
+	request := wire.MakeChannelStateVerificationRequest(raw, addr, state)
 	var response wire.VerificationResponse
-	err := b.walletServer.CallEndpoint(EndpointVerifyChannelStateSignature, request, &response)
+	err = b.walletServer.CallEndpoint(EndpointVerifyChannelStateSignature, request, &response)
 	if err != nil {
 		return false, fmt.Errorf("wallet server could not verify message: %w", err)
 	}
@@ -106,12 +272,17 @@ func (b RemoteBackend) CalculateChannelID(parameters types.ChannelParameters) (c
 	return response, nil
 }
 
+// ToChannelStateSigningAccount returns the given account, if it is able to sign channel states (e.g. a RemoteAccount
+// or an account with a locally held signing key). Any other account is wrapped in a DataSigningAccount, whose
+// signatures rely on the local serialization of channel states (see DataSigningAccount).
 func (b RemoteBackend) ToChannelStateSigningAccount(account wallet.Account) (types.ChannelStateSigningAccount, error) {
-	acc, ok := account.(RemoteAccount)
-	if !ok {
-		return acc, errors.New("account is not a RemoteAccount")
+	if account == nil {
+		return nil, errors.New("account must not be nil")
+	}
+	if acc, ok := account.(types.ChannelStateSigningAccount); ok {
+		return acc, nil
 	}
-	return acc, nil
+	return MakeDataSigningAccount(account), nil
 }
 
 var _ wallet.Backend = RemoteBackend{}
@@ -18,6 +18,10 @@ import (
 	"bytes"
 	"github.com/stretchr/testify/require"
 	"io"
+	"net/http"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/transport"
 	"perun.network/perun-cardano-backend/wallet"
 	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wallet/test"
@@ -134,3 +138,78 @@ func TestRemoteBackend_VerifyChannelStateSignature(t *testing.T) {
 		len(r.InvalidSignatureLonger),
 	)
 }
+
+// legacyRemote is a wallet server without the batch verification endpoint.
+type legacyRemote struct {
+	wallet.Remote
+}
+
+func (r legacyRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	if endpoint == wallet.EndpointVerifyChannelStateSignatures {
+		return &transport.StatusError{Endpoint: endpoint, StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+	}
+	return r.Remote.CallEndpoint(endpoint, body, result)
+}
+
+func TestRemoteBackend_VerifyChannelStateSignatures(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	multiSigAddr, multiSigRemote := setupMultiSig(rng, 2)
+	singleAddr := test.MakeRandomAddress(rng)
+	singleRemote := test.NewGenericRemote([]address.Address{singleAddr}, rng)
+	state := ctest.MakeRandomChannelState(rng)
+
+	singleSig, err := test.MakeRemoteAccount(singleAddr, singleRemote).SignChannelState(state)
+	require.NoError(t, err)
+	account, err := test.NewRemoteWallet(multiSigRemote).Unlock(&multiSigAddr)
+	require.NoError(t, err)
+	multiSig, err := account.(wallet.RemoteAccount).SignChannelState(state)
+	require.NoError(t, err)
+	components, err := wallet.DecodeMultiSig(multiSig)
+	require.NoError(t, err)
+	tooFew, err := wallet.MakeMultiSig(components[:1])
+	require.NoError(t, err)
+	wrongKey, err := wallet.MakeMultiSig([]wallet.MultiSigComponent{
+		components[0],
+		{KeyIndex: 2, Signature: components[1].Signature},
+	})
+	require.NoError(t, err)
+
+	sigs := []types.ChannelStateSignature{
+		{State: state, Sig: singleSig, Address: &singleAddr},
+		{State: state, Sig: test.MakeRandomSignature(rng), Address: &singleAddr},
+		{State: state, Sig: multiSig, Address: &multiSigAddr},
+		{State: state, Sig: tooFew, Address: &multiSigAddr},
+		{State: state, Sig: wrongKey, Address: &multiSigAddr},
+	}
+	expected := []bool{true, false, true, false, false}
+
+	remote := newCountingRemote(multiSigRemote)
+	backend := wallet.MakeRemoteBackend(remote)
+	results, err := backend.VerifyChannelStateSignatures(sigs)
+	require.NoError(t, err, "unable to verify batch of signatures")
+	require.Equal(t, expected, results, "batch verification returned wrong results")
+	require.Equal(t, 1, remote.count(wallet.EndpointVerifyChannelStateSignatures), "signatures were not verified in one call")
+	require.Zero(t, remote.count(wallet.EndpointVerifyChannelStateSignature), "signatures were verified one by one")
+
+	results, err = backend.VerifyChannelStateSignatures(sigs)
+	require.NoError(t, err)
+	require.Equal(t, expected, results)
+	require.Equal(t, 1, remote.count(wallet.EndpointVerifyChannelStateSignatures), "cached results were not used")
+	for i, s := range sigs {
+		valid, err := backend.VerifyChannelStateSignature(s.State, s.Sig, s.Address)
+		require.NoError(t, err)
+		require.Equal(t, expected[i], valid, "batch and single verification disagree")
+	}
+	require.Zero(t, remote.count(wallet.EndpointVerifyChannelStateSignature), "cached results were not used")
+
+	legacy := newCountingRemote(legacyRemote{multiSigRemote})
+	results, err = wallet.MakeRemoteBackendWithCache(legacy, nil).VerifyChannelStateSignatures(sigs)
+	require.NoError(t, err, "unable to verify batch of signatures without batch endpoint")
+	require.Equal(t, expected, results)
+	require.Equal(t, 6, legacy.count(wallet.EndpointVerifyChannelStateSignature), "every single key signature should be verified once")
+
+	_, err = backend.VerifyChannelStateSignatures([]types.ChannelStateSignature{
+		{State: state, Sig: test.MakeTooShortSignature(rng), Address: &singleAddr},
+	})
+	require.Error(t, err, "failed to error on signature of invalid length")
+}
@@ -0,0 +1,42 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+// DataSigningAccount makes any wallet.Account a types.ChannelStateSigningAccount. It signs the local serialization of
+// the contract's ChannelState (see wire.MakeChannelStateMessage) with the SignData method of the wrapped account,
+// whereas a RemoteAccount lets the wallet server serialize it. The signatures are only valid on-chain, if both
+// serializations are equal, which is not checked at runtime. Set PERUN_CARDANO_WALLET_URL to check it against a wallet
+// server in the tests.
+type DataSigningAccount struct {
+	wallet.Account
+}
+
+// MakeDataSigningAccount returns a new DataSigningAccount wrapping the given account.
+func MakeDataSigningAccount(account wallet.Account) DataSigningAccount {
+	return DataSigningAccount{Account: account}
+}
+
+// SignChannelState signs the canonical message of the given channel state with the wrapped account.
+func (a DataSigningAccount) SignChannelState(channelState types.ChannelState) (wallet.Sig, error) {
+	return a.SignData(wire.MakeChannelStateMessage(channelState))
+}
+
+var _ types.ChannelStateSigningAccount = DataSigningAccount{}
@@ -0,0 +1,103 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet/address"
+)
+
+// KeyUnavailableError is returned, if the wallet server holds fewer of the signing keys of an address than needed to
+// sign for it.
+type KeyUnavailableError struct {
+	Address   address.Address
+	Available int
+	Required  int
+}
+
+func (e *KeyUnavailableError) Error() string {
+	if e.Required == 1 {
+		return fmt.Sprintf("wallet server has no private key for address %s", e.Address)
+	}
+	return fmt.Sprintf(
+		"wallet server holds only %d of the %d required signing keys for address %s",
+		e.Available,
+		e.Required,
+		e.Address,
+	)
+}
+
+// Class returns errclass.KeyUnavailable.
+func (e *KeyUnavailableError) Class() errclass.Class {
+	return errclass.KeyUnavailable
+}
+
+// AccountLockedError is returned, if an account of a RemoteWallet is used to sign after it was locked (see
+// RemoteWallet.DecrementUsage and RemoteWallet.LockAll).
+type AccountLockedError struct {
+	Address address.Address
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account %s is locked", e.Address)
+}
+
+// Class returns errclass.KeyUnavailable.
+func (e *AccountLockedError) Class() errclass.Class {
+	return errclass.KeyUnavailable
+}
+
+// InvalidRequestError is returned by Remote implementations serving a wallet server (see Server), if a request to the
+// given endpoint is malformed.
+type InvalidRequestError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *InvalidRequestError) Error() string {
+	return fmt.Sprintf("invalid request to %s: %v", e.Endpoint, e.Err)
+}
+
+// Unwrap returns the reason the request is invalid.
+func (e *InvalidRequestError) Unwrap() error {
+	return e.Err
+}
+
+// Class returns errclass.Client.
+func (e *InvalidRequestError) Class() errclass.Class {
+	return errclass.Client
+}
+
+// IsUnsupportedEndpoint returns true, iff the given error reports that the wallet server does not offer the called
+// endpoint, e.g. because it is an older wallet server that responds with 404 Not Found. Errors of other transports
+// report this with an `Unsupported() bool` method.
+func IsUnsupportedEndpoint(err error) bool {
+	var statusErr *transport.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusNotFound
+	}
+	var unsupported interface{ Unsupported() bool }
+	return errors.As(err, &unsupported) && unsupported.Unsupported()
+}
+
+var (
+	_ errclass.Classified = &KeyUnavailableError{}
+	_ errclass.Classified = &AccountLockedError{}
+	_ errclass.Classified = &InvalidRequestError{}
+)
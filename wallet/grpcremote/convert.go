@@ -0,0 +1,540 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcremote
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/walletpb"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+// makeStreamRequest returns the request for the given wallet server endpoint with the given json api request body.
+func makeStreamRequest(endpoint string, body interface{}) (*walletpb.StreamRequest, error) {
+	var err error
+	request := &walletpb.StreamRequest{}
+	switch endpoint {
+	case wallet.EndpointSignData:
+		b, ok := body.(wire.SigningRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r := &walletpb.SignRequest{}
+		if r.PubKey, err = pubKeyToProto(b.PubKey); err != nil {
+			return nil, err
+		}
+		if r.Message, err = hex.DecodeString(b.Message); err != nil {
+			return nil, fmt.Errorf("unable to decode message: %w", err)
+		}
+		request.Request = &walletpb.StreamRequest_Sign{Sign: r}
+	case wallet.EndpointSignChannelState:
+		b, ok := body.(wire.ChannelStateSigningRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r := &walletpb.SignChannelStateRequest{State: channelStateToProto(b.ChannelState)}
+		if r.PubKey, err = pubKeyToProto(b.PubKey); err != nil {
+			return nil, err
+		}
+		request.Request = &walletpb.StreamRequest_SignChannelState{SignChannelState: r}
+	case wallet.EndpointVerifyDataSignature:
+		b, ok := body.(wire.VerificationRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r := &walletpb.VerifyRequest{}
+		if r.Signature, err = signatureToProto(b.Signature); err != nil {
+			return nil, err
+		}
+		if r.PubKey, err = pubKeyToProto(b.PubKey); err != nil {
+			return nil, err
+		}
+		if r.Message, err = hex.DecodeString(b.Message); err != nil {
+			return nil, fmt.Errorf("unable to decode message: %w", err)
+		}
+		request.Request = &walletpb.StreamRequest_Verify{Verify: r}
+	case wallet.EndpointVerifyChannelStateSignature:
+		b, ok := body.(wire.ChannelStateVerificationRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r, err := verifyChannelStateRequestToProto(b)
+		if err != nil {
+			return nil, err
+		}
+		request.Request = &walletpb.StreamRequest_VerifyChannelState{VerifyChannelState: r}
+	case wallet.EndpointVerifyChannelStateSignatures:
+		b, ok := body.(wire.BatchVerificationRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r := &walletpb.VerifyChannelStatesRequest{}
+		for _, req := range b.Requests {
+			v, err := verifyChannelStateRequestToProto(req)
+			if err != nil {
+				return nil, err
+			}
+			r.Requests = append(r.Requests, v)
+		}
+		request.Request = &walletpb.StreamRequest_VerifyChannelStates{VerifyChannelStates: r}
+	case wallet.EndpointKeyAvailable:
+		b, ok := body.(wire.KeyAvailabilityRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r := &walletpb.KeyAvailableRequest{}
+		if r.PubKey, err = pubKeyToProto(b); err != nil {
+			return nil, err
+		}
+		request.Request = &walletpb.StreamRequest_KeyAvailable{KeyAvailable: r}
+	case wallet.EndpointLockKey, wallet.EndpointUnlockKey:
+		b, ok := body.(wire.KeyLockRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r := &walletpb.KeyLockRequest{}
+		if r.PubKey, err = pubKeyToProto(b); err != nil {
+			return nil, err
+		}
+		if endpoint == wallet.EndpointLockKey {
+			request.Request = &walletpb.StreamRequest_LockKey{LockKey: r}
+		} else {
+			request.Request = &walletpb.StreamRequest_UnlockKey{UnlockKey: r}
+		}
+	case wallet.EndpointListAccounts:
+		if _, ok := body.(wire.ListAccountsRequest); !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		request.Request = &walletpb.StreamRequest_ListAccounts{ListAccounts: &walletpb.ListAccountsRequest{}}
+	case wallet.EndpointCreateAccount:
+		b, ok := body.(wire.CreateAccountRequest)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		request.Request = &walletpb.StreamRequest_CreateAccount{
+			CreateAccount: &walletpb.CreateAccountRequest{Scheme: b.Scheme, WalletId: b.WalletID},
+		}
+	case wallet.EndpointCalculateChannelID:
+		b, ok := body.(wire.ChannelParameters)
+		if !ok {
+			return nil, fmt.Errorf("invalid request type %T for endpoint %s", body, endpoint)
+		}
+		r, err := parametersToProto(b)
+		if err != nil {
+			return nil, err
+		}
+		request.Request = &walletpb.StreamRequest_CalculateChannelId{CalculateChannelId: r}
+	default:
+		return nil, fmt.Errorf("invalid endpoint: %s", endpoint)
+	}
+	return request, nil
+}
+
+// decodeStreamResponse decodes the given response into the given json api result. `result` must be a pointer.
+func decodeStreamResponse(response *walletpb.StreamResponse, result interface{}) error {
+	switch r := response.Response.(type) {
+	case *walletpb.StreamResponse_Error:
+		return &RPCError{Code: codeOf(r.Error.GetCode()), Message: r.Error.GetMessage()}
+	case *walletpb.StreamResponse_Sign:
+		res, ok := result.(*wire.SigningResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for signing response", result)
+		}
+		*res = wire.MakeSignature(r.Sign.GetSignature())
+	case *walletpb.StreamResponse_Verify:
+		res, ok := result.(*wire.VerificationResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for verification response", result)
+		}
+		*res = r.Verify.GetValid()
+	case *walletpb.StreamResponse_VerifyChannelStates:
+		res, ok := result.(*wire.BatchVerificationResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for batch verification response", result)
+		}
+		*res = append(wire.BatchVerificationResponse{}, r.VerifyChannelStates.GetValid()...)
+	case *walletpb.StreamResponse_KeyAvailable:
+		res, ok := result.(*wire.KeyAvailabilityResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for key availability response", result)
+		}
+		*res = r.KeyAvailable.GetAvailable()
+	case *walletpb.StreamResponse_KeyLock:
+		res, ok := result.(*wire.KeyLockResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for key lock response", result)
+		}
+		*res = r.KeyLock.GetAvailable()
+	case *walletpb.StreamResponse_ListAccounts:
+		res, ok := result.(*wire.ListAccountsResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for list accounts response", result)
+		}
+		*res = make(wire.ListAccountsResponse, 0, len(r.ListAccounts.GetAccounts()))
+		for _, info := range r.ListAccounts.GetAccounts() {
+			*res = append(*res, accountInfoFromProto(info))
+		}
+	case *walletpb.StreamResponse_Account:
+		res, ok := result.(*wire.CreateAccountResponse)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for account response", result)
+		}
+		*res = accountInfoFromProto(r.Account)
+	case *walletpb.StreamResponse_CalculateChannelId:
+		res, ok := result.(*wire.ChannelID)
+		if !ok {
+			return fmt.Errorf("invalid result type %T for channel id response", result)
+		}
+		id := r.CalculateChannelId.GetChannelId()
+		if len(id) != wire.ChannelIDLength {
+			return fmt.Errorf("channel id has wrong length: %d", len(id))
+		}
+		copy(res[:], id)
+	default:
+		return errors.New("empty response")
+	}
+	return nil
+}
+
+// decodeStreamRequest returns the wallet server endpoint and the json api request body of the given request, as well
+// as a pointer to the json api result the endpoint responds with.
+func decodeStreamRequest(request *walletpb.StreamRequest) (string, interface{}, interface{}, error) {
+	switch r := request.Request.(type) {
+	case *walletpb.StreamRequest_Sign:
+		body := wire.SigningRequest{
+			PubKey:  pubKeyFromProto(r.Sign.GetPubKey()),
+			Message: hex.EncodeToString(r.Sign.GetMessage()),
+		}
+		return wallet.EndpointSignData, body, new(wire.SigningResponse), nil
+	case *walletpb.StreamRequest_SignChannelState:
+		state, err := channelStateFromProto(r.SignChannelState.GetState())
+		if err != nil {
+			return "", nil, nil, err
+		}
+		body := wire.ChannelStateSigningRequest{
+			PubKey:       pubKeyFromProto(r.SignChannelState.GetPubKey()),
+			ChannelState: state,
+		}
+		return wallet.EndpointSignChannelState, body, new(wire.SigningResponse), nil
+	case *walletpb.StreamRequest_Verify:
+		body := wire.VerificationRequest{
+			Signature: wire.MakeSignature(r.Verify.GetSignature()),
+			PubKey:    pubKeyFromProto(r.Verify.GetPubKey()),
+			Message:   hex.EncodeToString(r.Verify.GetMessage()),
+		}
+		return wallet.EndpointVerifyDataSignature, body, new(wire.VerificationResponse), nil
+	case *walletpb.StreamRequest_VerifyChannelState:
+		body, err := verifyChannelStateRequestFromProto(r.VerifyChannelState)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return wallet.EndpointVerifyChannelStateSignature, body, new(wire.VerificationResponse), nil
+	case *walletpb.StreamRequest_VerifyChannelStates:
+		var body wire.BatchVerificationRequest
+		for _, req := range r.VerifyChannelStates.GetRequests() {
+			v, err := verifyChannelStateRequestFromProto(req)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			body.Requests = append(body.Requests, v)
+		}
+		return wallet.EndpointVerifyChannelStateSignatures, body, new(wire.BatchVerificationResponse), nil
+	case *walletpb.StreamRequest_KeyAvailable:
+		body := pubKeyFromProto(r.KeyAvailable.GetPubKey())
+		return wallet.EndpointKeyAvailable, body, new(wire.KeyAvailabilityResponse), nil
+	case *walletpb.StreamRequest_LockKey:
+		body := pubKeyFromProto(r.LockKey.GetPubKey())
+		return wallet.EndpointLockKey, body, new(wire.KeyLockResponse), nil
+	case *walletpb.StreamRequest_UnlockKey:
+		body := pubKeyFromProto(r.UnlockKey.GetPubKey())
+		return wallet.EndpointUnlockKey, body, new(wire.KeyLockResponse), nil
+	case *walletpb.StreamRequest_ListAccounts:
+		return wallet.EndpointListAccounts, wire.ListAccountsRequest{}, new(wire.ListAccountsResponse), nil
+	case *walletpb.StreamRequest_CreateAccount:
+		body := wire.CreateAccountRequest{
+			Scheme:   r.CreateAccount.GetScheme(),
+			WalletID: r.CreateAccount.GetWalletId(),
+		}
+		return wallet.EndpointCreateAccount, body, new(wire.CreateAccountResponse), nil
+	case *walletpb.StreamRequest_CalculateChannelId:
+		body, err := parametersFromProto(r.CalculateChannelId)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return wallet.EndpointCalculateChannelID, body, new(wire.ChannelID), nil
+	default:
+		return "", nil, nil, errors.New("empty request")
+	}
+}
+
+// makeStreamResponse returns the response for the given json api result of the given wallet server endpoint.
+func makeStreamResponse(id uint64, endpoint string, result interface{}) (*walletpb.StreamResponse, error) {
+	response := &walletpb.StreamResponse{Id: id}
+	switch endpoint {
+	case wallet.EndpointSignData, wallet.EndpointSignChannelState:
+		sig, err := signatureToProto(*result.(*wire.SigningResponse))
+		if err != nil {
+			return nil, err
+		}
+		response.Response = &walletpb.StreamResponse_Sign{Sign: &walletpb.SignResponse{Signature: sig}}
+	case wallet.EndpointVerifyDataSignature, wallet.EndpointVerifyChannelStateSignature:
+		valid := *result.(*wire.VerificationResponse)
+		response.Response = &walletpb.StreamResponse_Verify{Verify: &walletpb.VerifyResponse{Valid: valid}}
+	case wallet.EndpointVerifyChannelStateSignatures:
+		valid := *result.(*wire.BatchVerificationResponse)
+		response.Response = &walletpb.StreamResponse_VerifyChannelStates{
+			VerifyChannelStates: &walletpb.VerifyChannelStatesResponse{Valid: valid},
+		}
+	case wallet.EndpointKeyAvailable:
+		available := *result.(*wire.KeyAvailabilityResponse)
+		response.Response = &walletpb.StreamResponse_KeyAvailable{
+			KeyAvailable: &walletpb.KeyAvailableResponse{Available: available},
+		}
+	case wallet.EndpointLockKey, wallet.EndpointUnlockKey:
+		available := *result.(*wire.KeyLockResponse)
+		response.Response = &walletpb.StreamResponse_KeyLock{
+			KeyLock: &walletpb.KeyLockResponse{Available: available},
+		}
+	case wallet.EndpointListAccounts:
+		infos := *result.(*wire.ListAccountsResponse)
+		accounts := make([]*walletpb.AccountInfo, len(infos))
+		for i, info := range infos {
+			var err error
+			if accounts[i], err = accountInfoToProto(info); err != nil {
+				return nil, err
+			}
+		}
+		response.Response = &walletpb.StreamResponse_ListAccounts{
+			ListAccounts: &walletpb.ListAccountsResponse{Accounts: accounts},
+		}
+	case wallet.EndpointCreateAccount:
+		account, err := accountInfoToProto(*result.(*wire.CreateAccountResponse))
+		if err != nil {
+			return nil, err
+		}
+		response.Response = &walletpb.StreamResponse_Account{Account: account}
+	case wallet.EndpointCalculateChannelID:
+		id := result.(*wire.ChannelID)
+		response.Response = &walletpb.StreamResponse_CalculateChannelId{
+			CalculateChannelId: &walletpb.CalculateChannelIDResponse{ChannelId: append([]byte{}, id[:]...)},
+		}
+	default:
+		return nil, fmt.Errorf("invalid endpoint: %s", endpoint)
+	}
+	return response, nil
+}
+
+func verifyChannelStateRequestToProto(request wire.ChannelStateVerificationRequest) (*walletpb.VerifyChannelStateRequest, error) {
+	var err error
+	r := &walletpb.VerifyChannelStateRequest{State: channelStateToProto(request.ChannelState)}
+	if r.Signature, err = signatureToProto(request.Signature); err != nil {
+		return nil, err
+	}
+	if r.PubKey, err = pubKeyToProto(request.PubKey); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func verifyChannelStateRequestFromProto(request *walletpb.VerifyChannelStateRequest) (wire.ChannelStateVerificationRequest, error) {
+	state, err := channelStateFromProto(request.GetState())
+	if err != nil {
+		return wire.ChannelStateVerificationRequest{}, err
+	}
+	return wire.ChannelStateVerificationRequest{
+		Signature:    wire.MakeSignature(request.GetSignature()),
+		PubKey:       pubKeyFromProto(request.GetPubKey()),
+		ChannelState: state,
+	}, nil
+}
+
+func signatureToProto(sig wire.Signature) ([]byte, error) {
+	b, err := hex.DecodeString(sig.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode signature: %w", err)
+	}
+	return b, nil
+}
+
+func pubKeyToProto(key wire.PubKey) (*walletpb.PubKey, error) {
+	b, err := hex.DecodeString(key.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode public key: %w", err)
+	}
+	return &walletpb.PubKey{Key: b, Scheme: key.Scheme}, nil
+}
+
+func pubKeyFromProto(key *walletpb.PubKey) wire.PubKey {
+	return wire.PubKey{Hex: hex.EncodeToString(key.GetKey()), Scheme: key.GetScheme()}
+}
+
+func accountInfoToProto(info wire.AccountInfo) (*walletpb.AccountInfo, error) {
+	key, err := pubKeyToProto(info.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.AccountInfo{PubKey: key, WalletId: info.WalletID}, nil
+}
+
+func accountInfoFromProto(info *walletpb.AccountInfo) wire.AccountInfo {
+	return wire.AccountInfo{PubKey: pubKeyFromProto(info.GetPubKey()), WalletID: info.GetWalletId()}
+}
+
+func channelStateToProto(state wire.ChannelState) *walletpb.ChannelState {
+	return &walletpb.ChannelState{
+		ChannelId: append([]byte{}, state.ChannelID[:]...),
+		Balances:  state.Balances,
+		Version:   state.Version,
+		Final:     state.Final,
+	}
+}
+
+func channelStateFromProto(state *walletpb.ChannelState) (wire.ChannelState, error) {
+	if len(state.GetChannelId()) != wire.ChannelIDLength {
+		return wire.ChannelState{}, fmt.Errorf("channel id has wrong length: %d", len(state.GetChannelId()))
+	}
+	s := wire.ChannelState{
+		Balances: state.GetBalances(),
+		Final:    state.GetFinal(),
+		Version:  state.GetVersion(),
+	}
+	copy(s.ChannelID[:], state.GetChannelId())
+	return s, nil
+}
+
+func credentialToProto(credential wire.Credential) (*walletpb.Credential, error) {
+	c, err := credential.Decode()
+	if err != nil {
+		return nil, err
+	}
+	t := walletpb.Credential_KEY_HASH
+	if c.Type == address.ScriptHashCredential {
+		t = walletpb.Credential_SCRIPT_HASH
+	}
+	return &walletpb.Credential{Type: t, Hash: append([]byte{}, c.Hash[:]...)}, nil
+}
+
+func credentialFromProto(credential *walletpb.Credential) (wire.Credential, error) {
+	if len(credential.GetHash()) != address.CredentialHashLength {
+		return wire.Credential{}, fmt.Errorf("credential hash has wrong length: %d", len(credential.GetHash()))
+	}
+	c := address.Credential{Type: address.KeyHashCredential}
+	switch credential.GetType() {
+	case walletpb.Credential_KEY_HASH:
+	case walletpb.Credential_SCRIPT_HASH:
+		c.Type = address.ScriptHashCredential
+	default:
+		return wire.Credential{}, fmt.Errorf("invalid credential type: %v", credential.GetType())
+	}
+	copy(c.Hash[:], credential.GetHash())
+	return wire.MakeCredential(c), nil
+}
+
+func addressToProto(addr wire.Address) (*walletpb.Address, error) {
+	payment, err := credentialToProto(addr.Credential)
+	if err != nil {
+		return nil, err
+	}
+	a := &walletpb.Address{PaymentCredential: payment}
+	if addr.StakingCredential != nil {
+		if addr.StakingCredential.Tag != wire.StakingHashTag {
+			return nil, fmt.Errorf("unsupported staking credential tag: %s", addr.StakingCredential.Tag)
+		}
+		if a.StakingCredential, err = credentialToProto(addr.StakingCredential.Contents); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func addressFromProto(addr *walletpb.Address) (wire.Address, error) {
+	payment, err := credentialFromProto(addr.GetPaymentCredential())
+	if err != nil {
+		return wire.Address{}, err
+	}
+	a := wire.Address{Credential: payment}
+	if addr.GetStakingCredential() != nil {
+		stake, err := credentialFromProto(addr.GetStakingCredential())
+		if err != nil {
+			return wire.Address{}, err
+		}
+		a.StakingCredential = &wire.StakingCredential{Contents: stake, Tag: wire.StakingHashTag}
+	}
+	return a, nil
+}
+
+func parametersToProto(params wire.ChannelParameters) (*walletpb.ChannelParameters, error) {
+	p := &walletpb.ChannelParameters{Nonce: params.Nonce, TimeLock: params.TimeLock}
+	for _, addr := range params.GetPaymentAddresses() {
+		a, err := addressToProto(addr)
+		if err != nil {
+			return nil, err
+		}
+		p.PaymentAddresses = append(p.PaymentAddresses, a)
+	}
+	for _, key := range params.SigningPubKeys {
+		k, err := pubKeyToProto(key.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		p.SigningPubKeys = append(p.SigningPubKeys, k)
+	}
+	for _, policy := range params.MultiSigPolicies {
+		mp := &walletpb.MultiSigPolicy{}
+		if policy != nil {
+			mp.Threshold = uint32(policy.Threshold)
+			for _, key := range policy.SigningPubKeys {
+				k, err := pubKeyToProto(key.PubKey)
+				if err != nil {
+					return nil, err
+				}
+				mp.SigningPubKeys = append(mp.SigningPubKeys, k)
+			}
+		}
+		p.MultiSigPolicies = append(p.MultiSigPolicies, mp)
+	}
+	return p, nil
+}
+
+func parametersFromProto(params *walletpb.ChannelParameters) (wire.ChannelParameters, error) {
+	p := wire.ChannelParameters{Nonce: params.GetNonce(), TimeLock: params.GetTimeLock()}
+	addresses := make([]wire.Address, 0, len(params.GetPaymentAddresses()))
+	for _, addr := range params.GetPaymentAddresses() {
+		a, err := addressFromProto(addr)
+		if err != nil {
+			return wire.ChannelParameters{}, err
+		}
+		addresses = append(addresses, a)
+	}
+	p.SetPaymentAddresses(addresses)
+	for _, key := range params.GetSigningPubKeys() {
+		p.SigningPubKeys = append(p.SigningPubKeys, wire.PaymentPubKey{PubKey: pubKeyFromProto(key)})
+	}
+	for _, policy := range params.GetMultiSigPolicies() {
+		if policy.GetThreshold() == 0 && len(policy.GetSigningPubKeys()) == 0 {
+			p.MultiSigPolicies = append(p.MultiSigPolicies, nil)
+			continue
+		}
+		mp := &wire.MultiSigPolicy{Threshold: int(policy.GetThreshold())}
+		for _, key := range policy.GetSigningPubKeys() {
+			mp.SigningPubKeys = append(mp.SigningPubKeys, wire.PaymentPubKey{PubKey: pubKeyFromProto(key)})
+		}
+		p.MultiSigPolicies = append(p.MultiSigPolicies, mp)
+	}
+	return p, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcremote
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/wallet"
+)
+
+// RPCError is returned, if a call to the wallet server over gRPC fails with a status other than OK.
+type RPCError struct {
+	Endpoint string
+	Code     codes.Code
+	Message  string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("wallet server endpoint %s failed with %s: %s", e.Endpoint, e.Code, e.Message)
+}
+
+// Class returns errclass.Network for unavailable servers and exceeded deadlines, errclass.Client for rejected requests
+// and errclass.Server otherwise.
+func (e *RPCError) Class() errclass.Class {
+	switch e.Code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return errclass.Network
+	case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.PermissionDenied,
+		codes.Unauthenticated, codes.Unimplemented:
+		return errclass.Client
+	default:
+		return errclass.Server
+	}
+}
+
+// Unsupported returns true, iff the wallet server does not implement the endpoint (see wallet.IsUnsupportedEndpoint).
+func (e *RPCError) Unsupported() bool {
+	return e.Code == codes.Unimplemented
+}
+
+// GRPCStatus returns the status of the failed call.
+func (e *RPCError) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Message)
+}
+
+// makeRPCError returns the RPCError for an error returned by a gRPC call to the given endpoint.
+func makeRPCError(endpoint string, err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return &RPCError{Endpoint: endpoint, Code: s.Code(), Message: s.Message()}
+}
+
+// statusOf returns the gRPC status code for an error of the wrapped Remote.
+func statusOf(err error) codes.Code {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	if wallet.IsUnsupportedEndpoint(err) {
+		return codes.Unimplemented
+	}
+	switch errclass.Of(err) {
+	case errclass.Network:
+		return codes.Unavailable
+	case errclass.Client:
+		return codes.InvalidArgument
+	case errclass.KeyUnavailable:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func codeOf(code uint32) codes.Code {
+	return codes.Code(code)
+}
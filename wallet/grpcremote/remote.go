@@ -0,0 +1,302 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcremote implements the wallet server protocol over gRPC (see walletpb). Remote is a wallet.Remote that
+// calls a wallet server over gRPC and Service serves any wallet.Remote as gRPC wallet server.
+package grpcremote
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/walletpb"
+	"sync"
+)
+
+// Remote is a wallet.Remote that calls the wallet server over gRPC. Every call is subject to the timeout of its
+// endpoint. Unary calls send the timeout to the wallet server as deadline of the call. A streaming Remote multiplexes
+// all calls over a single Wallet.Stream, which is reopened on the next call after it broke. The stream has no deadline,
+// so the timeout of a streamed call is only enforced by the Remote.
+type Remote struct {
+	conn      *grpc.ClientConn
+	client    walletpb.WalletClient
+	transport *transport.Config
+	streaming bool
+
+	mu      sync.Mutex
+	stream  *stream
+	nextID  uint64
+	pending map[uint64]chan *walletpb.StreamResponse
+}
+
+// stream is an open Wallet.Stream of a Remote. sendMu serializes the requests sent over the stream.
+type stream struct {
+	client walletpb.Wallet_StreamClient
+	cancel context.CancelFunc
+	sendMu sync.Mutex
+}
+
+// NewRemote returns a Remote for the wallet server at the given target (e.g. "localhost:8889"). The transport options
+// configure TLS and timeouts of all calls, as well as their authentication. Only bearer tokens are supported for
+// authentication. Retries and failover hosts are not supported over gRPC.
+func NewRemote(target string, opts ...transport.Option) (*Remote, error) {
+	t := transport.NewConfig(opts...)
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if t.UsesTLS() {
+		dialOpts[0] = grpc.WithTransportCredentials(credentials.NewTLS(t.TLSConfig()))
+	}
+	switch auth := t.Authenticator().(type) {
+	case nil:
+	case transport.BearerToken:
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerCredentials{token: auth, tls: t.UsesTLS()}))
+	default:
+		return nil, fmt.Errorf("authenticator %T is not supported over gRPC", auth)
+	}
+	conn, err := grpc.Dial(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to wallet server: %w", err)
+	}
+	return &Remote{
+		conn:      conn,
+		client:    walletpb.NewWalletClient(conn),
+		transport: t,
+		pending:   make(map[uint64]chan *walletpb.StreamResponse),
+	}, nil
+}
+
+// NewStreamingRemote is like NewRemote, but returns a Remote that multiplexes all calls over a single Wallet.Stream.
+func NewStreamingRemote(target string, opts ...transport.Option) (*Remote, error) {
+	r, err := NewRemote(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	r.streaming = true
+	return r, nil
+}
+
+// Close closes the connection to the wallet server.
+func (r *Remote) Close() error {
+	return r.conn.Close()
+}
+
+// CallEndpoint calls the given endpoint of the wallet server with the given json api request body and writes the
+// response to the given result. `result` must be a pointer. Failed calls are returned as RPCError.
+func (r *Remote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	request, err := makeStreamRequest(endpoint, body)
+	if err != nil {
+		return fmt.Errorf("unable to encode request: %w", err)
+	}
+	var response *walletpb.StreamResponse
+	if r.streaming {
+		response, err = r.callStream(endpoint, request)
+	} else {
+		response, err = r.call(endpoint, request)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to call endpoint: %w", err)
+	}
+	if err = decodeStreamResponse(response, result); err != nil {
+		if rpcErr, ok := err.(*RPCError); ok {
+			rpcErr.Endpoint = endpoint
+		}
+		return fmt.Errorf("failed to call endpoint: %w", err)
+	}
+	return nil
+}
+
+// call issues the given request with the unary call of its endpoint.
+func (r *Remote) call(endpoint string, request *walletpb.StreamRequest) (*walletpb.StreamResponse, error) {
+	ctx := context.Background()
+	if timeout := r.transport.Timeout(endpoint); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	response := &walletpb.StreamResponse{Id: request.Id}
+	var err error
+	switch req := request.Request.(type) {
+	case *walletpb.StreamRequest_Sign:
+		var res *walletpb.SignResponse
+		res, err = r.client.Sign(ctx, req.Sign)
+		response.Response = &walletpb.StreamResponse_Sign{Sign: res}
+	case *walletpb.StreamRequest_SignChannelState:
+		var res *walletpb.SignResponse
+		res, err = r.client.SignChannelState(ctx, req.SignChannelState)
+		response.Response = &walletpb.StreamResponse_Sign{Sign: res}
+	case *walletpb.StreamRequest_Verify:
+		var res *walletpb.VerifyResponse
+		res, err = r.client.Verify(ctx, req.Verify)
+		response.Response = &walletpb.StreamResponse_Verify{Verify: res}
+	case *walletpb.StreamRequest_VerifyChannelState:
+		var res *walletpb.VerifyResponse
+		res, err = r.client.VerifyChannelState(ctx, req.VerifyChannelState)
+		response.Response = &walletpb.StreamResponse_Verify{Verify: res}
+	case *walletpb.StreamRequest_VerifyChannelStates:
+		var res *walletpb.VerifyChannelStatesResponse
+		res, err = r.client.VerifyChannelStates(ctx, req.VerifyChannelStates)
+		response.Response = &walletpb.StreamResponse_VerifyChannelStates{VerifyChannelStates: res}
+	case *walletpb.StreamRequest_KeyAvailable:
+		var res *walletpb.KeyAvailableResponse
+		res, err = r.client.KeyAvailable(ctx, req.KeyAvailable)
+		response.Response = &walletpb.StreamResponse_KeyAvailable{KeyAvailable: res}
+	case *walletpb.StreamRequest_LockKey:
+		var res *walletpb.KeyLockResponse
+		res, err = r.client.LockKey(ctx, req.LockKey)
+		response.Response = &walletpb.StreamResponse_KeyLock{KeyLock: res}
+	case *walletpb.StreamRequest_UnlockKey:
+		var res *walletpb.KeyLockResponse
+		res, err = r.client.UnlockKey(ctx, req.UnlockKey)
+		response.Response = &walletpb.StreamResponse_KeyLock{KeyLock: res}
+	case *walletpb.StreamRequest_ListAccounts:
+		var res *walletpb.ListAccountsResponse
+		res, err = r.client.ListAccounts(ctx, req.ListAccounts)
+		response.Response = &walletpb.StreamResponse_ListAccounts{ListAccounts: res}
+	case *walletpb.StreamRequest_CreateAccount:
+		var res *walletpb.AccountInfo
+		res, err = r.client.CreateAccount(ctx, req.CreateAccount)
+		response.Response = &walletpb.StreamResponse_Account{Account: res}
+	case *walletpb.StreamRequest_CalculateChannelId:
+		var res *walletpb.CalculateChannelIDResponse
+		res, err = r.client.CalculateChannelID(ctx, req.CalculateChannelId)
+		response.Response = &walletpb.StreamResponse_CalculateChannelId{CalculateChannelId: res}
+	}
+	if err != nil {
+		return nil, makeRPCError(endpoint, err)
+	}
+	return response, nil
+}
+
+// callStream sends the given request over the stream of the Remote and waits for its response. The timeout of the
+// endpoint applies to the whole call, i.e. to opening the stream, sending the request and receiving its response. The
+// stream is opened, if it is not open yet. If sending does not complete in time, the stream is reset, so that it does
+// not block later calls.
+func (r *Remote) callStream(endpoint string, request *walletpb.StreamRequest) (*walletpb.StreamResponse, error) {
+	ctx := context.Background()
+	if timeout := r.transport.Timeout(endpoint); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	responses := make(chan *walletpb.StreamResponse, 1)
+	r.mu.Lock()
+	if r.stream == nil {
+		if err := r.openStream(); err != nil {
+			r.mu.Unlock()
+			return nil, makeRPCError(endpoint, err)
+		}
+	}
+	s := r.stream
+	r.nextID++
+	request.Id = r.nextID
+	r.pending[request.Id] = responses
+	r.mu.Unlock()
+
+	sent := make(chan error, 1)
+	go func() {
+		s.sendMu.Lock()
+		defer s.sendMu.Unlock()
+		sent <- s.client.Send(request)
+	}()
+	select {
+	case <-sent:
+		// If sending failed, the receiving goroutine fails all pending calls, including this one.
+	case <-ctx.Done():
+		s.cancel()
+		return nil, r.expire(endpoint, request.Id)
+	}
+	select {
+	case response := <-responses:
+		return response, nil
+	case <-ctx.Done():
+		return nil, r.expire(endpoint, request.Id)
+	}
+}
+
+// expire removes the pending call with the given id and returns the error of a call that did not complete within the
+// timeout of its endpoint.
+func (r *Remote) expire(endpoint string, id uint64) error {
+	r.mu.Lock()
+	delete(r.pending, id)
+	r.mu.Unlock()
+	return &RPCError{Endpoint: endpoint, Code: codes.DeadlineExceeded, Message: "no response within timeout"}
+}
+
+// openStream opens a new stream and starts receiving its responses. The caller must hold r.mu.
+func (r *Remote) openStream() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	client, err := r.client.Stream(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	s := &stream{client: client, cancel: cancel}
+	r.stream = s
+	go r.receive(s)
+	return nil
+}
+
+// receive delivers the responses of the given stream to the pending calls. If the stream breaks, all pending calls
+// fail with its error.
+func (r *Remote) receive(s *stream) {
+	for {
+		response, err := s.client.Recv()
+		r.mu.Lock()
+		if err != nil {
+			s.cancel()
+			if r.stream == s {
+				r.stream = nil
+			}
+			e := makeRPCError("", err)
+			code, msg := codes.Unavailable, e.Error()
+			if rpcErr, ok := e.(*RPCError); ok {
+				code, msg = rpcErr.Code, rpcErr.Message
+			}
+			for id, responses := range r.pending {
+				responses <- &walletpb.StreamResponse{
+					Id:       id,
+					Response: &walletpb.StreamResponse_Error{Error: &walletpb.Error{Code: uint32(code), Message: msg}},
+				}
+				delete(r.pending, id)
+			}
+			r.mu.Unlock()
+			return
+		}
+		if responses, ok := r.pending[response.Id]; ok {
+			responses <- response
+			delete(r.pending, response.Id)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// bearerCredentials authenticates every call with a bearer token.
+type bearerCredentials struct {
+	token transport.BearerToken
+	tls   bool
+}
+
+func (c bearerCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(c.token)}, nil
+}
+
+func (c bearerCredentials) RequireTransportSecurity() bool {
+	return c.tls
+}
+
+var _ wallet.Remote = &Remote{}
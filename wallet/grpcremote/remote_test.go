@@ -0,0 +1,308 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcremote_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math/big"
+	"math/rand"
+	"net"
+	gptest "perun.network/go-perun/wallet/test"
+	channeltest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/grpcremote"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wallet/walletpb"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingRemote records the request bodies received by the wrapped remote.
+type recordingRemote struct {
+	wallet.Remote
+	mu     sync.Mutex
+	bodies map[string]interface{}
+}
+
+func (r *recordingRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	r.mu.Lock()
+	r.bodies[endpoint] = body
+	r.mu.Unlock()
+	return r.Remote.CallEndpoint(endpoint, body, result)
+}
+
+func (r *recordingRemote) body(endpoint string) interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies[endpoint]
+}
+
+// remoteFunc is a wallet.Remote calling the function.
+type remoteFunc func(endpoint string, body interface{}, result interface{}) error
+
+func (f remoteFunc) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	return f(endpoint, body, result)
+}
+
+// serve serves the given remote over gRPC and returns the address of the server.
+func serve(t *testing.T, remote wallet.Remote, opts ...grpc.ServerOption) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(opts...)
+	walletpb.RegisterWalletServer(server, grpcremote.NewService(remote))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+type remoteConstructor func(target string, opts ...transport.Option) (*grpcremote.Remote, error)
+
+// forEachMode runs the given test with a unary and a streaming Remote.
+func forEachMode(t *testing.T, test func(t *testing.T, newRemote remoteConstructor)) {
+	t.Run("Unary", func(t *testing.T) { test(t, grpcremote.NewRemote) })
+	t.Run("Streaming", func(t *testing.T) { test(t, grpcremote.NewStreamingRemote) })
+}
+
+func newRemote(t *testing.T, newRemote remoteConstructor, target string, opts ...transport.Option) *grpcremote.Remote {
+	r, err := newRemote(target, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func setup(t *testing.T, rng *rand.Rand, newRemoteFn remoteConstructor) *gptest.Setup {
+	generic := test.NewGenericRemote([]address.Address{test.MakeRandomAddress(rng)}, rng)
+	r := newRemote(t, newRemoteFn, serve(t, generic))
+	return test.NewSetup(rng, r, generic.AvailableAddresses[0])
+}
+
+func TestRemote_AccountWithWalletAndBackend(t *testing.T) {
+	forEachMode(t, func(t *testing.T, newRemote remoteConstructor) {
+		gptest.TestAccountWithWalletAndBackend(t, setup(t, pkgtest.Prng(t), newRemote))
+	})
+}
+
+func TestRemote_Requests(t *testing.T) {
+	forEachMode(t, func(t *testing.T, newRemoteFn remoteConstructor) {
+		rng := pkgtest.Prng(t)
+		addr := test.MakeRandomSchemeAddress(rng)
+		generic := test.NewGenericRemote([]address.Address{addr}, rng)
+		recorder := &recordingRemote{Remote: generic, bodies: make(map[string]interface{})}
+		r := newRemote(t, newRemoteFn, serve(t, recorder))
+		state := channeltest.MakeRandomChannelState(rng)
+
+		var sig wire.SigningResponse
+		request := wire.MakeChannelStateSigningRequest(addr, state)
+		require.NoError(t, r.CallEndpoint(wallet.EndpointSignChannelState, request, &sig))
+		require.Equal(t, request, recorder.body(wallet.EndpointSignChannelState))
+		rawSig, err := sig.DecodeWithLength(len(sig.Hex) / 2)
+		require.NoError(t, err)
+
+		var valid wire.VerificationResponse
+		verification := wire.MakeChannelStateVerificationRequest(rawSig, addr, state)
+		require.NoError(t, r.CallEndpoint(wallet.EndpointVerifyChannelStateSignature, verification, &valid))
+		require.Equal(t, verification, recorder.body(wallet.EndpointVerifyChannelStateSignature))
+		require.True(t, valid)
+
+		var results wire.BatchVerificationResponse
+		batch := wire.BatchVerificationRequest{Requests: []wire.ChannelStateVerificationRequest{
+			verification,
+			wire.MakeChannelStateVerificationRequest(test.MakeRandomSignature(rng), addr, state),
+		}}
+		require.NoError(t, r.CallEndpoint(wallet.EndpointVerifyChannelStateSignatures, batch, &results))
+		require.Equal(t, batch, recorder.body(wallet.EndpointVerifyChannelStateSignatures))
+		require.Equal(t, wire.BatchVerificationResponse{true, false}, results)
+
+		var available wire.KeyAvailabilityResponse
+		keyRequest := wire.MakeKeyAvailabilityRequest(addr)
+		require.NoError(t, r.CallEndpoint(wallet.EndpointKeyAvailable, keyRequest, &available))
+		require.Equal(t, keyRequest, recorder.body(wallet.EndpointKeyAvailable))
+		require.True(t, available)
+
+		var created wire.CreateAccountResponse
+		create := wire.MakeCreateAccountRequest(address.Ed25519, "wallet")
+		require.NoError(t, r.CallEndpoint(wallet.EndpointCreateAccount, create, &created))
+		require.Equal(t, create, recorder.body(wallet.EndpointCreateAccount))
+		require.Equal(t, "wallet", created.WalletID)
+
+		var accounts wire.ListAccountsResponse
+		require.NoError(t, r.CallEndpoint(wallet.EndpointListAccounts, wire.ListAccountsRequest{}, &accounts))
+		require.Equal(t, wire.ListAccountsResponse{wire.MakeAccountInfo(addr, ""), created}, accounts)
+
+		params := types.ChannelParameters{
+			Parties: []address.Address{
+				test.MakeRandomAddressWithStakeCredential(rng),
+				test.MakeRandomMultiSigAddress(rng, 3),
+				test.MakeRandomAddress(rng),
+			},
+			Nonce:   big.NewInt(rng.Int63()),
+			Timeout: time.Hour,
+		}
+		var id, expectedID wire.ChannelID
+		require.NoError(t, r.CallEndpoint(wallet.EndpointCalculateChannelID, wire.MakeChannelParameters(params), &id))
+		require.Equal(t, wire.MakeChannelParameters(params), recorder.body(wallet.EndpointCalculateChannelID))
+		require.NoError(t, generic.CallEndpoint(wallet.EndpointCalculateChannelID, wire.MakeChannelParameters(params), &expectedID))
+		require.Equal(t, expectedID, id)
+	})
+}
+
+func TestRemote_Concurrent(t *testing.T) {
+	forEachMode(t, func(t *testing.T, newRemoteFn remoteConstructor) {
+		rng := pkgtest.Prng(t)
+		addr := test.MakeRandomAddress(rng)
+		r := newRemote(t, newRemoteFn, serve(t, test.NewGenericRemote([]address.Address{addr}, rng)))
+		acc := test.MakeRemoteAccount(addr, r)
+		var wg sync.WaitGroup
+		errs := make(chan error, 16)
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := acc.SignData([]byte(fmt.Sprintf("message %d", i)))
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestRemote_Errors(t *testing.T) {
+	forEachMode(t, func(t *testing.T, newRemoteFn remoteConstructor) {
+		rng := pkgtest.Prng(t)
+		addr := test.MakeRandomAddress(rng)
+		failing := remoteFunc(func(string, interface{}, interface{}) error {
+			return &wallet.KeyUnavailableError{Address: addr}
+		})
+		r := newRemote(t, newRemoteFn, serve(t, failing))
+		var valid bool
+		err := r.CallEndpoint(wallet.EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(addr), &valid)
+		var rpcErr *grpcremote.RPCError
+		require.True(t, errors.As(err, &rpcErr))
+		require.Equal(t, codes.FailedPrecondition, rpcErr.Code)
+		require.Equal(t, wallet.EndpointKeyAvailable, rpcErr.Endpoint)
+		require.Equal(t, errclass.Client, errclass.Of(err))
+
+		err = r.CallEndpoint("/unknown", nil, &valid)
+		require.Error(t, err)
+	})
+}
+
+func TestRemote_Deadline(t *testing.T) {
+	forEachMode(t, func(t *testing.T, newRemoteFn remoteConstructor) {
+		rng := pkgtest.Prng(t)
+		addr := test.MakeRandomAddress(rng)
+		release := make(chan struct{})
+		defer close(release)
+		blocking := remoteFunc(func(string, interface{}, interface{}) error {
+			<-release
+			return nil
+		})
+		r := newRemote(t, newRemoteFn, serve(t, blocking),
+			transport.WithEndpointTimeout(wallet.EndpointSignData, 20*time.Millisecond))
+		var sig wire.SigningResponse
+		err := r.CallEndpoint(wallet.EndpointSignData, wire.MakeSigningRequest(addr, []byte("message")), &sig)
+		var rpcErr *grpcremote.RPCError
+		require.True(t, errors.As(err, &rpcErr))
+		require.Equal(t, codes.DeadlineExceeded, rpcErr.Code)
+		require.Equal(t, errclass.Network, errclass.Of(err))
+	})
+}
+
+func TestRemote_StreamSendDeadline(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	release := make(chan struct{})
+	defer close(release)
+	// The server never reads from the stream, so sending a request larger than the flow control window blocks. A fixed
+	// window size disables the dynamic window of the server.
+	fixedWindow := grpc.InitialWindowSize(1 << 16)
+	blocking := grpc.StreamInterceptor(
+		func(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, grpc.StreamHandler) error {
+			<-release
+			return nil
+		},
+	)
+	r := newRemote(t, grpcremote.NewStreamingRemote, serve(t, test.NewGenericRemote(nil, rng), blocking, fixedWindow),
+		transport.WithEndpointTimeout(wallet.EndpointSignData, 50*time.Millisecond))
+	message := make([]byte, 1<<20)
+	// The first request exhausts the write quota of the stream, so that sending the second request blocks.
+	for i := 0; i < 2; i++ {
+		var sig wire.SigningResponse
+		err := r.CallEndpoint(wallet.EndpointSignData, wire.MakeSigningRequest(addr, message), &sig)
+		var rpcErr *grpcremote.RPCError
+		require.True(t, errors.As(err, &rpcErr), "unexpected error: %v", err)
+		require.Equal(t, codes.DeadlineExceeded, rpcErr.Code)
+	}
+}
+
+func TestRemote_BearerToken(t *testing.T) {
+	const token = "secret-token"
+	authorize := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("authorization"); len(values) != 1 || values[0] != "Bearer "+token {
+			return status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return nil
+	}
+	unary := grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	})
+	stream := grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	})
+	forEachMode(t, func(t *testing.T, newRemoteFn remoteConstructor) {
+		rng := pkgtest.Prng(t)
+		addr := test.MakeRandomAddress(rng)
+		target := serve(t, test.NewGenericRemote([]address.Address{addr}, rng), unary, stream)
+
+		var available bool
+		r := newRemote(t, newRemoteFn, target, transport.WithBearerToken(token))
+		require.NoError(t, r.CallEndpoint(wallet.EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(addr), &available))
+		require.True(t, available)
+
+		r = newRemote(t, newRemoteFn, target, transport.WithBearerToken("wrong"))
+		err := r.CallEndpoint(wallet.EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(addr), &available)
+		var rpcErr *grpcremote.RPCError
+		require.True(t, errors.As(err, &rpcErr))
+		require.Equal(t, codes.Unauthenticated, rpcErr.Code)
+	})
+
+	_, err := grpcremote.NewRemote("localhost:0", transport.WithHMAC("key", []byte("secret")))
+	require.Error(t, err, "HMAC authentication is not supported over gRPC")
+}
@@ -0,0 +1,154 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcremote
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/walletpb"
+	"sync"
+)
+
+// Service is a gRPC wallet server that serves the given wallet.Remote (e.g. a PerunCardanoWallet to offer a json api
+// wallet server over gRPC). Requests received over a Wallet.Stream are handled concurrently.
+type Service struct {
+	walletpb.UnimplementedWalletServer
+	remote wallet.Remote
+}
+
+// NewService returns a new Service for the given wallet.Remote. Register it with walletpb.RegisterWalletServer.
+func NewService(remote wallet.Remote) *Service {
+	return &Service{remote: remote}
+}
+
+// handle calls the endpoint of the given request on the wallet.Remote.
+func (s *Service) handle(request *walletpb.StreamRequest) (*walletpb.StreamResponse, error) {
+	endpoint, body, result, err := decodeStreamRequest(request)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if err = s.remote.CallEndpoint(endpoint, body, result); err != nil {
+		return nil, status.Error(statusOf(err), err.Error())
+	}
+	response, err := makeStreamResponse(request.Id, endpoint, result)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid response: %v", err)
+	}
+	return response, nil
+}
+
+func (s *Service) Sign(_ context.Context, request *walletpb.SignRequest) (*walletpb.SignResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{Request: &walletpb.StreamRequest_Sign{Sign: request}})
+	return response.GetSign(), err
+}
+
+func (s *Service) SignChannelState(_ context.Context, request *walletpb.SignChannelStateRequest) (*walletpb.SignResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_SignChannelState{SignChannelState: request},
+	})
+	return response.GetSign(), err
+}
+
+func (s *Service) Verify(_ context.Context, request *walletpb.VerifyRequest) (*walletpb.VerifyResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{Request: &walletpb.StreamRequest_Verify{Verify: request}})
+	return response.GetVerify(), err
+}
+
+func (s *Service) VerifyChannelState(_ context.Context, request *walletpb.VerifyChannelStateRequest) (*walletpb.VerifyResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_VerifyChannelState{VerifyChannelState: request},
+	})
+	return response.GetVerify(), err
+}
+
+func (s *Service) VerifyChannelStates(_ context.Context, request *walletpb.VerifyChannelStatesRequest) (*walletpb.VerifyChannelStatesResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_VerifyChannelStates{VerifyChannelStates: request},
+	})
+	return response.GetVerifyChannelStates(), err
+}
+
+func (s *Service) KeyAvailable(_ context.Context, request *walletpb.KeyAvailableRequest) (*walletpb.KeyAvailableResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_KeyAvailable{KeyAvailable: request},
+	})
+	return response.GetKeyAvailable(), err
+}
+
+func (s *Service) LockKey(_ context.Context, request *walletpb.KeyLockRequest) (*walletpb.KeyLockResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{Request: &walletpb.StreamRequest_LockKey{LockKey: request}})
+	return response.GetKeyLock(), err
+}
+
+func (s *Service) UnlockKey(_ context.Context, request *walletpb.KeyLockRequest) (*walletpb.KeyLockResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{Request: &walletpb.StreamRequest_UnlockKey{UnlockKey: request}})
+	return response.GetKeyLock(), err
+}
+
+func (s *Service) ListAccounts(_ context.Context, request *walletpb.ListAccountsRequest) (*walletpb.ListAccountsResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_ListAccounts{ListAccounts: request},
+	})
+	return response.GetListAccounts(), err
+}
+
+func (s *Service) CreateAccount(_ context.Context, request *walletpb.CreateAccountRequest) (*walletpb.AccountInfo, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_CreateAccount{CreateAccount: request},
+	})
+	return response.GetAccount(), err
+}
+
+func (s *Service) CalculateChannelID(_ context.Context, request *walletpb.ChannelParameters) (*walletpb.CalculateChannelIDResponse, error) {
+	response, err := s.handle(&walletpb.StreamRequest{
+		Request: &walletpb.StreamRequest_CalculateChannelId{CalculateChannelId: request},
+	})
+	return response.GetCalculateChannelId(), err
+}
+
+// Stream handles the requests received over the given stream concurrently. Failed requests are answered with an
+// error response, so the stream stays open.
+func (s *Service) Stream(stream walletpb.Wallet_StreamServer) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	var sendMu sync.Mutex
+	for {
+		request, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := s.handle(request)
+			if err != nil {
+				st, _ := status.FromError(err)
+				response = &walletpb.StreamResponse{
+					Id: request.Id,
+					Response: &walletpb.StreamResponse_Error{
+						Error: &walletpb.Error{Code: uint32(st.Code()), Message: st.Message()},
+					},
+				}
+			}
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			_ = stream.Send(response)
+		}()
+	}
+}
+
+var _ walletpb.WalletServer = &Service{}
@@ -0,0 +1,73 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyfile
+
+import (
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+// Account is a cardano account whose signing key is held locally instead of by a wallet server. Channel states are
+// signed on the local serialization of the contract's ChannelState (see wire.MakeChannelStateMessage), whereas a
+// wallet.RemoteAccount lets the wallet server serialize them. The signatures are only valid on-chain, if both
+// serializations are equal, which is not checked at runtime. Set PERUN_CARDANO_WALLET_URL to check it against a wallet
+// server in the tests.
+type Account struct {
+	AccountAddress address.Address
+	key            *SigningKey
+}
+
+// NewAccount returns a new Account for the given signing key. Set a stake credential on AccountAddress to receive
+// payouts at the base address of the key, which requires a contract that supports payout addresses (see
+// wire.ChannelParameters).
+func NewAccount(key *SigningKey) (*Account, error) {
+	addr, err := key.Address()
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		AccountAddress: addr,
+		key:            key,
+	}, nil
+}
+
+// LoadAccount returns a new Account for the (extended) payment signing key file at the given path (e.g.
+// `payment.skey`).
+func LoadAccount(path string) (*Account, error) {
+	key, err := LoadSigningKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewAccount(key)
+}
+
+// Address returns the Address associated with this account.
+func (a *Account) Address() wallet.Address {
+	return &a.AccountAddress
+}
+
+// SignData signs arbitrary data with this account.
+func (a *Account) SignData(data []byte) (wallet.Sig, error) {
+	return a.key.Sign(data), nil
+}
+
+// SignChannelState signs the given channel state with this account.
+func (a *Account) SignChannelState(channelState types.ChannelState) (wallet.Sig, error) {
+	return a.key.Sign(wire.MakeChannelStateMessage(channelState)), nil
+}
+
+var _ types.ChannelStateSigningAccount = &Account{}
@@ -0,0 +1,194 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyfile
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"filippo.io/edwards25519"
+	"fmt"
+	"perun.network/perun-cardano-backend/wallet/address"
+)
+
+const (
+	// ExtendedVerificationKeyLength is the length of an extended verification key: the public key followed by the
+	// chain code.
+	ExtendedVerificationKeyLength = address.PubKeyLength + chainCodeLength
+	// ExtendedSigningKeyLength is the length of an extended signing key as written by cardano-cli: the 64 byte
+	// Ed25519-BIP32 extended private key (kL || kR), followed by the public key and the chain code.
+	ExtendedSigningKeyLength = 2*extendedScalarLength + address.PubKeyLength + chainCodeLength
+
+	chainCodeLength      = 32
+	extendedScalarLength = 32
+)
+
+// ParseVerificationKey returns the Address of the (extended) payment verification key in the given TextEnvelope. The
+// payment public key hash is the hash of the key.
+func ParseVerificationKey(envelope TextEnvelope) (address.Address, error) {
+	pubKey, err := verificationKey(envelope, PaymentVerificationKeyType, PaymentExtendedVerificationKeyType)
+	if err != nil {
+		return address.Address{}, err
+	}
+	return address.MakeAddressFromSinglePubKey(pubKey)
+}
+
+// LoadVerificationKey returns the Address of the (extended) payment verification key file at the given path (e.g.
+// `payment.vkey`).
+func LoadVerificationKey(path string) (address.Address, error) {
+	envelope, err := ReadTextEnvelope(path)
+	if err != nil {
+		return address.Address{}, err
+	}
+	return ParseVerificationKey(envelope)
+}
+
+// ParseStakeVerificationKey returns the key hash stake credential of the (extended) stake verification key in the given
+// TextEnvelope (see address.Address.SetStakeCredential).
+func ParseStakeVerificationKey(envelope TextEnvelope) (address.Credential, error) {
+	pubKey, err := verificationKey(envelope, StakeVerificationKeyType, StakeExtendedVerificationKeyType)
+	if err != nil {
+		return address.Credential{}, err
+	}
+	var key [address.PubKeyLength]byte
+	copy(key[:], pubKey)
+	hash, err := address.CalculatePubKeyHash(key)
+	if err != nil {
+		return address.Credential{}, err
+	}
+	return address.MakeKeyHashCredential(hash), nil
+}
+
+// LoadStakeVerificationKey returns the key hash stake credential of the (extended) stake verification key file at the
+// given path (e.g. `stake.vkey`).
+func LoadStakeVerificationKey(path string) (address.Credential, error) {
+	envelope, err := ReadTextEnvelope(path)
+	if err != nil {
+		return address.Credential{}, err
+	}
+	return ParseStakeVerificationKey(envelope)
+}
+
+// verificationKey returns the public key of the given TextEnvelope, which must be of the given plain or extended
+// verification key type. The chain code of extended keys is dropped.
+func verificationKey(envelope TextEnvelope, plainType, extendedType string) ([]byte, error) {
+	switch envelope.Type {
+	case plainType:
+		return envelope.RawKey(address.PubKeyLength)
+	case extendedType:
+		key, err := envelope.RawKey(ExtendedVerificationKeyLength)
+		if err != nil {
+			return nil, err
+		}
+		return key[:address.PubKeyLength], nil
+	default:
+		return nil, fmt.Errorf("TextEnvelope of type %s is no %s", envelope.Type, plainType)
+	}
+}
+
+// SigningKey is a payment signing key loaded from a cardano-cli key file. It is either a plain Ed25519 key or an
+// Ed25519-BIP32 extended key.
+type SigningKey struct {
+	pubKey [address.PubKeyLength]byte
+	// privateKey is set for plain Ed25519 keys.
+	privateKey ed25519.PrivateKey
+	// scalar and prefix are kL and kR of extended keys.
+	scalar *edwards25519.Scalar
+	prefix []byte
+}
+
+// ParseSigningKey returns the (extended) payment signing key in the given TextEnvelope.
+func ParseSigningKey(envelope TextEnvelope) (*SigningKey, error) {
+	switch envelope.Type {
+	case PaymentSigningKeyType:
+		seed, err := envelope.RawKey(ed25519.SeedSize)
+		if err != nil {
+			return nil, err
+		}
+		key := &SigningKey{privateKey: ed25519.NewKeyFromSeed(seed)}
+		copy(key.pubKey[:], key.privateKey.Public().(ed25519.PublicKey))
+		return key, nil
+	case PaymentExtendedSigningKeyType:
+		raw, err := envelope.RawKey(ExtendedSigningKeyLength)
+		if err != nil {
+			return nil, err
+		}
+		return makeExtendedSigningKey(raw)
+	default:
+		return nil, fmt.Errorf("TextEnvelope of type %s is no %s", envelope.Type, PaymentSigningKeyType)
+	}
+}
+
+// LoadSigningKey returns the (extended) payment signing key of the key file at the given path (e.g. `payment.skey`).
+func LoadSigningKey(path string) (*SigningKey, error) {
+	envelope, err := ReadTextEnvelope(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSigningKey(envelope)
+}
+
+// makeExtendedSigningKey returns the SigningKey of the given raw extended signing key and checks that its public key
+// belongs to its private key.
+func makeExtendedSigningKey(raw []byte) (*SigningKey, error) {
+	// kL is a little-endian integer that may exceed the group order, so it is reduced modulo the group order. This
+	// does not change kL * B, because B is of prime order.
+	wide := make([]byte, 64)
+	copy(wide, raw[:extendedScalarLength])
+	scalar, err := edwards25519.NewScalar().SetUniformBytes(wide)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extended private key: %w", err)
+	}
+	key := &SigningKey{
+		scalar: scalar,
+		prefix: append([]byte{}, raw[extendedScalarLength:2*extendedScalarLength]...),
+	}
+	copy(key.pubKey[:], raw[2*extendedScalarLength:])
+	if string(new(edwards25519.Point).ScalarBaseMult(scalar).Bytes()) != string(key.pubKey[:]) {
+		return nil, errors.New("public key of extended signing key does not match its private key")
+	}
+	return key, nil
+}
+
+// GetPubKey returns the public key of this signing key.
+func (k *SigningKey) GetPubKey() [address.PubKeyLength]byte {
+	return k.pubKey
+}
+
+// Address returns the Address of this signing key. The payment public key hash is the hash of the public key.
+func (k *SigningKey) Address() (address.Address, error) {
+	return address.MakeAddressFromSinglePubKey(k.pubKey[:])
+}
+
+// Sign returns the Ed25519 signature of the given message. Signatures of extended keys are created as specified by
+// Ed25519-BIP32 and are verified like any other Ed25519 signature.
+func (k *SigningKey) Sign(msg []byte) []byte {
+	if k.privateKey != nil {
+		return ed25519.Sign(k.privateKey, msg)
+	}
+	h := sha512.New()
+	h.Write(k.prefix)
+	h.Write(msg)
+	r, _ := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	encodedR := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h.Reset()
+	h.Write(encodedR)
+	h.Write(k.pubKey[:])
+	h.Write(msg)
+	c, _ := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	s := edwards25519.NewScalar().MultiplyAdd(c, k.scalar, r)
+	return append(encodedR, s.Bytes()...)
+}
@@ -0,0 +1,202 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyfile_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"filippo.io/edwards25519"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"path/filepath"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/keyfile"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+// makeExtendedSigningKey returns a random raw extended signing key as written by cardano-cli.
+func makeExtendedSigningKey(rng *rand.Rand) []byte {
+	raw := test.GetRandomByteSlice(keyfile.ExtendedSigningKeyLength, keyfile.ExtendedSigningKeyLength, rng)
+	// Clamp kL as specified by Ed25519-BIP32.
+	raw[0] &= 0xf8
+	raw[31] &= 0x1f
+	raw[31] |= 0x40
+	wide := make([]byte, 64)
+	copy(wide, raw[:32])
+	scalar, err := edwards25519.NewScalar().SetUniformBytes(wide)
+	if err != nil {
+		panic(err)
+	}
+	copy(raw[64:96], new(edwards25519.Point).ScalarBaseMult(scalar).Bytes())
+	return raw
+}
+
+func TestTextEnvelope_CardanoCLI(t *testing.T) {
+	// Key files as written by cardano-cli for the RFC 8032 test vector 1 key.
+	const vkey = `{
+    "type": "PaymentVerificationKeyShelley_ed25519",
+    "description": "Payment Verification Key",
+    "cborHex": "5820d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+}`
+	const skey = `{
+    "type": "PaymentSigningKeyShelley_ed25519",
+    "description": "Payment Signing Key",
+    "cborHex": "58209d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+}`
+	envelope, err := keyfile.ParseTextEnvelope([]byte(vkey))
+	require.NoError(t, err, "unable to parse verification key file")
+	addr, err := keyfile.ParseVerificationKey(envelope)
+	require.NoError(t, err, "unable to parse verification key")
+	require.Equal(t,
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		hex.EncodeToString(addr.GetPubKeySlice()),
+		"public key is not as expected",
+	)
+	expectedHash, err := address.CalculatePubKeyHash(addr.GetPubKey())
+	require.NoError(t, err, "this should not fail!")
+	require.Equal(t, expectedHash, addr.GetPubKeyHash(), "payment public key hash is not the hash of the key")
+
+	envelope, err = keyfile.ParseTextEnvelope([]byte(skey))
+	require.NoError(t, err, "unable to parse signing key file")
+	key, err := keyfile.ParseSigningKey(envelope)
+	require.NoError(t, err, "unable to parse signing key")
+	require.Equal(t, addr.GetPubKey(), key.GetPubKey(), "public key of signing key is not as expected")
+	require.Equal(t,
+		"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+		hex.EncodeToString(key.Sign(nil)),
+		"signature is not as expected",
+	)
+}
+
+func TestLoadAccount(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	dir := t.TempDir()
+	seed := test.GetRandomByteSlice(ed25519.SeedSize, ed25519.SeedSize, rng)
+	extended := makeExtendedSigningKey(rng)
+	keys := []struct {
+		name   string
+		vkey   keyfile.TextEnvelope
+		skey   keyfile.TextEnvelope
+		pubKey []byte
+	}{
+		{
+			"plain",
+			keyfile.MakeTextEnvelope(keyfile.PaymentVerificationKeyType, "", ed25519.NewKeyFromSeed(seed)[32:]),
+			keyfile.MakeTextEnvelope(keyfile.PaymentSigningKeyType, "", seed),
+			ed25519.NewKeyFromSeed(seed)[32:],
+		},
+		{
+			"extended",
+			keyfile.MakeTextEnvelope(keyfile.PaymentExtendedVerificationKeyType, "", extended[64:]),
+			keyfile.MakeTextEnvelope(keyfile.PaymentExtendedSigningKeyType, "", extended),
+			extended[64:96],
+		},
+	}
+	for _, k := range keys {
+		vkeyPath := filepath.Join(dir, k.name+".vkey")
+		skeyPath := filepath.Join(dir, k.name+".skey")
+		require.NoError(t, k.vkey.WriteFile(vkeyPath), "unable to write verification key file")
+		require.NoError(t, k.skey.WriteFile(skeyPath), "unable to write signing key file")
+
+		addr, err := keyfile.LoadVerificationKey(vkeyPath)
+		require.NoErrorf(t, err, "unable to load %s verification key", k.name)
+		require.Equalf(t, k.pubKey, addr.GetPubKeySlice(), "public key of %s verification key is not as expected", k.name)
+		account, err := keyfile.LoadAccount(skeyPath)
+		require.NoErrorf(t, err, "unable to load %s signing key", k.name)
+		require.Truef(t, addr.Equal(account.Address()), "address of %s signing key is not as expected", k.name)
+
+		msg := test.GetRandomByteSlice(0, 0x100, rng)
+		sig, err := account.SignData(msg)
+		require.NoError(t, err, "unable to sign data")
+		require.Truef(t, ed25519.Verify(k.pubKey, msg, sig), "data signature of %s key is invalid", k.name)
+
+		state := ctest.MakeRandomChannelState(rng)
+		signingAccount, err := wallet.MakeRemoteBackend(test.NewMockRemote(rng)).ToChannelStateSigningAccount(account)
+		require.NoError(t, err, "account should be a ChannelStateSigningAccount")
+		sig, err = signingAccount.SignChannelState(state)
+		require.NoError(t, err, "unable to sign channel state")
+		require.Truef(
+			t,
+			ed25519.Verify(k.pubKey, wire.MakeChannelStateMessage(state), sig),
+			"channel state signature of %s key is invalid",
+			k.name,
+		)
+	}
+}
+
+func TestParseStakeVerificationKey(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	pubKey := test.MakeRandomAddress(rng).GetPubKey()
+	expected, err := address.CalculatePubKeyHash(pubKey)
+	require.NoError(t, err, "this should not fail!")
+	for _, envelope := range []keyfile.TextEnvelope{
+		keyfile.MakeTextEnvelope(keyfile.StakeVerificationKeyType, "", pubKey[:]),
+		keyfile.MakeTextEnvelope(
+			keyfile.StakeExtendedVerificationKeyType,
+			"",
+			append(pubKey[:], test.GetRandomByteSlice(32, 32, rng)...),
+		),
+	} {
+		credential, err := keyfile.ParseStakeVerificationKey(envelope)
+		require.NoError(t, err, "unable to parse stake verification key")
+		require.Equal(t, address.MakeKeyHashCredential(expected), credential, "stake credential is not as expected")
+	}
+	_, err = keyfile.ParseStakeVerificationKey(keyfile.MakeTextEnvelope(keyfile.PaymentVerificationKeyType, "", pubKey[:]))
+	require.Error(t, err, "failed to error on payment verification key")
+}
+
+func TestParse_Invalid(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	_, err := keyfile.ParseTextEnvelope([]byte(`{"description": "", "cborHex": "5820"}`))
+	require.Error(t, err, "failed to error on TextEnvelope without type")
+	_, err = keyfile.ParseTextEnvelope([]byte(`not json`))
+	require.Error(t, err, "failed to error on invalid json")
+	_, err = keyfile.LoadSigningKey(filepath.Join(t.TempDir(), "missing.skey"))
+	require.Error(t, err, "failed to error on missing key file")
+
+	seed := test.GetRandomByteSlice(ed25519.SeedSize, ed25519.SeedSize, rng)
+	_, err = keyfile.ParseVerificationKey(keyfile.MakeTextEnvelope(keyfile.PaymentSigningKeyType, "", seed))
+	require.Error(t, err, "failed to error on signing key as verification key")
+	_, err = keyfile.ParseSigningKey(keyfile.MakeTextEnvelope(keyfile.PaymentVerificationKeyType, "", seed))
+	require.Error(t, err, "failed to error on verification key as signing key")
+	_, err = keyfile.ParseSigningKey(keyfile.MakeTextEnvelope(keyfile.PaymentSigningKeyType, "", seed[1:]))
+	require.Error(t, err, "failed to error on signing key of invalid length")
+	_, err = keyfile.ParseSigningKey(keyfile.TextEnvelope{Type: keyfile.PaymentSigningKeyType, CborHex: "4100"})
+	require.Error(t, err, "failed to error on invalid cborHex")
+
+	extended := makeExtendedSigningKey(rng)
+	extended[64] ^= 0x01
+	_, err = keyfile.ParseSigningKey(keyfile.MakeTextEnvelope(keyfile.PaymentExtendedSigningKeyType, "", extended))
+	require.Error(t, err, "failed to error on extended signing key with mismatching public key")
+}
+
+func TestAccount_SignChannelState_WalletServer(t *testing.T) {
+	backend := wallet.MakeRemoteBackend(wallet.NewPerunCardanoWallet(test.WalletServerURL(t)))
+	rng := pkgtest.Prng(t)
+	key, _ := makeSigningKey(t, rng)
+	account, err := keyfile.NewAccount(key)
+	require.NoError(t, err)
+	state := ctest.MakeRandomChannelState(rng)
+	sig, err := account.SignChannelState(state)
+	require.NoError(t, err)
+	valid, err := backend.VerifyChannelStateSignature(state, sig, account.Address())
+	require.NoError(t, err)
+	require.True(t, valid, "wallet server does not verify channel state signature of local account")
+}
@@ -0,0 +1,404 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyfile
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+	"sort"
+	"sync"
+)
+
+// SigningKeyExtension is the file extension of the signing key files loaded by LoadRemote.
+const SigningKeyExtension = ".skey"
+
+// Remote is a wallet.Remote holding Ed25519 payment signing keys locally. It implements the perun-cardano-wallet
+// endpoints, so it can be served as a wallet server (see wallet.NewServer) or used directly in place of a
+// wallet.PerunCardanoWallet. Channel states are signed as wire.MakeChannelStateMessage, like Account signs them.
+// Signatures of addresses of the signature schemes of the Remote are verified (see SetSignatureSchemes). Remote is safe
+// for concurrent use.
+type Remote struct {
+	mu sync.Mutex
+	// dir is the directory new keys are written to. If it is empty, new keys are only held in memory.
+	dir      string
+	keys     map[[address.PubKeyLength]byte]*SigningKey
+	order    [][address.PubKeyLength]byte
+	walletID string
+	locked   map[[address.PubKeyLength]byte]bool
+	schemes  wallet.SignatureSchemes
+}
+
+// NewRemote returns a new Remote holding the given signing keys.
+func NewRemote(keys ...*SigningKey) *Remote {
+	r := &Remote{
+		keys:   make(map[[address.PubKeyLength]byte]*SigningKey),
+		locked: make(map[[address.PubKeyLength]byte]bool),
+	}
+	for _, key := range keys {
+		r.AddKey(key)
+	}
+	return r
+}
+
+// SetSignatureSchemes sets the signature schemes with which the Remote verifies signatures. By default, only the
+// built-in schemes are supported (see wallet.SignatureSchemes).
+func (r *Remote) SetSignatureSchemes(schemes wallet.SignatureSchemes) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemes = schemes
+}
+
+// LoadRemote returns a new Remote holding the (extended) payment signing keys of all key files with the extension
+// SigningKeyExtension in the given directory (e.g. `payment.skey` files written by cardano-cli). Keys created with the
+// wallet.EndpointCreateAccount endpoint are written to the directory.
+func LoadRemote(dir string) (*Remote, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+SigningKeyExtension))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list key files: %w", err)
+	}
+	sort.Strings(files)
+	r := NewRemote()
+	r.dir = dir
+	for _, file := range files {
+		key, err := LoadSigningKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load key file %s: %w", file, err)
+		}
+		r.AddKey(key)
+	}
+	return r, nil
+}
+
+// SetWalletID sets the cardano wallet id reported for the accounts of this Remote.
+func (r *Remote) SetWalletID(walletID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.walletID = walletID
+}
+
+// AddKey makes the given signing key available in this Remote.
+func (r *Remote) AddKey(key *SigningKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pubKey := key.GetPubKey()
+	if _, ok := r.keys[pubKey]; !ok {
+		r.order = append(r.order, pubKey)
+	}
+	r.keys[pubKey] = key
+}
+
+// Addresses returns the addresses of the keys held by this Remote in the order they were added.
+func (r *Remote) Addresses() []address.Address {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addrs := make([]address.Address, 0, len(r.order))
+	for _, pubKey := range r.order {
+		if addr, err := address.MakeAddressFromSinglePubKey(pubKey[:]); err == nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// CallEndpoint handles a request to the given perun-cardano-wallet endpoint. The body and result must be the wire
+// request value and a pointer to the wire response of the endpoint (see wallet.PerunCardanoWallet).
+func (r *Remote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	var ok bool
+	switch endpoint {
+	case wallet.EndpointSignData:
+		var request wire.SigningRequest
+		var response *wire.SigningResponse
+		if request, ok = body.(wire.SigningRequest); ok {
+			if response, ok = result.(*wire.SigningResponse); ok {
+				return r.signData(request, response)
+			}
+		}
+	case wallet.EndpointSignChannelState:
+		var request wire.ChannelStateSigningRequest
+		var response *wire.SigningResponse
+		if request, ok = body.(wire.ChannelStateSigningRequest); ok {
+			if response, ok = result.(*wire.SigningResponse); ok {
+				return r.signChannelState(request, response)
+			}
+		}
+	case wallet.EndpointVerifyDataSignature:
+		var request wire.VerificationRequest
+		var response *wire.VerificationResponse
+		if request, ok = body.(wire.VerificationRequest); ok {
+			if response, ok = result.(*wire.VerificationResponse); ok {
+				return r.verifyData(request, response)
+			}
+		}
+	case wallet.EndpointVerifyChannelStateSignature:
+		var request wire.ChannelStateVerificationRequest
+		var response *wire.VerificationResponse
+		if request, ok = body.(wire.ChannelStateVerificationRequest); ok {
+			if response, ok = result.(*wire.VerificationResponse); ok {
+				return r.verifyChannelState(request, response)
+			}
+		}
+	case wallet.EndpointVerifyChannelStateSignatures:
+		var request wire.BatchVerificationRequest
+		var response *wire.BatchVerificationResponse
+		if request, ok = body.(wire.BatchVerificationRequest); ok {
+			if response, ok = result.(*wire.BatchVerificationResponse); ok {
+				return r.verifyChannelStates(request, response)
+			}
+		}
+	case wallet.EndpointKeyAvailable:
+		var request wire.KeyAvailabilityRequest
+		var response *wire.KeyAvailabilityResponse
+		if request, ok = body.(wire.KeyAvailabilityRequest); ok {
+			if response, ok = result.(*wire.KeyAvailabilityResponse); ok {
+				return r.keyAvailable(endpoint, request, response)
+			}
+		}
+	case wallet.EndpointLockKey, wallet.EndpointUnlockKey:
+		var request wire.KeyLockRequest
+		var response *wire.KeyLockResponse
+		if request, ok = body.(wire.KeyLockRequest); ok {
+			if response, ok = result.(*wire.KeyLockResponse); ok {
+				return r.setKeyLock(endpoint, request, response, endpoint == wallet.EndpointLockKey)
+			}
+		}
+	case wallet.EndpointListAccounts:
+		var response *wire.ListAccountsResponse
+		if response, ok = result.(*wire.ListAccountsResponse); ok {
+			r.listAccounts(response)
+			return nil
+		}
+	case wallet.EndpointCreateAccount:
+		var request wire.CreateAccountRequest
+		var response *wire.CreateAccountResponse
+		if request, ok = body.(wire.CreateAccountRequest); ok {
+			if response, ok = result.(*wire.CreateAccountResponse); ok {
+				return r.createAccount(request, response)
+			}
+		}
+	case wallet.EndpointCalculateChannelID:
+		var request wire.ChannelParameters
+		var response *wire.ChannelID
+		if request, ok = body.(wire.ChannelParameters); ok {
+			if response, ok = result.(*wire.ChannelID); ok {
+				return r.calculateChannelID(request, response)
+			}
+		}
+	default:
+		return &wallet.InvalidRequestError{Endpoint: endpoint, Err: errors.New("unknown endpoint")}
+	}
+	return &wallet.InvalidRequestError{
+		Endpoint: endpoint,
+		Err:      fmt.Errorf("unexpected request %T or result %T", body, result),
+	}
+}
+
+// signingKey returns the unlocked signing key of the given public key.
+func (r *Remote) signingKey(endpoint string, pubKey wire.PubKey) (*SigningKey, error) {
+	addr, err := pubKey.Decode()
+	if err != nil {
+		return nil, &wallet.InvalidRequestError{Endpoint: endpoint, Err: err}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[addr.GetPubKey()]
+	if !ok || addr.GetSignatureScheme() != address.Ed25519 || addr.IsMultiSig() {
+		return nil, &wallet.KeyUnavailableError{Address: addr, Required: 1}
+	}
+	if r.locked[addr.GetPubKey()] {
+		return nil, &wallet.AccountLockedError{Address: addr}
+	}
+	return key, nil
+}
+
+func (r *Remote) signData(request wire.SigningRequest, response *wire.SigningResponse) error {
+	key, err := r.signingKey(wallet.EndpointSignData, request.PubKey)
+	if err != nil {
+		return err
+	}
+	msg, err := hex.DecodeString(request.Message)
+	if err != nil {
+		return &wallet.InvalidRequestError{Endpoint: wallet.EndpointSignData, Err: err}
+	}
+	*response = wire.MakeSignature(key.Sign(msg))
+	return nil
+}
+
+func (r *Remote) signChannelState(request wire.ChannelStateSigningRequest, response *wire.SigningResponse) error {
+	key, err := r.signingKey(wallet.EndpointSignChannelState, request.PubKey)
+	if err != nil {
+		return err
+	}
+	*response = wire.MakeSignature(key.Sign(wire.MakeChannelStateMessage(request.ChannelState.Decode())))
+	return nil
+}
+
+// verify returns true, iff the given signature of the given public key on the given message is valid.
+func (r *Remote) verify(endpoint string, pubKey wire.PubKey, sig wire.Signature, msg []byte) (bool, error) {
+	addr, err := pubKey.Decode()
+	if err != nil {
+		return false, &wallet.InvalidRequestError{Endpoint: endpoint, Err: err}
+	}
+	rawSig, err := hex.DecodeString(sig.Hex)
+	if err != nil {
+		return false, &wallet.InvalidRequestError{Endpoint: endpoint, Err: err}
+	}
+	if addr.IsMultiSig() {
+		return false, nil
+	}
+	r.mu.Lock()
+	schemes := r.schemes
+	r.mu.Unlock()
+	scheme, err := schemes.Get(addr.GetSignatureScheme())
+	if err != nil {
+		return false, &wallet.InvalidRequestError{Endpoint: endpoint, Err: err}
+	}
+	if len(rawSig) != scheme.SignatureLength() {
+		return false, nil
+	}
+	valid, err := scheme.Verify(addr.GetSchemePubKey(), msg, rawSig)
+	if err != nil {
+		return false, &wallet.InvalidRequestError{Endpoint: endpoint, Err: err}
+	}
+	return valid, nil
+}
+
+func (r *Remote) verifyData(request wire.VerificationRequest, response *wire.VerificationResponse) error {
+	msg, err := hex.DecodeString(request.Message)
+	if err != nil {
+		return &wallet.InvalidRequestError{Endpoint: wallet.EndpointVerifyDataSignature, Err: err}
+	}
+	*response, err = r.verify(wallet.EndpointVerifyDataSignature, request.PubKey, request.Signature, msg)
+	return err
+}
+
+func (r *Remote) verifyChannelState(request wire.ChannelStateVerificationRequest, response *wire.VerificationResponse) error {
+	var err error
+	*response, err = r.verify(
+		wallet.EndpointVerifyChannelStateSignature,
+		request.PubKey,
+		request.Signature,
+		wire.MakeChannelStateMessage(request.ChannelState.Decode()),
+	)
+	return err
+}
+
+func (r *Remote) verifyChannelStates(request wire.BatchVerificationRequest, response *wire.BatchVerificationResponse) error {
+	*response = make(wire.BatchVerificationResponse, len(request.Requests))
+	for i, req := range request.Requests {
+		if err := r.verifyChannelState(req, &(*response)[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// holds returns true, iff this Remote holds the signing key of the given address.
+func (r *Remote) holds(endpoint string, pubKey wire.PubKey) (address.Address, bool, error) {
+	addr, err := pubKey.Decode()
+	if err != nil {
+		return addr, false, &wallet.InvalidRequestError{Endpoint: endpoint, Err: err}
+	}
+	_, ok := r.keys[addr.GetPubKey()]
+	return addr, ok && addr.GetSignatureScheme() == address.Ed25519 && !addr.IsMultiSig(), nil
+}
+
+func (r *Remote) keyAvailable(endpoint string, request wire.KeyAvailabilityRequest, response *wire.KeyAvailabilityResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	_, *response, err = r.holds(endpoint, request)
+	return err
+}
+
+func (r *Remote) setKeyLock(endpoint string, request wire.KeyLockRequest, response *wire.KeyLockResponse, lock bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addr, ok, err := r.holds(endpoint, request)
+	if err != nil || !ok {
+		*response = false
+		return err
+	}
+	if lock {
+		r.locked[addr.GetPubKey()] = true
+	} else {
+		delete(r.locked, addr.GetPubKey())
+	}
+	*response = true
+	return nil
+}
+
+func (r *Remote) listAccounts(response *wire.ListAccountsResponse) {
+	addrs := r.Addresses()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*response = make(wire.ListAccountsResponse, len(addrs))
+	for i, addr := range addrs {
+		(*response)[i] = wire.MakeAccountInfo(addr, r.walletID)
+	}
+}
+
+// createAccount generates a new Ed25519 key and writes it to the key directory, if the Remote has one. The account
+// belongs to the cardano wallet of the Remote, regardless of the requested wallet id.
+func (r *Remote) createAccount(request wire.CreateAccountRequest, response *wire.CreateAccountResponse) error {
+	if request.Scheme != "" && request.Scheme != address.Ed25519.String() {
+		return &wallet.InvalidRequestError{
+			Endpoint: wallet.EndpointCreateAccount,
+			Err:      fmt.Errorf("unsupported signature scheme: %s", request.Scheme),
+		}
+	}
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return fmt.Errorf("unable to generate key: %w", err)
+	}
+	envelope := MakeTextEnvelope(PaymentSigningKeyType, "Payment Signing Key", seed)
+	key, err := ParseSigningKey(envelope)
+	if err != nil {
+		return err
+	}
+	addr, err := key.Address()
+	if err != nil {
+		return err
+	}
+	if r.dir != "" {
+		pubKey := key.GetPubKey()
+		file := filepath.Join(r.dir, hex.EncodeToString(pubKey[:])+SigningKeyExtension)
+		if err = envelope.WriteFile(file); err != nil {
+			return fmt.Errorf("unable to write key file: %w", err)
+		}
+	}
+	r.AddKey(key)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*response = wire.MakeAccountInfo(addr, r.walletID)
+	return nil
+}
+
+func (r *Remote) calculateChannelID(request wire.ChannelParameters, response *wire.ChannelID) error {
+	params, err := request.Decode()
+	if err != nil {
+		return &wallet.InvalidRequestError{Endpoint: wallet.EndpointCalculateChannelID, Err: err}
+	}
+	*response = wire.ChannelID(wire.CalculateChannelID(params))
+	return nil
+}
+
+var _ wallet.Remote = &Remote{}
@@ -0,0 +1,155 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyfile_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"math/rand"
+	"net/http/httptest"
+	"path/filepath"
+	gptest "perun.network/go-perun/wallet/test"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/keyfile"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+	"time"
+)
+
+// makeSigningKey returns a random plain Ed25519 signing key and its TextEnvelope.
+func makeSigningKey(t *testing.T, rng *rand.Rand) (*keyfile.SigningKey, keyfile.TextEnvelope) {
+	envelope := keyfile.MakeTextEnvelope(keyfile.PaymentSigningKeyType, "", test.GetRandomByteSlice(32, 32, rng))
+	key, err := keyfile.ParseSigningKey(envelope)
+	require.NoError(t, err)
+	return key, envelope
+}
+
+// serveRemote serves a Remote holding a random signing key and returns a PerunCardanoWallet connected to it.
+func serveRemote(t *testing.T, rng *rand.Rand) (*keyfile.Remote, *wallet.PerunCardanoWallet, *keyfile.SigningKey) {
+	key, _ := makeSigningKey(t, rng)
+	remote := keyfile.NewRemote(key)
+	server := httptest.NewServer(wallet.NewServer(remote))
+	t.Cleanup(server.Close)
+	return remote, wallet.NewPerunCardanoWallet(server.URL), key
+}
+
+func TestRemote_AccountWithWalletAndBackend(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	remote, client, _ := serveRemote(t, rng)
+	gptest.TestAccountWithWalletAndBackend(t, test.NewSetup(rng, client, remote.Addresses()[0]))
+}
+
+func TestRemote_ChannelState(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	remote, client, key := serveRemote(t, rng)
+	addr := remote.Addresses()[0]
+	backend := wallet.MakeRemoteBackend(client)
+	acc := wallet.MakeRemoteAccount(addr, client, "")
+	state := ctest.MakeRandomChannelState(rng)
+
+	sig, err := acc.SignChannelState(state)
+	require.NoError(t, err)
+	valid, err := backend.VerifyChannelStateSignature(state, sig, &addr)
+	require.NoError(t, err)
+	require.True(t, valid, "channel state signature of the server is invalid")
+
+	local, err := keyfile.NewAccount(key)
+	require.NoError(t, err)
+	localSig, err := local.SignChannelState(state)
+	require.NoError(t, err)
+	require.Equal(t, localSig, sig, "server signs channel states differently than a local account")
+
+	other := state
+	other.Version++
+	valid, err = backend.VerifyChannelStateSignature(other, sig, &addr)
+	require.NoError(t, err)
+	require.False(t, valid, "signature on a different channel state is valid")
+
+	params := types.ChannelParameters{
+		Parties: []address.Address{addr, test.MakeRandomAddress(rng)},
+		Nonce:   big.NewInt(rng.Int63()),
+		Timeout: time.Duration(rng.Intn(1000)+1) * time.Second,
+	}
+	id, err := backend.CalculateChannelID(params)
+	require.NoError(t, err)
+	require.Equal(t, wire.CalculateChannelID(params), id)
+}
+
+func TestRemote_Keys(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	remote, client, _ := serveRemote(t, rng)
+	addr := remote.Addresses()[0]
+	var available bool
+	require.NoError(t, client.CallEndpoint(wallet.EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(addr), &available))
+	require.True(t, available)
+
+	unknown := test.MakeRandomAddress(rng)
+	require.NoError(t, client.CallEndpoint(wallet.EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(unknown), &available))
+	require.False(t, available)
+	var sig wire.SigningResponse
+	err := remote.CallEndpoint(wallet.EndpointSignData, wire.MakeSigningRequest(unknown, []byte{1}), &sig)
+	require.Equal(t, errclass.KeyUnavailable, errclass.Of(err), "signing with an unknown key has the wrong error class")
+	err = client.CallEndpoint(wallet.EndpointSignData, wire.MakeSigningRequest(unknown, []byte{1}), &sig)
+	require.Equal(t, errclass.Client, errclass.Of(err), "server responded to an unknown key with the wrong status")
+
+	var locked bool
+	require.NoError(t, client.CallEndpoint(wallet.EndpointLockKey, wire.MakeKeyLockRequest(addr), &locked))
+	require.True(t, locked)
+	require.Error(t, client.CallEndpoint(wallet.EndpointSignData, wire.MakeSigningRequest(addr, []byte{1}), &sig),
+		"locked key was used to sign")
+	require.NoError(t, client.CallEndpoint(wallet.EndpointUnlockKey, wire.MakeKeyLockRequest(addr), &locked))
+	require.NoError(t, client.CallEndpoint(wallet.EndpointSignData, wire.MakeSigningRequest(addr, []byte{1}), &sig))
+
+	err = client.CallEndpoint(wallet.EndpointSignData, wire.SigningRequest{PubKey: wire.MakePubKey(addr), Message: "xyz"}, &sig)
+	require.Equal(t, errclass.Client, errclass.Of(err), "malformed request was not rejected")
+	require.True(t, wallet.IsUnsupportedEndpoint(client.CallEndpoint("/unknown", nil, &sig)))
+}
+
+func TestLoadRemote(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	dir := t.TempDir()
+	var addrs []address.Address
+	for i := 0; i < 2; i++ {
+		key, envelope := makeSigningKey(t, rng)
+		require.NoError(t, envelope.WriteFile(filepath.Join(dir, string(rune('a'+i))+keyfile.SigningKeyExtension)))
+		addr, err := key.Address()
+		require.NoError(t, err)
+		addrs = append(addrs, addr)
+	}
+	remote, err := keyfile.LoadRemote(dir)
+	require.NoError(t, err)
+	require.Equal(t, addrs, remote.Addresses())
+
+	var created wire.CreateAccountResponse
+	require.NoError(t, remote.CallEndpoint(wallet.EndpointCreateAccount, wire.CreateAccountRequest{}, &created))
+	createdKey, err := created.PubKey.Decode()
+	require.NoError(t, err)
+	createdAddr, err := address.MakeAddressFromSinglePubKey(createdKey.GetPubKeySlice())
+	require.NoError(t, err)
+	reloaded, err := keyfile.LoadRemote(dir)
+	require.NoError(t, err)
+	require.Len(t, reloaded.Addresses(), 3, "created key was not written to the key directory")
+	require.Contains(t, reloaded.Addresses(), createdAddr)
+
+	var accounts wire.ListAccountsResponse
+	require.NoError(t, reloaded.CallEndpoint(wallet.EndpointListAccounts, wire.ListAccountsRequest{}, &accounts))
+	require.Len(t, accounts, 3)
+}
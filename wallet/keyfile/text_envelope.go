@@ -0,0 +1,108 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyfile
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TextEnvelope types of the key files written by cardano-cli.
+const (
+	PaymentVerificationKeyType         = "PaymentVerificationKeyShelley_ed25519"
+	PaymentSigningKeyType              = "PaymentSigningKeyShelley_ed25519"
+	PaymentExtendedVerificationKeyType = "PaymentExtendedVerificationKeyShelley_ed25519_bip32"
+	PaymentExtendedSigningKeyType      = "PaymentExtendedSigningKeyShelley_ed25519_bip32"
+	StakeVerificationKeyType           = "StakeVerificationKeyShelley_ed25519"
+	StakeSigningKeyType                = "StakeSigningKeyShelley_ed25519"
+	StakeExtendedVerificationKeyType   = "StakeExtendedVerificationKeyShelley_ed25519_bip32"
+	StakeExtendedSigningKeyType        = "StakeExtendedSigningKeyShelley_ed25519_bip32"
+)
+
+// TextEnvelope is the json file format cardano-cli uses for keys (e.g. `payment.vkey` and `payment.skey`). CborHex is
+// the hex encoded CBOR byte string holding the raw key.
+type TextEnvelope struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	CborHex     string `json:"cborHex"`
+}
+
+// ReadTextEnvelope reads the TextEnvelope file at the given path.
+func ReadTextEnvelope(path string) (TextEnvelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TextEnvelope{}, fmt.Errorf("unable to read key file: %w", err)
+	}
+	return ParseTextEnvelope(data)
+}
+
+// ParseTextEnvelope parses the given json encoded TextEnvelope.
+func ParseTextEnvelope(data []byte) (TextEnvelope, error) {
+	var envelope TextEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return TextEnvelope{}, fmt.Errorf("unable to parse TextEnvelope: %w", err)
+	}
+	if envelope.Type == "" {
+		return TextEnvelope{}, errors.New("TextEnvelope has no type")
+	}
+	return envelope, nil
+}
+
+// MakeTextEnvelope returns a TextEnvelope of the given type holding the given raw key.
+func MakeTextEnvelope(envelopeType string, description string, rawKey []byte) TextEnvelope {
+	// Keys are between 32 and 128 bytes long, so the CBOR head is the byte string major type with a one byte length.
+	head := []byte{0x58, byte(len(rawKey))}
+	return TextEnvelope{
+		Type:        envelopeType,
+		Description: description,
+		CborHex:     hex.EncodeToString(append(head, rawKey...)),
+	}
+}
+
+// RawKey returns the raw key held by this TextEnvelope and checks that it is of the given length.
+func (e TextEnvelope) RawKey(length int) ([]byte, error) {
+	data, err := hex.DecodeString(e.CborHex)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode cborHex of TextEnvelope: %w", err)
+	}
+	// The key is a CBOR byte string (major type 2) with a one byte length argument for all key lengths of cardano-cli.
+	if len(data) < 2 || data[0] != 0x58 || int(data[1]) != len(data)-2 {
+		return nil, errors.New("cborHex of TextEnvelope is no CBOR byte string")
+	}
+	if len(data)-2 != length {
+		return nil, fmt.Errorf(
+			"key of TextEnvelope of type %s has incorrect length. expected: %d bytes actual: %d bytes",
+			e.Type,
+			length,
+			len(data)-2,
+		)
+	}
+	return data[2:], nil
+}
+
+// WriteFile writes this TextEnvelope to the given path in the format of cardano-cli.
+func (e TextEnvelope) WriteFile(path string) error {
+	data, err := json.MarshalIndent(e, "", "    ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal TextEnvelope: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("unable to write key file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+const envelopeTypeMultiSig byte = 0x01
+
+// multiSigComponentLength is the length of one component of a multi-signature: the index of the signing key followed
+// by the signature.
+const multiSigComponentLength = 1 + wire.SignatureLength
+
+// MultiSigComponent is one signature of a multi-signature together with the index of the key that created it in the
+// signing keys of the multi-signature address (see address.Address.GetSigningPubKeys).
+type MultiSigComponent struct {
+	KeyIndex  uint8
+	Signature wallet.Sig
+}
+
+// MakeMultiSig encodes the given components into a single multi-signature.
+func MakeMultiSig(components []MultiSigComponent) (wallet.Sig, error) {
+	sig, err := makeEnvelope(envelopeTypeMultiSig, len(components)*multiSigComponentLength)
+	if err != nil {
+		return nil, fmt.Errorf("too many multi-signature components: %d", len(components))
+	}
+	for _, c := range components {
+		if len(c.Signature) != wire.SignatureLength {
+			return nil, fmt.Errorf(
+				"signature of key %d has incorrect length. expected: %d bytes actual: %d bytes",
+				c.KeyIndex,
+				wire.SignatureLength,
+				len(c.Signature),
+			)
+		}
+		sig = append(sig, c.KeyIndex)
+		sig = append(sig, c.Signature...)
+	}
+	return sig, nil
+}
+
+// IsMultiSig returns true, iff the given signature is encoded as a multi-signature.
+func IsMultiSig(sig wallet.Sig) bool {
+	return isEnvelope(sig, envelopeTypeMultiSig)
+}
+
+// DecodeMultiSig decodes the components of the given multi-signature.
+func DecodeMultiSig(sig wallet.Sig) ([]MultiSigComponent, error) {
+	if !IsMultiSig(sig) {
+		return nil, errors.New("signature is not a multi-signature")
+	}
+	payload, err := envelopePayload(sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload)%multiSigComponentLength != 0 {
+		return nil, fmt.Errorf("multi-signature has invalid payload length: %d", len(payload))
+	}
+	components := make([]MultiSigComponent, len(payload)/multiSigComponentLength)
+	for i := range components {
+		c := payload[i*multiSigComponentLength : (i+1)*multiSigComponentLength]
+		components[i] = MultiSigComponent{
+			KeyIndex:  c[0],
+			Signature: append(wallet.Sig{}, c[1:]...),
+		}
+	}
+	return components, nil
+}
+
+// verifyMultiSig verifies the given multi-signature for the given multi-signature address. verify is called for every
+// component with an address holding the single signing key of that component. The multi-signature is valid, iff it
+// consists of at least the threshold of valid signatures of distinct signing keys.
+func verifyMultiSig(
+	sig wallet.Sig,
+	addr address.Address,
+	verify func(sig wallet.Sig, signer address.Address) (bool, error),
+) (bool, error) {
+	components, err := DecodeMultiSig(sig)
+	if err != nil {
+		return false, err
+	}
+	keys := addr.GetSigningPubKeys()
+	if len(components) < addr.GetSignatureThreshold() {
+		return false, nil
+	}
+	seen := make(map[uint8]bool, len(components))
+	for _, c := range components {
+		if int(c.KeyIndex) >= len(keys) || seen[c.KeyIndex] {
+			return false, nil
+		}
+		seen[c.KeyIndex] = true
+		valid, err := verify(c.Signature, address.MakeAddressFromPubKeyByteArray(keys[c.KeyIndex]))
+		if err != nil || !valid {
+			return false, err
+		}
+	}
+	return true, nil
+}
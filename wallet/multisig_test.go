@@ -0,0 +1,133 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math/rand"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+// setupMultiSig returns a 2-of-3 multi-signature address and a GenericRemote holding the private keys of the first
+// `available` signing keys.
+func setupMultiSig(rng *rand.Rand, available int) (address.Address, *test.GenericRemote) {
+	keys := make([][address.PubKeyLength]byte, 3)
+	availableAddresses := make([]address.Address, available)
+	for i := range keys {
+		keys[i] = test.MakeRandomAddress(rng).GetPubKey()
+		if i < available {
+			availableAddresses[i] = address.MakeAddressFromPubKeyByteArray(keys[i])
+		}
+	}
+	addr, err := address.MakeMultiSigAddress(2, keys)
+	if err != nil {
+		panic(err)
+	}
+	return addr, test.NewGenericRemote(availableAddresses, rng)
+}
+
+func TestMultiSig_Encoding(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	expected := []wallet.MultiSigComponent{
+		{KeyIndex: 0, Signature: test.MakeRandomSignature(rng)},
+		{KeyIndex: 2, Signature: test.MakeRandomSignature(rng)},
+	}
+	sig, err := wallet.MakeMultiSig(expected)
+	require.NoError(t, err, "unable to encode multi-signature")
+	require.True(t, wallet.IsMultiSig(sig), "encoded multi-signature is not recognized")
+	require.False(t, wallet.IsMultiSig(expected[0].Signature), "plain signature is recognized as multi-signature")
+	actual, err := wallet.DecodeMultiSig(sig)
+	require.NoError(t, err, "unable to decode multi-signature")
+	require.Equal(t, expected, actual, "decoded multi-signature is not as expected")
+
+	_, err = wallet.MakeMultiSig([]wallet.MultiSigComponent{{Signature: test.MakeTooShortSignature(rng)}})
+	require.Error(t, err, "failed to error on component signature of invalid length")
+	_, err = wallet.DecodeMultiSig(sig[:len(sig)-1])
+	require.Error(t, err, "failed to error on truncated multi-signature")
+
+	// DecodeSig must read exactly one multi-signature from the stream.
+	backend := wallet.MakeRemoteBackend(test.NewMockRemote(rng))
+	rest := test.MakeRandomSignature(rng)
+	reader := bytes.NewReader(append(append([]byte{}, sig...), rest...))
+	decoded, err := backend.DecodeSig(reader)
+	require.NoError(t, err, "unable to decode multi-signature from reader")
+	require.Equal(t, sig, decoded, "decoded multi-signature is not as expected")
+	remaining, err := io.ReadAll(reader)
+	require.NoError(t, err, "this should not fail!")
+	require.Equal(t, []byte(rest), remaining, "DecodeSig read more than one multi-signature")
+
+	_, err = backend.DecodeSig(bytes.NewReader(sig[:len(sig)-1]))
+	require.Error(t, err, "failed to error on truncated multi-signature stream")
+}
+
+func TestMultiSig_SignAndVerify(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr, remote := setupMultiSig(rng, 2)
+	backend := wallet.MakeRemoteBackend(remote)
+	account, err := test.NewRemoteWallet(remote).Unlock(&addr)
+	require.NoError(t, err, "unable to unlock multi-signature account with enough available keys")
+	state := ctest.MakeRandomChannelState(rng)
+
+	sig, err := account.(wallet.RemoteAccount).SignChannelState(state)
+	require.NoError(t, err, "unable to sign channel state with multi-signature account")
+	components, err := wallet.DecodeMultiSig(sig)
+	require.NoError(t, err, "signature of multi-signature account is no multi-signature")
+	require.Len(t, components, 2, "multi-signature should consist of exactly the threshold of signatures")
+	valid, err := backend.VerifyChannelStateSignature(state, sig, &addr)
+	require.NoError(t, err, "unable to verify valid multi-signature")
+	require.True(t, valid, "valid multi-signature was not verified as valid")
+
+	tooFew, err := wallet.MakeMultiSig(components[:1])
+	require.NoError(t, err, "this should not fail!")
+	valid, err = backend.VerifyChannelStateSignature(state, tooFew, &addr)
+	require.NoError(t, err, "unable to verify multi-signature")
+	require.False(t, valid, "multi-signature with too few signatures was verified as valid")
+
+	duplicate, err := wallet.MakeMultiSig([]wallet.MultiSigComponent{components[0], components[0]})
+	require.NoError(t, err, "this should not fail!")
+	valid, err = backend.VerifyChannelStateSignature(state, duplicate, &addr)
+	require.NoError(t, err, "unable to verify multi-signature")
+	require.False(t, valid, "multi-signature with duplicate signatures was verified as valid")
+
+	wrongKey, err := wallet.MakeMultiSig([]wallet.MultiSigComponent{
+		components[0],
+		{KeyIndex: 2, Signature: components[1].Signature},
+	})
+	require.NoError(t, err, "this should not fail!")
+	valid, err = backend.VerifyChannelStateSignature(state, wrongKey, &addr)
+	require.NoError(t, err, "unable to verify multi-signature")
+	require.False(t, valid, "multi-signature with signature attributed to wrong key was verified as valid")
+
+	msg := test.GetRandomByteSlice(0, 0x100, rng)
+	sig, err = account.SignData(msg)
+	require.NoError(t, err, "unable to sign data with multi-signature account")
+	valid, err = backend.VerifySignature(msg, sig, &addr)
+	require.NoError(t, err, "unable to verify valid multi-signature")
+	require.True(t, valid, "valid multi-signature was not verified as valid")
+}
+
+func TestMultiSig_Unlock(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr, remote := setupMultiSig(rng, 1)
+	_, err := test.NewRemoteWallet(remote).Unlock(&addr)
+	require.Error(t, err, "unlocked multi-signature account with less than threshold available keys")
+}
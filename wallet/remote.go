@@ -15,20 +15,23 @@
 package wallet
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"perun.network/perun-cardano-backend/transport"
 )
 
 const (
-	EndpointSignData                    = "/sign"
-	EndpointSignChannelState            = "/signChannelState"
-	EndpointVerifyDataSignature         = "/verify"
-	EndpointVerifyChannelStateSignature = "/verifyChannelState"
-	EndpointKeyAvailable                = "/keyAvailable"
-	EndpointCalculateChannelID          = "/calculateChannelID"
+	EndpointSignData                     = "/sign"
+	EndpointSignChannelState             = "/signChannelState"
+	EndpointVerifyDataSignature          = "/verify"
+	EndpointVerifyChannelStateSignature  = "/verifyChannelState"
+	EndpointVerifyChannelStateSignatures = "/verifyChannelStates"
+	EndpointKeyAvailable                 = "/keyAvailable"
+	EndpointLockKey                      = "/lockKey"
+	EndpointUnlockKey                    = "/unlockKey"
+	EndpointListAccounts                 = "/listAccounts"
+	EndpointCreateAccount                = "/createAccount"
+	EndpointCalculateChannelID           = "/calculateChannelID"
 )
 
 // Remote is an interface, which instances are used to communicate with the perun-cardano-wallet server.
@@ -39,12 +42,34 @@ type Remote interface {
 
 // PerunCardanoWallet is a basic implementation Remote implementation that calls perun-cardano-wallet via http.
 type PerunCardanoWallet struct {
-	serverAddress string
+	// serverAddresses holds the address of the wallet server followed by the addresses of its failover hosts.
+	serverAddresses []string
+	transport       *transport.Config
 }
 
-// NewPerunCardanoWallet returns a new PerunCardanoWallet with the given server address.
-func NewPerunCardanoWallet(addr string) *PerunCardanoWallet {
-	return &PerunCardanoWallet{serverAddress: addr}
+// NewPerunCardanoWallet returns a new PerunCardanoWallet with the given server address (e.g.
+// "https://wallet.example.com"). The options configure TLS, authentication and timeouts of all requests to the wallet
+// server, as well as retries and failover (see transport.WithRetryPolicy and transport.WithFailoverHosts). Requests of
+// one PerunCardanoWallet reuse the connections of a single connection pool. The session with the wallet server can be
+// recorded and replayed (see transport.WithRecorder and transport.WithReplayer).
+func NewPerunCardanoWallet(addr string, opts ...transport.Option) *PerunCardanoWallet {
+	t := transport.NewConfig(opts...)
+	return &PerunCardanoWallet{
+		serverAddresses: append([]string{addr}, t.FailoverHosts()...),
+		transport:       t,
+	}
+}
+
+// IsIdempotentEndpoint returns true, iff calling the given wallet server endpoint repeatedly has the same effect as
+// calling it once. Only requests to idempotent endpoints are repeated after they may have reached the wallet server.
+func IsIdempotentEndpoint(endpoint string) bool {
+	switch endpoint {
+	case EndpointVerifyDataSignature, EndpointVerifyChannelStateSignature, EndpointVerifyChannelStateSignatures,
+		EndpointKeyAvailable, EndpointLockKey, EndpointUnlockKey, EndpointListAccounts, EndpointCalculateChannelID:
+		return true
+	default:
+		return false
+	}
 }
 
 // CallEndpoint calls the given endpoint on the remote wallet and decodes the json response into the given result.
@@ -66,34 +91,7 @@ func (r *PerunCardanoWallet) CallEndpoint(endpoint string, body interface{}, res
 
 // callEndpoint issues a request to the given endpoint with the given body.
 func (r *PerunCardanoWallet) callEndpoint(jsonBody []byte, endpoint string) ([]byte, error) {
-	request, err := http.NewRequest("POST", r.serverAddress+endpoint, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("unable to prepare http request: %w", err)
-	}
-	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	response, err := client.Do(request)
-	if err != nil {
-		return nil, fmt.Errorf("unable to send http request: %w", err)
-	}
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		errorBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to interact with wallet server: %s", response.Status)
-		}
-		return nil, fmt.Errorf(
-			"failed to interact with wallet server: %s with error: %s",
-			response.Status,
-			string(errorBody),
-		)
-	}
-	jsonResponse, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("unable to read server response: %w", err)
-	}
-	return jsonResponse, nil
+	return r.transport.Call("wallet server", r.serverAddresses, endpoint, IsIdempotentEndpoint(endpoint), jsonBody)
 }
 
 var _ Remote = &PerunCardanoWallet{}
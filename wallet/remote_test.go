@@ -0,0 +1,95 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPerunCardanoWallet_Retry(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1)%2 == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("true"))
+	}))
+	defer server.Close()
+	remote := wallet.NewPerunCardanoWallet(server.URL, transport.WithRetryPolicy(transport.RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	}))
+
+	var valid bool
+	require.NoError(t, remote.CallEndpoint(wallet.EndpointVerifyDataSignature, nil, &valid), "verification was not retried")
+	require.True(t, valid)
+	require.EqualValues(t, 2, atomic.LoadInt32(&count))
+
+	err := remote.CallEndpoint(wallet.EndpointSignData, nil, &valid)
+	var ambiguous *transport.AmbiguousError
+	require.True(t, errors.As(err, &ambiguous), "failed signing request should be ambiguous")
+	require.EqualValues(t, 3, atomic.LoadInt32(&count), "signing request was repeated")
+}
+
+func TestFaultyRemote(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		_, _ = w.Write([]byte("true"))
+	}))
+	defer server.Close()
+	remote := test.NewFaultyRemote(wallet.NewPerunCardanoWallet(server.URL), pkgtest.Prng(t), test.RemoteFaults{})
+
+	var valid bool
+	require.NoError(t, remote.CallEndpoint(wallet.EndpointVerifyDataSignature, nil, &valid), "remote without faults failed")
+	require.True(t, valid)
+	require.EqualValues(t, 1, atomic.LoadInt32(&count))
+
+	for _, tc := range []struct {
+		name     string
+		faults   test.RemoteFaults
+		endpoint string
+		class    errclass.Class
+		reached  bool
+	}{
+		{"drop", test.RemoteFaults{DropRate: 1}, wallet.EndpointSignData, errclass.Network, false},
+		{"server error", test.RemoteFaults{ServerErrorRate: 1}, wallet.EndpointSignData, errclass.Server, false},
+		{"lost idempotent", test.RemoteFaults{LostResponseRate: 1}, wallet.EndpointVerifyDataSignature, errclass.Network, true},
+		{"lost non-idempotent", test.RemoteFaults{LostResponseRate: 1}, wallet.EndpointSignData, errclass.Ambiguous, true},
+		{"truncate", test.RemoteFaults{TruncateRate: 1}, wallet.EndpointVerifyDataSignature, errclass.Unknown, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := atomic.LoadInt32(&count)
+			remote.SetFaults(tc.faults)
+			err := remote.CallEndpoint(tc.endpoint, nil, &valid)
+			require.Error(t, err)
+			require.Equal(t, tc.class, errclass.Of(err), "fault has the wrong error class")
+			reached := atomic.LoadInt32(&count) > before
+			require.Equal(t, tc.reached, reached, "fault was injected at the wrong point")
+		})
+	}
+}
@@ -16,52 +16,356 @@ package wallet
 
 import (
 	"fmt"
+	"perun.network/go-perun/log"
 	"perun.network/go-perun/wallet"
 	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wire"
+	"sync"
+	"sync/atomic"
 )
 
-// RemoteWallet is a cardano signing wallet using a remote wallet server.
+// RemoteWallet is a cardano signing wallet using a remote wallet server. It tracks the usage of the accounts it
+// unlocked. Accounts are locked, once their usage drops to zero (see DecrementUsage) or LockAll is called. Locked
+// accounts refuse signing and the signing keys no unlocked account needs anymore are locked in the wallet server.
+// Every account belongs to a cardano wallet. The cardano wallet ids of the accounts are learned from the wallet server
+// (see Accounts and CreateAccount) or set explicitly (see SetWalletID). Accounts of unknown cardano wallet belong to
+// the default cardano wallet of the RemoteWallet.
 // Note: If we decide to stick with a remote signing wallet, we will have to harden the wallet server!
 type RemoteWallet struct {
 	walletServer    Remote
 	cardanoWalletID string
+
+	// keyMu orders the changes of the lock state of signing keys in the wallet server. It is acquired before mu and
+	// held during the calls to the wallet server, so that mu is never held during a network call.
+	keyMu    sync.Mutex
+	mu       sync.Mutex
+	accounts map[wallet.AddrKey]*unlockedAccount
+	// keyUsers holds the number of unlocked accounts using every signing key.
+	keyUsers map[string]int
+	// walletIDs holds the cardano wallet id of every signing key.
+	walletIDs map[string]string
+	// schemes decodes the signatures of the unlocked accounts.
+	schemes SignatureSchemes
+}
+
+// unlockedAccount is an account unlocked by a RemoteWallet.
+type unlockedAccount struct {
+	account RemoteAccount
+	usage   int
+	// signers are the signing keys the wallet server holds for the account.
+	signers []address.Address
+}
+
+// accountLock is shared by all copies of a RemoteAccount unlocked by a RemoteWallet.
+type accountLock struct {
+	locked int32
 }
 
-// NewRemoteWallet returns a pointer to a new RemoteWallet struct associated with the given Remote wallet server.
+func (l *accountLock) lock() {
+	atomic.StoreInt32(&l.locked, 1)
+}
+
+// isLocked returns true, iff the account was locked. Accounts without accountLock are never locked.
+func (l *accountLock) isLocked() bool {
+	return l != nil && atomic.LoadInt32(&l.locked) == 1
+}
+
+// NewRemoteWallet returns a pointer to a new RemoteWallet struct associated with the given Remote wallet server. The
+// given id is the default cardano wallet id of the accounts.
 func NewRemoteWallet(remote Remote, id string) *RemoteWallet {
 	return &RemoteWallet{
 		walletServer:    remote,
 		cardanoWalletID: id,
+		accounts:        make(map[wallet.AddrKey]*unlockedAccount),
+		keyUsers:        make(map[string]int),
+		walletIDs:       make(map[string]string),
+	}
+}
+
+// SetSignatureSchemes sets the signature schemes with which the accounts unlocked afterwards decode signatures. By
+// default, only the built-in schemes are supported (see SignatureSchemes).
+func (w *RemoteWallet) SetSignatureSchemes(schemes SignatureSchemes) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.schemes = schemes
+}
+
+// Accounts returns the addresses of all single key accounts the wallet server holds the key of and records their
+// cardano wallet ids.
+func (w *RemoteWallet) Accounts() ([]address.Address, error) {
+	var response wire.ListAccountsResponse
+	err := w.walletServer.CallEndpoint(EndpointListAccounts, wire.ListAccountsRequest{}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("wallet server could not list accounts: %w", err)
+	}
+	addresses := make([]address.Address, len(response))
+	for i, info := range response {
+		if addresses[i], err = w.addAccountInfo(info); err != nil {
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+// CreateAccount generates a new key pair of the given signature scheme in the wallet server and returns the address of
+// the new account. The account belongs to the cardano wallet of the given id or the default cardano wallet of the
+// wallet server, if the id is empty.
+func (w *RemoteWallet) CreateAccount(scheme address.SignatureScheme, walletID string) (address.Address, error) {
+	var response wire.CreateAccountResponse
+	err := w.walletServer.CallEndpoint(EndpointCreateAccount, wire.MakeCreateAccountRequest(scheme, walletID), &response)
+	if err != nil {
+		return address.Address{}, fmt.Errorf("wallet server could not create account: %w", err)
 	}
+	return w.addAccountInfo(response)
 }
 
-// LockAll is unimplemented due to the remote nature of this wallet.
+// addAccountInfo decodes the address of the given AccountInfo and records its cardano wallet id.
+func (w *RemoteWallet) addAccountInfo(info wire.AccountInfo) (address.Address, error) {
+	addr, err := info.PubKey.Decode()
+	if err != nil {
+		return address.Address{}, fmt.Errorf("unable to decode account of wallet server: %w", err)
+	}
+	if info.WalletID != "" {
+		w.SetWalletID(addr, info.WalletID)
+	}
+	return addr, nil
+}
+
+// SetWalletID sets the cardano wallet id of the signing keys of the given address. Accounts that are already unlocked
+// keep their cardano wallet id until they are locked.
+func (w *RemoteWallet) SetWalletID(addr address.Address, walletID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, signer := range signersOf(addr) {
+		w.walletIDs[signerKey(signer)] = walletID
+	}
+}
+
+// WalletID returns the cardano wallet id of the account of the given address. For multi-signature addresses, this is
+// the cardano wallet id of the first signing key with known cardano wallet id.
+func (w *RemoteWallet) WalletID(addr address.Address) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.walletID(signersOf(addr))
+}
+
+// walletID returns the cardano wallet id of the first of the given signing keys with known cardano wallet id or the
+// default cardano wallet id. The caller must hold w.mu.
+func (w *RemoteWallet) walletID(signers []address.Address) string {
+	for _, signer := range signers {
+		if id, ok := w.walletIDs[signerKey(signer)]; ok {
+			return id
+		}
+	}
+	return w.cardanoWalletID
+}
+
+// LockAll locks all accounts unlocked by this wallet and their signing keys in the wallet server.
 func (w *RemoteWallet) LockAll() {
+	w.keyMu.Lock()
+	defer w.keyMu.Unlock()
+	w.mu.Lock()
+	var keys []address.Address
+	for key, acc := range w.accounts {
+		keys = append(keys, w.release(acc)...)
+		delete(w.accounts, key)
+	}
+	w.mu.Unlock()
+	w.lockKeys(keys)
 }
 
-// IncrementUsage is unimplemented due to the remote nature of this wallet.
-func (w *RemoteWallet) IncrementUsage(address wallet.Address) {
+// IncrementUsage increments the usage of the unlocked account of the given address. It panics, if the account is not
+// unlocked.
+func (w *RemoteWallet) IncrementUsage(addr wallet.Address) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	acc, ok := w.accounts[wallet.Key(addr)]
+	if !ok {
+		panic(fmt.Sprintf("IncrementUsage: account %s is not unlocked", addr))
+	}
+	acc.usage++
+}
+
+// DecrementUsage decrements the usage of the unlocked account of the given address. If the usage drops to zero, the
+// account and its signing keys in the wallet server are locked. It panics, if the account is not unlocked or the call
+// has no matching IncrementUsage call.
+func (w *RemoteWallet) DecrementUsage(addr wallet.Address) {
+	w.keyMu.Lock()
+	defer w.keyMu.Unlock()
+	w.mu.Lock()
+	key := wallet.Key(addr)
+	acc, ok := w.accounts[key]
+	if !ok {
+		w.mu.Unlock()
+		panic(fmt.Sprintf("DecrementUsage: account %s is not unlocked", addr))
+	}
+	if acc.usage == 0 {
+		w.mu.Unlock()
+		panic(fmt.Sprintf("DecrementUsage: unmatched call for account %s", addr))
+	}
+	acc.usage--
+	var keys []address.Address
+	if acc.usage == 0 {
+		keys = w.release(acc)
+		delete(w.accounts, key)
+	}
+	w.mu.Unlock()
+	w.lockKeys(keys)
 }
 
-// DecrementUsage is unimplemented due to the remote nature of this wallet.
-func (w *RemoteWallet) DecrementUsage(address wallet.Address) {
+// UsageCount returns the usage of the unlocked account of the given address and false, if the account is not
+// unlocked.
+func (w *RemoteWallet) UsageCount(addr wallet.Address) (int, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	acc, ok := w.accounts[wallet.Key(addr)]
+	if !ok {
+		return 0, false
+	}
+	return acc.usage, true
 }
 
 // Unlock returns the account of the given address, iff the wallet server associated with this RemoteWallet
-// has that account.
+// has that account. For multi-signature addresses, the wallet server needs to hold at least the threshold of the
+// signing keys. The signing keys of the account are unlocked in the wallet server. Unlocking an account repeatedly
+// returns the same account until it is locked.
 func (w *RemoteWallet) Unlock(addr wallet.Address) (wallet.Account, error) {
 	rwAddress, ok := addr.(*address.Address)
 	if !ok {
 		return nil, fmt.Errorf("invalid address for signature verification (expected type Address)")
 	}
-	var response wire.KeyAvailabilityResponse
-	err := w.walletServer.CallEndpoint(EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(*rwAddress), &response)
+	if acc, ok := w.unlockedAccount(addr); ok {
+		return acc, nil
+	}
+	var signers []address.Address
+	for _, signer := range signersOf(*rwAddress) {
+		ok, err := keyAvailable(w.walletServer, signer)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) < rwAddress.GetSignatureThreshold() {
+		return nil, &KeyUnavailableError{
+			Address:   *rwAddress,
+			Available: len(signers),
+			Required:  rwAddress.GetSignatureThreshold(),
+		}
+	}
+
+	w.keyMu.Lock()
+	defer w.keyMu.Unlock()
+	if acc, ok := w.unlockedAccount(addr); ok {
+		return acc, nil
+	}
+	w.mu.Lock()
+	var locked []address.Address
+	for _, signer := range signers {
+		if w.keyUsers[signerKey(signer)] == 0 {
+			locked = append(locked, signer)
+		}
+	}
+	w.mu.Unlock()
+	for i, signer := range locked {
+		if err := setKeyLock(w.walletServer, EndpointUnlockKey, signer); err != nil {
+			w.lockKeys(locked[:i])
+			return nil, err
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, signer := range signers {
+		w.keyUsers[signerKey(signer)]++
+	}
+	acc := MakeRemoteAccount(*rwAddress, w.walletServer, w.walletID(signers)).WithSignatureSchemes(w.schemes)
+	acc.lock = &accountLock{}
+	w.accounts[wallet.Key(addr)] = &unlockedAccount{account: acc, signers: signers}
+	return acc, nil
+}
+
+// unlockedAccount returns the unlocked account of the given address and true, iff it is unlocked.
+func (w *RemoteWallet) unlockedAccount(addr wallet.Address) (wallet.Account, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	acc, ok := w.accounts[wallet.Key(addr)]
+	if !ok {
+		return nil, false
+	}
+	return acc.account, true
+}
+
+// release locks the given account and returns the signing keys no other unlocked account uses. These need to be locked
+// in the wallet server (see lockKeys). The caller must hold w.keyMu and w.mu.
+func (w *RemoteWallet) release(acc *unlockedAccount) []address.Address {
+	acc.account.lock.lock()
+	var unused []address.Address
+	for _, signer := range acc.signers {
+		key := signerKey(signer)
+		w.keyUsers[key]--
+		if w.keyUsers[key] > 0 {
+			continue
+		}
+		delete(w.keyUsers, key)
+		unused = append(unused, signer)
+	}
+	return unused
+}
+
+// lockKeys locks the keys of the given single key addresses in the wallet server. The caller must hold w.keyMu, but
+// not w.mu.
+func (w *RemoteWallet) lockKeys(signers []address.Address) {
+	for _, signer := range signers {
+		if err := setKeyLock(w.walletServer, EndpointLockKey, signer); err != nil {
+			log.WithError(err).Warnf("Unable to lock key of %s in wallet server", signer)
+		}
+	}
+}
+
+// signersOf returns the single key addresses of the signing keys of the given address. This is the address itself for
+// single key addresses.
+func signersOf(addr address.Address) []address.Address {
+	if !addr.IsMultiSig() {
+		return []address.Address{addr}
+	}
+	keys := addr.GetSigningPubKeys()
+	signers := make([]address.Address, len(keys))
+	for i, key := range keys {
+		signers[i] = address.MakeAddressFromPubKeyByteArray(key)
+	}
+	return signers
+}
+
+// signerKey identifies the signing key of the given single key address.
+func signerKey(signer address.Address) string {
+	return string(signer.GetSchemePubKey())
+}
+
+// setKeyLock calls the given lock or unlock endpoint of the wallet server for the key of the given single key address.
+// Wallet servers that do not support locking keys are ignored.
+func setKeyLock(walletServer Remote, endpoint string, signer address.Address) error {
+	var response wire.KeyLockResponse
+	err := walletServer.CallEndpoint(endpoint, wire.MakeKeyLockRequest(signer), &response)
+	if IsUnsupportedEndpoint(err) {
+		return nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("wallet server could not assert key availability: %w", err)
+		return fmt.Errorf("wallet server could not lock or unlock key: %w", err)
 	}
 	if !response {
-		return nil, fmt.Errorf("wallet server has no private key for address %s", rwAddress)
+		return &KeyUnavailableError{Address: signer, Required: 1}
+	}
+	return nil
+}
+
+// keyAvailable returns true, iff the given wallet server holds the private key of the given address' public key.
+func keyAvailable(walletServer Remote, addr address.Address) (bool, error) {
+	var response wire.KeyAvailabilityResponse
+	err := walletServer.CallEndpoint(EndpointKeyAvailable, wire.MakeKeyAvailabilityRequest(addr), &response)
+	if err != nil {
+		return false, fmt.Errorf("wallet server could not assert key availability: %w", err)
 	}
-	return MakeRemoteAccount(*rwAddress, w.walletServer, w.cardanoWalletID), nil
+	return response, nil
 }
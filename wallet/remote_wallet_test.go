@@ -15,9 +15,12 @@
 package wallet_test
 
 import (
+	"errors"
 	"github.com/stretchr/testify/require"
 	"math/rand"
+	gpwallet "perun.network/go-perun/wallet"
 	gptest "perun.network/go-perun/wallet/test"
+	"perun.network/perun-cardano-backend/errclass"
 	"perun.network/perun-cardano-backend/wallet"
 	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wallet/test"
@@ -97,26 +100,188 @@ func TestRemoteWallet_Unlock(t *testing.T) {
 		err,
 		"unlock should fail if the remote wallet does not have the private key to the given address",
 	)
+	var keyErr *wallet.KeyUnavailableError
+	require.True(t, errors.As(err, &keyErr), "unlock should fail with a KeyUnavailableError")
+	require.Equal(t, r.UnavailableAddress, keyErr.Address, "KeyUnavailableError holds the wrong address")
+	require.Equal(t, errclass.KeyUnavailable, errclass.Of(err), "unlock failure has the wrong error class")
 }
 
-func setup(rng *rand.Rand) *gptest.Setup {
-	r := test.NewGenericRemote([]address.Address{test.MakeRandomAddress(rng)}, rng)
-	w := test.NewRemoteWallet(r)
-	b := wallet.MakeRemoteBackend(r)
-	marshalledAddress, err := test.MakeRandomAddress(rng).MarshalBinary()
-	if err != nil {
-		panic(err)
+func TestRemoteWallet_Usage(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	generic := test.NewGenericRemote([]address.Address{addr}, rng)
+	r := newCountingRemote(generic)
+	w := wallet.NewRemoteWallet(r, "")
+	msg := test.GetRandomByteSlice(1, 0x100, rng)
+
+	require.Panics(t, func() { w.IncrementUsage(&addr) }, "incremented usage of locked account")
+	account, err := w.Unlock(&addr)
+	require.NoError(t, err)
+	require.Equal(t, 1, r.count(wallet.EndpointUnlockKey), "unlocking the account must unlock its key")
+	again, err := w.Unlock(&addr)
+	require.NoError(t, err)
+	require.Equal(t, 1, r.count(wallet.EndpointUnlockKey), "unlocking an unlocked account must not unlock its key")
+
+	w.IncrementUsage(&addr)
+	w.IncrementUsage(&addr)
+	usage, ok := w.UsageCount(&addr)
+	require.True(t, ok)
+	require.Equal(t, 2, usage)
+
+	w.DecrementUsage(&addr)
+	_, err = account.SignData(msg)
+	require.NoError(t, err, "account must be usable while it is used")
+	require.Zero(t, r.count(wallet.EndpointLockKey))
+
+	w.DecrementUsage(&addr)
+	_, ok = w.UsageCount(&addr)
+	require.False(t, ok, "account must be locked after its usage dropped to zero")
+	require.Equal(t, 1, r.count(wallet.EndpointLockKey), "locking the account must lock its key")
+	require.True(t, generic.IsLocked(addr))
+	for _, acc := range []gpwallet.Account{account, again} {
+		_, err = acc.SignData(msg)
+		var lockedErr *wallet.AccountLockedError
+		require.True(t, errors.As(err, &lockedErr), "locked account must refuse signing")
+		require.Equal(t, errclass.KeyUnavailable, errclass.Of(err))
+	}
+	require.Panics(t, func() { w.DecrementUsage(&addr) }, "decremented usage of locked account")
+
+	account, err = w.Unlock(&addr)
+	require.NoError(t, err)
+	require.False(t, generic.IsLocked(addr))
+	w.IncrementUsage(&addr)
+	w.LockAll()
+	_, err = account.SignData(msg)
+	require.Error(t, err, "LockAll must lock all accounts")
+	require.True(t, generic.IsLocked(addr))
+}
+
+func TestRemoteWallet_SharedKeys(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	multiSig, generic := setupMultiSig(rng, 2)
+	signers := generic.AvailableAddresses
+	r := newCountingRemote(generic)
+	w := wallet.NewRemoteWallet(r, "")
+
+	_, err := w.Unlock(&signers[0])
+	require.NoError(t, err)
+	_, err = w.Unlock(&multiSig)
+	require.NoError(t, err)
+	w.IncrementUsage(&signers[0])
+	w.IncrementUsage(&multiSig)
+
+	w.DecrementUsage(&signers[0])
+	require.False(t, generic.IsLocked(signers[0]), "key used by an unlocked account must stay unlocked")
+	w.DecrementUsage(&multiSig)
+	require.True(t, generic.IsLocked(signers[0]))
+	require.True(t, generic.IsLocked(signers[1]))
+	require.Equal(t, 2, r.count(wallet.EndpointLockKey))
+}
+
+// blockingRemote blocks calls to the given endpoint of the wrapped Remote until release is closed. It signals every
+// blocked call on called.
+type blockingRemote struct {
+	wallet.Remote
+	endpoint string
+	called   chan struct{}
+	release  chan struct{}
+}
+
+func (r *blockingRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	if endpoint == r.endpoint {
+		r.called <- struct{}{}
+		<-r.release
 	}
-	zero := b.NewAddress()
-	return &gptest.Setup{
-		Backend:           b,
-		Wallet:            w,
-		AddressInWallet:   &r.AvailableAddresses[0],
-		ZeroAddress:       zero,
-		AddressMarshalled: marshalledAddress,
+	return r.Remote.CallEndpoint(endpoint, body, result)
+}
+
+func TestRemoteWallet_NoLockDuringNetworkCalls(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addresses := []address.Address{test.MakeRandomAddress(rng), test.MakeRandomAddress(rng)}
+	generic := test.NewGenericRemote(addresses, rng)
+	for _, endpoint := range []string{wallet.EndpointKeyAvailable, wallet.EndpointUnlockKey, wallet.EndpointLockKey} {
+		t.Run(endpoint, func(t *testing.T) {
+			r := &blockingRemote{Remote: generic, called: make(chan struct{}), release: make(chan struct{})}
+			w := wallet.NewRemoteWallet(r, "")
+			for i := range addresses {
+				_, err := w.Unlock(&addresses[i])
+				require.NoError(t, err)
+				w.IncrementUsage(&addresses[i])
+			}
+			if endpoint != wallet.EndpointLockKey {
+				w.DecrementUsage(&addresses[0])
+			}
+
+			r.endpoint = endpoint
+			done := make(chan error)
+			go func() {
+				if endpoint == wallet.EndpointLockKey {
+					w.DecrementUsage(&addresses[0])
+					done <- nil
+					return
+				}
+				_, err := w.Unlock(&addresses[0])
+				done <- err
+			}()
+			<-r.called
+
+			usage, ok := w.UsageCount(&addresses[1])
+			require.True(t, ok)
+			require.Equal(t, 1, usage)
+			w.IncrementUsage(&addresses[1])
+			w.SetWalletID(addresses[1], "wallet")
+			require.Equal(t, "wallet", w.WalletID(addresses[1]))
+			close(r.release)
+			require.NoError(t, <-done)
+		})
 	}
 }
 
+func TestRemoteWallet_Accounts(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddress(rng)
+	r := test.NewGenericRemote([]address.Address{addr}, rng)
+	w := wallet.NewRemoteWallet(r, "default")
+
+	accounts, err := w.Accounts()
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, addr.GetPubKey(), accounts[0].GetPubKey())
+	require.Equal(t, "default", w.WalletID(addr), "account of unknown cardano wallet must belong to the default wallet")
+
+	created, err := w.CreateAccount(address.Ed25519, "created")
+	require.NoError(t, err)
+	require.Equal(t, "created", w.WalletID(created))
+	_, err = w.CreateAccount(address.EcdsaSecp256k1, "")
+	require.Error(t, err, "created account of unsupported signature scheme")
+
+	accounts, err = w.Accounts()
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.True(t, accounts[1].Equal(&created))
+
+	account, err := w.Unlock(&created)
+	require.NoError(t, err)
+	require.Equal(t, "created", account.(wallet.RemoteAccount).GetCardanoWalletID())
+
+	w.SetWalletID(addr, "other")
+	account, err = w.Unlock(&addr)
+	require.NoError(t, err)
+	require.Equal(t, "other", account.(wallet.RemoteAccount).GetCardanoWalletID())
+
+	multiSig, err := address.MakeMultiSigAddress(1, [][address.PubKeyLength]byte{
+		test.MakeRandomAddress(rng).GetPubKey(),
+		created.GetPubKey(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "created", w.WalletID(multiSig))
+}
+
+func setup(rng *rand.Rand) *gptest.Setup {
+	r := test.NewGenericRemote([]address.Address{test.MakeRandomAddress(rng)}, rng)
+	return test.NewSetup(rng, r, r.AvailableAddresses[0])
+}
+
 func TestAddress(t *testing.T) {
 	gptest.TestAddress(t, setup(pkgtest.Prng(t)))
 }
@@ -128,3 +293,26 @@ func TestGenericSignatureSize(t *testing.T) {
 func TestAccountWithWalletAndBackend(t *testing.T) {
 	gptest.TestAccountWithWalletAndBackend(t, setup(pkgtest.Prng(t)))
 }
+
+func TestRandomizer(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	r := test.NewGenericRemote(nil, rng)
+	randomizer := test.NewRandomizer(r)
+	b := wallet.MakeRemoteBackend(r)
+
+	acc := randomizer.RandomWallet().NewRandomAccount(rng)
+	require.Len(t, r.AvailableAddresses, 1)
+	require.True(t, acc.Address().Equal(&r.AvailableAddresses[0]))
+	data := test.GetRandomByteSlice(1, 0x100, rng)
+	sig, err := acc.SignData(data)
+	require.NoError(t, err)
+	valid, err := b.VerifySignature(data, sig, acc.Address())
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// Accounts of new wallets are held by the same remote.
+	other := randomizer.NewWallet().NewRandomAccount(rng)
+	require.Len(t, r.AvailableAddresses, 2)
+	require.False(t, other.Address().Equal(acc.Address()))
+	require.False(t, randomizer.NewRandomAddress(rng).Equal(acc.Address()))
+}
@@ -0,0 +1,235 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"fmt"
+	"perun.network/perun-cardano-backend/wire"
+	"strings"
+	"sync"
+)
+
+// RoutingRemote is a Remote in front of multiple wallet servers that hold disjoint sets of keys. Requests tied to a
+// public key are dispatched to the wallet server holding that key. Requests that are not tied to a public key are
+// either split across the wallet servers and their results merged (listing accounts, batch verification) or sent to
+// one of the wallet servers (creating accounts, calculating channel ids).
+type RoutingRemote struct {
+	remotes []Remote
+
+	mu sync.Mutex
+	// routes holds the index of the wallet server that holds the key of every known public key. Public keys no wallet
+	// server held when they were last routed map to noRoute.
+	routes map[string]int
+	// next is the index of the wallet server the next account is created on.
+	next int
+}
+
+// noRoute marks public keys no wallet server holds.
+const noRoute = -1
+
+// NewRoutingRemote returns a new RoutingRemote in front of the given wallet servers. At least one wallet server is
+// required. Requests for public keys no wallet server holds are sent to the first wallet server.
+func NewRoutingRemote(remotes ...Remote) (*RoutingRemote, error) {
+	if len(remotes) == 0 {
+		return nil, fmt.Errorf("routing remote requires at least one wallet server")
+	}
+	return &RoutingRemote{
+		remotes: append([]Remote{}, remotes...),
+		routes:  make(map[string]int),
+	}, nil
+}
+
+// CallEndpoint calls the given endpoint on the wallet server responsible for the given request.
+func (r *RoutingRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	switch endpoint {
+	case EndpointListAccounts:
+		return r.listAccounts(body, result)
+	case EndpointCreateAccount:
+		return r.createAccount(body, result)
+	case EndpointVerifyChannelStateSignatures:
+		return r.verifyBatch(body, result)
+	case EndpointCalculateChannelID:
+		return r.remotes[0].CallEndpoint(endpoint, body, result)
+	}
+	key, err := pubKeyOf(body)
+	if err != nil {
+		return fmt.Errorf("unable to route request to %s: %w", endpoint, err)
+	}
+	isVerification := endpoint == EndpointVerifyDataSignature || endpoint == EndpointVerifyChannelStateSignature
+	i, ok, err := r.route(key, isVerification)
+	if err != nil {
+		return err
+	}
+	switch endpoint {
+	case EndpointKeyAvailable, EndpointLockKey, EndpointUnlockKey:
+		res, isBool := result.(*bool)
+		if !ok && isBool {
+			*res = false
+			return nil
+		}
+	}
+	return r.remotes[i].CallEndpoint(endpoint, body, result)
+}
+
+// route returns the index of the wallet server holding the key of the given public key. If no wallet server holds the
+// key, route returns the index of the first wallet server and false. Verifications do not need the key, so public keys
+// no wallet server held before are not routed again for verifications. This keeps verifying the signatures of other
+// channel participants cheap.
+func (r *RoutingRemote) route(key wire.PubKey, isVerification bool) (int, bool, error) {
+	id := routingKey(key)
+	r.mu.Lock()
+	i, ok := r.routes[id]
+	r.mu.Unlock()
+	if ok && i != noRoute {
+		return i, true, nil
+	}
+	if ok && isVerification {
+		return 0, false, nil
+	}
+	for i, remote := range r.remotes {
+		var available wire.KeyAvailabilityResponse
+		if err := remote.CallEndpoint(EndpointKeyAvailable, key, &available); err != nil {
+			return 0, false, fmt.Errorf("unable to route request: %w", err)
+		}
+		if available {
+			r.addRoute(key, i)
+			return i, true, nil
+		}
+	}
+	r.addRoute(key, noRoute)
+	return 0, false, nil
+}
+
+func (r *RoutingRemote) addRoute(key wire.PubKey, i int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[routingKey(key)] = i
+}
+
+// listAccounts merges the accounts of all wallet servers.
+func (r *RoutingRemote) listAccounts(body interface{}, result interface{}) error {
+	res, ok := result.(*wire.ListAccountsResponse)
+	if !ok {
+		return fmt.Errorf("invalid result type %T for endpoint %s", result, EndpointListAccounts)
+	}
+	var accounts wire.ListAccountsResponse
+	for i, remote := range r.remotes {
+		var response wire.ListAccountsResponse
+		if err := remote.CallEndpoint(EndpointListAccounts, body, &response); err != nil {
+			return err
+		}
+		for _, info := range response {
+			r.addRoute(info.PubKey, i)
+		}
+		accounts = append(accounts, response...)
+	}
+	*res = accounts
+	return nil
+}
+
+// createAccount creates the account on the wallet servers in turns.
+func (r *RoutingRemote) createAccount(body interface{}, result interface{}) error {
+	res, ok := result.(*wire.CreateAccountResponse)
+	if !ok {
+		return fmt.Errorf("invalid result type %T for endpoint %s", result, EndpointCreateAccount)
+	}
+	r.mu.Lock()
+	i := r.next
+	r.next = (r.next + 1) % len(r.remotes)
+	r.mu.Unlock()
+	if err := r.remotes[i].CallEndpoint(EndpointCreateAccount, body, res); err != nil {
+		return err
+	}
+	r.addRoute(res.PubKey, i)
+	return nil
+}
+
+// verifyBatch splits the given batch verification request by wallet server, verifies the parts concurrently and merges
+// the results in the order of the requests.
+func (r *RoutingRemote) verifyBatch(body interface{}, result interface{}) error {
+	request, ok := body.(wire.BatchVerificationRequest)
+	if !ok {
+		return fmt.Errorf("invalid request type %T for endpoint %s", body, EndpointVerifyChannelStateSignatures)
+	}
+	res, ok := result.(*wire.BatchVerificationResponse)
+	if !ok {
+		return fmt.Errorf("invalid result type %T for endpoint %s", result, EndpointVerifyChannelStateSignatures)
+	}
+	parts := make(map[int][]int)
+	for j, req := range request.Requests {
+		i, _, err := r.route(req.PubKey, true)
+		if err != nil {
+			return err
+		}
+		parts[i] = append(parts[i], j)
+	}
+	valid := make(wire.BatchVerificationResponse, len(request.Requests))
+	errs := make(chan error, len(parts))
+	var wg sync.WaitGroup
+	for i, indices := range parts {
+		wg.Add(1)
+		go func(remote Remote, indices []int) {
+			defer wg.Done()
+			part := wire.BatchVerificationRequest{Requests: make([]wire.ChannelStateVerificationRequest, len(indices))}
+			for k, j := range indices {
+				part.Requests[k] = request.Requests[j]
+			}
+			var response wire.BatchVerificationResponse
+			if err := remote.CallEndpoint(EndpointVerifyChannelStateSignatures, part, &response); err != nil {
+				errs <- err
+				return
+			}
+			if len(response) != len(indices) {
+				errs <- fmt.Errorf("wallet server returned %d results for %d verifications", len(response), len(indices))
+				return
+			}
+			for k, j := range indices {
+				valid[j] = response[k]
+			}
+		}(r.remotes[i], indices)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+	*res = valid
+	return nil
+}
+
+// pubKeyOf returns the public key the given request is tied to.
+func pubKeyOf(body interface{}) (wire.PubKey, error) {
+	switch b := body.(type) {
+	case wire.SigningRequest:
+		return b.PubKey, nil
+	case wire.ChannelStateSigningRequest:
+		return b.PubKey, nil
+	case wire.VerificationRequest:
+		return b.PubKey, nil
+	case wire.ChannelStateVerificationRequest:
+		return b.PubKey, nil
+	case wire.PubKey:
+		return b, nil
+	default:
+		return wire.PubKey{}, fmt.Errorf("request of type %T is not tied to a public key", body)
+	}
+}
+
+// routingKey identifies the key of the given public key.
+func routingKey(key wire.PubKey) string {
+	return key.Scheme + ":" + strings.ToLower(key.Hex)
+}
+
+var _ Remote = &RoutingRemote{}
@@ -0,0 +1,94 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet_test
+
+import (
+	"github.com/stretchr/testify/require"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+func TestRoutingRemote(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addresses := []address.Address{test.MakeRandomAddress(rng), test.MakeRandomAddress(rng)}
+	servers := []*countingRemote{
+		newCountingRemote(test.NewGenericRemote([]address.Address{addresses[0]}, rng)),
+		newCountingRemote(test.NewGenericRemote([]address.Address{addresses[1]}, rng)),
+	}
+	_, err := wallet.NewRoutingRemote()
+	require.Error(t, err, "created routing remote without wallet servers")
+	r, err := wallet.NewRoutingRemote(servers[0], servers[1])
+	require.NoError(t, err)
+	w := wallet.NewRemoteWallet(r, "")
+	backend := wallet.MakeRemoteBackend(r)
+	state := ctest.MakeRandomChannelState(rng)
+
+	sigs := make([][]byte, len(addresses))
+	for i := range addresses {
+		acc, err := w.Unlock(&addresses[i])
+		require.NoError(t, err)
+		sigs[i], err = acc.(wallet.RemoteAccount).SignChannelState(state)
+		require.NoError(t, err)
+		for j, server := range servers {
+			expected := 0
+			if j <= i {
+				expected = 1
+			}
+			require.Equal(t, expected, server.count(wallet.EndpointSignChannelState), "request was routed to the wrong server")
+		}
+	}
+
+	other := test.MakeRandomAddress(rng)
+	_, err = w.Unlock(&other)
+	require.Error(t, err, "unlocked account no wallet server holds")
+
+	signatures := []types.ChannelStateSignature{
+		{State: state, Sig: sigs[0], Address: &addresses[0]},
+		{State: state, Sig: sigs[1], Address: &addresses[1]},
+		{State: state, Sig: sigs[0], Address: &addresses[1]},
+		{State: state, Sig: test.MakeRandomSignature(rng), Address: &other},
+	}
+	valid, err := backend.VerifyChannelStateSignatures(signatures)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true, false, false}, valid)
+	require.Equal(t, 1, servers[0].count(wallet.EndpointVerifyChannelStateSignatures))
+	require.Equal(t, 1, servers[1].count(wallet.EndpointVerifyChannelStateSignatures))
+
+	keyQueries := servers[1].count(wallet.EndpointKeyAvailable)
+	ok, err := backend.VerifyChannelStateSignature(state, test.MakeRandomSignature(rng), &other)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, keyQueries, servers[1].count(wallet.EndpointKeyAvailable), "unheld key was routed again for verification")
+
+	created, err := w.CreateAccount(address.Ed25519, "")
+	require.NoError(t, err)
+	accounts, err := w.Accounts()
+	require.NoError(t, err)
+	expected := [][address.PubKeyLength]byte{addresses[0].GetPubKey(), created.GetPubKey(), addresses[1].GetPubKey()}
+	require.Len(t, accounts, len(expected))
+	for i, key := range expected {
+		require.Equal(t, key, accounts[i].GetPubKey(), "accounts are not merged in the order of the servers")
+	}
+	acc, err := w.Unlock(&created)
+	require.NoError(t, err)
+	_, err = acc.SignData([]byte("message"))
+	require.NoError(t, err)
+	require.Equal(t, 1, servers[0].count(wallet.EndpointSignData), "account was created on the wrong server")
+}
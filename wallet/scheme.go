@@ -0,0 +1,173 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+// SignatureScheme is a signature scheme that addresses may use to sign data.
+// Signatures of a scheme are created by the wallet server. Signatures on data of schemes other than address.Ed25519
+// are verified locally with Verify. Schemes other than address.Ed25519 are off-chain only: the channel validator only
+// verifies Ed25519 signatures, so addresses of other schemes can not be channel participants (see
+// types.MakeChannelParameters).
+type SignatureScheme interface {
+	// ID returns the identifier of the scheme which is recorded in the addresses using it.
+	ID() address.SignatureScheme
+	// SignatureLength returns the length of raw signatures of this scheme in bytes.
+	SignatureLength() int
+	// Verify returns true, iff the given raw signature is valid for the given message under the given public key
+	// (encoded as returned by address.Address.GetSchemePubKey).
+	Verify(pubKey []byte, msg []byte, sig []byte) (bool, error)
+}
+
+// builtinSignatureSchemes holds the signature schemes every SignatureSchemes supports. It must not be modified.
+var builtinSignatureSchemes = map[address.SignatureScheme]SignatureScheme{
+	address.Ed25519:          Ed25519Scheme{},
+	address.EcdsaSecp256k1:   EcdsaSecp256k1Scheme{},
+	address.SchnorrSecp256k1: SchnorrSecp256k1Scheme{},
+}
+
+// SignatureSchemes is an immutable set of signature schemes by ID, which is passed to the backend, accounts, wallets
+// and remotes that verify or decode signatures. The zero value holds the built-in schemes Ed25519Scheme,
+// EcdsaSecp256k1Scheme and SchnorrSecp256k1Scheme.
+type SignatureSchemes struct {
+	custom map[address.SignatureScheme]SignatureScheme
+}
+
+// NewSignatureSchemes returns SignatureSchemes holding the built-in schemes and the given schemes. The given schemes
+// replace built-in schemes with the same ID.
+func NewSignatureSchemes(schemes ...SignatureScheme) SignatureSchemes {
+	custom := make(map[address.SignatureScheme]SignatureScheme, len(schemes))
+	for _, scheme := range schemes {
+		custom[scheme.ID()] = scheme
+	}
+	return SignatureSchemes{custom: custom}
+}
+
+// Get returns the signature scheme with the given ID.
+func (s SignatureSchemes) Get(id address.SignatureScheme) (SignatureScheme, error) {
+	if scheme, ok := s.custom[id]; ok {
+		return scheme, nil
+	}
+	if scheme, ok := builtinSignatureSchemes[id]; ok {
+		return scheme, nil
+	}
+	return nil, fmt.Errorf("signature scheme is not supported: %s", id)
+}
+
+// Ed25519Scheme is the native Cardano signature scheme.
+type Ed25519Scheme struct{}
+
+// ID returns address.Ed25519.
+func (Ed25519Scheme) ID() address.SignatureScheme {
+	return address.Ed25519
+}
+
+// SignatureLength returns wire.SignatureLength.
+func (Ed25519Scheme) SignatureLength() int {
+	return wire.SignatureLength
+}
+
+// Verify verifies the given Ed25519 signature.
+func (Ed25519Scheme) Verify(pubKey []byte, msg []byte, sig []byte) (bool, error) {
+	if len(pubKey) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid Ed25519 public key length: %d bytes", len(pubKey))
+	}
+	return ed25519.Verify(pubKey, msg, sig), nil
+}
+
+// EcdsaSecp256k1Scheme is ECDSA over secp256k1 with signatures encoded as the 32 byte big-endian r followed by the 32
+// byte big-endian s. Only canonical signatures (low s) are valid, as required by the Plutus builtin. Data signatures
+// sign the SHA-256 digest of the data, because the Plutus builtin only accepts 32 byte messages.
+type EcdsaSecp256k1Scheme struct{}
+
+// ID returns address.EcdsaSecp256k1.
+func (EcdsaSecp256k1Scheme) ID() address.SignatureScheme {
+	return address.EcdsaSecp256k1
+}
+
+// SignatureLength returns 64.
+func (EcdsaSecp256k1Scheme) SignatureLength() int {
+	return 64
+}
+
+// Verify verifies the given ECDSA signature on the SHA-256 digest of msg.
+func (EcdsaSecp256k1Scheme) Verify(pubKey []byte, msg []byte, sig []byte) (bool, error) {
+	key, err := btcec.ParsePubKey(pubKey)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse secp256k1 public key: %w", err)
+	}
+	if len(sig) != 64 {
+		return false, nil
+	}
+	var r, s btcec.ModNScalar
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) || s.IsOverHalfOrder() {
+		return false, nil
+	}
+	digest := sha256.Sum256(msg)
+	return ecdsa.NewSignature(&r, &s).Verify(digest[:], key), nil
+}
+
+// SchnorrSecp256k1Scheme is BIP-340 Schnorr over secp256k1. Data signatures sign the SHA-256 digest of the data.
+type SchnorrSecp256k1Scheme struct{}
+
+// ID returns address.SchnorrSecp256k1.
+func (SchnorrSecp256k1Scheme) ID() address.SignatureScheme {
+	return address.SchnorrSecp256k1
+}
+
+// SignatureLength returns schnorr.SignatureSize.
+func (SchnorrSecp256k1Scheme) SignatureLength() int {
+	return schnorr.SignatureSize
+}
+
+// Verify verifies the given BIP-340 signature on the SHA-256 digest of msg.
+func (SchnorrSecp256k1Scheme) Verify(pubKey []byte, msg []byte, sig []byte) (bool, error) {
+	key, err := schnorr.ParsePubKey(pubKey)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse x-only secp256k1 public key: %w", err)
+	}
+	signature, err := schnorr.ParseSignature(sig)
+	if err != nil {
+		return false, nil
+	}
+	digest := sha256.Sum256(msg)
+	return signature.Verify(digest[:], key), nil
+}
+
+var (
+	_ SignatureScheme = Ed25519Scheme{}
+	_ SignatureScheme = EcdsaSecp256k1Scheme{}
+	_ SignatureScheme = SchnorrSecp256k1Scheme{}
+)
+
+// verifySchemeSignature verifies a data signature of an address with a signature scheme other than address.Ed25519
+// locally with the given schemes.
+func verifySchemeSignature(schemes SignatureSchemes, msg []byte, sig wallet.Sig, addr address.Address) (bool, error) {
+	raw, scheme, err := unwrapSig(schemes, sig, addr)
+	if err != nil {
+		return false, err
+	}
+	return scheme.Verify(addr.GetSchemePubKey(), msg, raw)
+}
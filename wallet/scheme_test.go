@@ -0,0 +1,175 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+// makeSchemeKey returns an address of the given secp256k1 signature scheme and a function that signs the SHA-256
+// digest of a message with the corresponding private key.
+func makeSchemeKey(t *testing.T, rng *rand.Rand, scheme address.SignatureScheme) (address.Address, func([]byte) []byte) {
+	seed := make([]byte, 32)
+	rng.Read(seed)
+	priv, pub := btcec.PrivKeyFromBytes(seed)
+	var pubKey []byte
+	var sign func(digest []byte) []byte
+	switch scheme {
+	case address.EcdsaSecp256k1:
+		pubKey = pub.SerializeCompressed()
+		sign = func(digest []byte) []byte {
+			// SignCompact returns the recovery code followed by r and s.
+			sig, err := ecdsa.SignCompact(priv, digest, true)
+			require.NoError(t, err, "this should not fail!")
+			return sig[1:]
+		}
+	case address.SchnorrSecp256k1:
+		pubKey = schnorr.SerializePubKey(pub)
+		sign = func(digest []byte) []byte {
+			sig, err := schnorr.Sign(priv, digest)
+			require.NoError(t, err, "this should not fail!")
+			return sig.Serialize()
+		}
+	}
+	addr, err := address.MakeAddressFromSchemePubKey(scheme, pubKey)
+	require.NoError(t, err, "unable to create address from valid public key")
+	return addr, func(msg []byte) []byte {
+		digest := sha256.Sum256(msg)
+		return sign(digest[:])
+	}
+}
+
+func TestSignatureScheme_Ed25519(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	pub, priv, err := ed25519.GenerateKey(rng)
+	require.NoError(t, err, "this should not fail!")
+	msg := test.GetRandomByteSlice(0, 0x100, rng)
+	scheme, err := wallet.SignatureSchemes{}.Get(address.Ed25519)
+	require.NoError(t, err, "Ed25519 is not supported by default")
+	valid, err := scheme.Verify(pub, msg, ed25519.Sign(priv, msg))
+	require.NoError(t, err, "unable to verify valid signature")
+	require.True(t, valid, "valid signature was not verified as valid")
+	valid, err = scheme.Verify(pub, append(msg, 0), ed25519.Sign(priv, msg))
+	require.NoError(t, err, "unable to verify invalid signature")
+	require.False(t, valid, "invalid signature was verified as valid")
+}
+
+func TestSignatureScheme_Secp256k1(t *testing.T) {
+	for _, id := range []address.SignatureScheme{address.EcdsaSecp256k1, address.SchnorrSecp256k1} {
+		id := id
+		t.Run(id.String(), func(t *testing.T) {
+			rng := pkgtest.Prng(t)
+			addr, sign := makeSchemeKey(t, rng, id)
+			backend := wallet.MakeRemoteBackend(test.NewMockRemote(rng))
+			msg := test.GetRandomByteSlice(0, 0x100, rng)
+			scheme, err := wallet.SignatureSchemes{}.Get(id)
+			require.NoError(t, err, "built-in signature scheme is not supported by default")
+			rawSig := sign(msg)
+			require.Len(t, rawSig, scheme.SignatureLength(), "signature has unexpected length")
+
+			valid, err := scheme.Verify(addr.GetSchemePubKey(), msg, rawSig)
+			require.NoError(t, err, "unable to verify valid signature")
+			require.True(t, valid, "valid signature was not verified as valid")
+
+			sig, err := wallet.MakeSchemeSig(id, rawSig)
+			require.NoError(t, err, "unable to wrap signature")
+			require.True(t, wallet.IsSchemeSig(sig), "wrapped signature is not recognized")
+			valid, err = backend.VerifySignature(msg, sig, &addr)
+			require.NoError(t, err, "unable to verify valid signature")
+			require.True(t, valid, "valid signature was not verified as valid")
+
+			valid, err = backend.VerifySignature(append(msg, 0), sig, &addr)
+			require.NoError(t, err, "unable to verify invalid signature")
+			require.False(t, valid, "signature on other message was verified as valid")
+
+			_, err = backend.VerifySignature(msg, rawSig, &addr)
+			require.Error(t, err, "failed to error on unwrapped signature")
+			otherScheme, err := wallet.MakeSchemeSig(address.Ed25519, rawSig)
+			require.NoError(t, err, "this should not fail!")
+			_, err = backend.VerifySignature(msg, otherScheme, &addr)
+			require.Error(t, err, "failed to error on signature of wrong scheme")
+			tooLong, err := wallet.MakeSchemeSig(id, append(rawSig, 0))
+			require.NoError(t, err, "this should not fail!")
+			_, err = backend.VerifySignature(msg, tooLong, &addr)
+			require.Error(t, err, "failed to error on signature of invalid length")
+
+			// DecodeSig must read exactly one wrapped signature from the stream.
+			rest := test.MakeRandomSignature(rng)
+			reader := bytes.NewReader(append(append([]byte{}, sig...), rest...))
+			decoded, err := backend.DecodeSig(reader)
+			require.NoError(t, err, "unable to decode wrapped signature from reader")
+			require.Equal(t, sig, decoded, "decoded signature is not as expected")
+			decodedScheme, decodedRaw, err := wallet.DecodeSchemeSig(decoded)
+			require.NoError(t, err, "unable to unwrap decoded signature")
+			require.Equal(t, id, decodedScheme, "decoded signature scheme is not as expected")
+			require.Equal(t, rawSig, decodedRaw, "decoded raw signature is not as expected")
+		})
+	}
+}
+
+// constantScheme is a custom signature scheme that accepts exactly one signature.
+type constantScheme struct {
+	sig []byte
+}
+
+func (s constantScheme) ID() address.SignatureScheme {
+	return 0x42
+}
+
+func (s constantScheme) SignatureLength() int {
+	return len(s.sig)
+}
+
+func (s constantScheme) Verify(_ []byte, _ []byte, sig []byte) (bool, error) {
+	return bytes.Equal(s.sig, sig), nil
+}
+
+func TestSignatureSchemes_Custom(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	scheme := constantScheme{sig: test.GetRandomByteSlice(1, 0x80, rng)}
+	_, err := wallet.SignatureSchemes{}.Get(scheme.ID())
+	require.Error(t, err, "failed to error on unsupported signature scheme")
+	schemes := wallet.NewSignatureSchemes(scheme)
+	got, err := schemes.Get(scheme.ID())
+	require.NoError(t, err, "custom signature scheme was not found")
+	require.Equal(t, scheme, got, "custom signature scheme is not as expected")
+	_, err = schemes.Get(address.Ed25519)
+	require.NoError(t, err, "built-in signature scheme is not supported alongside custom schemes")
+
+	addr, err := address.MakeAddressFromSchemePubKey(scheme.ID(), test.GetRandomByteSlice(32, 32, rng))
+	require.NoError(t, err, "unable to create address of custom signature scheme")
+	sig, err := wallet.MakeSchemeSig(scheme.ID(), scheme.sig)
+	require.NoError(t, err, "this should not fail!")
+	backend := wallet.MakeRemoteBackend(test.NewMockRemote(rng))
+	_, err = backend.VerifySignature(nil, sig, &addr)
+	require.Error(t, err, "backend without custom schemes failed to error on custom signature scheme")
+	valid, err := backend.WithSignatureSchemes(schemes).VerifySignature(nil, sig, &addr)
+	require.NoError(t, err, "unable to verify signature of custom signature scheme")
+	require.True(t, valid, "valid signature of custom signature scheme was not verified as valid")
+}
+
+var _ wallet.SignatureScheme = constantScheme{}
@@ -0,0 +1,155 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"perun.network/perun-cardano-backend/errclass"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+// DefaultMaxRequestSize is the maximum size of request bodies accepted by a Server without a configured limit.
+const DefaultMaxRequestSize = 1 << 20
+
+// Server is an http.Handler serving the json api of the perun-cardano-wallet server with the given Remote (e.g. a
+// keyfile.Remote holding local keys). It is the counterpart of PerunCardanoWallet: every endpoint expects a POST
+// request with the json encoded wire request of the endpoint and responds with the json encoded wire response.
+// Unknown endpoints are answered with 404 Not Found, malformed requests (see InvalidRequestError) with 400 Bad Request,
+// requests for keys the Remote does not hold with 403 Forbidden and other failures with 500 Internal Server Error.
+type Server struct {
+	remote         Remote
+	maxRequestSize int64
+}
+
+// NewServer returns a new Server for the given Remote.
+func NewServer(remote Remote) *Server {
+	return &Server{remote: remote, maxRequestSize: DefaultMaxRequestSize}
+}
+
+// SetMaxRequestSize sets the maximum size of request bodies in bytes.
+func (s *Server) SetMaxRequestSize(size int64) {
+	s.maxRequestSize = size
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isServerEndpoint(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequestSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+	body, result, err := decodeServerRequest(r.URL.Path, data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("malformed request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err = s.remote.CallEndpoint(r.URL.Path, body, result); err != nil {
+		http.Error(w, err.Error(), serverStatusOf(err))
+		return
+	}
+	response, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_, _ = w.Write(response)
+}
+
+// isServerEndpoint returns true, iff the given endpoint is served by a Server.
+func isServerEndpoint(endpoint string) bool {
+	switch endpoint {
+	case EndpointSignData, EndpointSignChannelState, EndpointVerifyDataSignature, EndpointVerifyChannelStateSignature,
+		EndpointVerifyChannelStateSignatures, EndpointKeyAvailable, EndpointLockKey, EndpointUnlockKey,
+		EndpointListAccounts, EndpointCreateAccount, EndpointCalculateChannelID:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeServerRequest decodes the given json request to the given endpoint and returns it together with a pointer to
+// the result of the endpoint, as expected by Remote.CallEndpoint.
+func decodeServerRequest(endpoint string, data []byte) (interface{}, interface{}, error) {
+	switch endpoint {
+	case EndpointSignData:
+		var body wire.SigningRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.SigningResponse), err
+	case EndpointSignChannelState:
+		var body wire.ChannelStateSigningRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.SigningResponse), err
+	case EndpointVerifyDataSignature:
+		var body wire.VerificationRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.VerificationResponse), err
+	case EndpointVerifyChannelStateSignature:
+		var body wire.ChannelStateVerificationRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.VerificationResponse), err
+	case EndpointVerifyChannelStateSignatures:
+		var body wire.BatchVerificationRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.BatchVerificationResponse), err
+	case EndpointKeyAvailable:
+		var body wire.KeyAvailabilityRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.KeyAvailabilityResponse), err
+	case EndpointLockKey, EndpointUnlockKey:
+		var body wire.KeyLockRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.KeyLockResponse), err
+	case EndpointListAccounts:
+		return wire.ListAccountsRequest{}, new(wire.ListAccountsResponse), nil
+	case EndpointCreateAccount:
+		var body wire.CreateAccountRequest
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.CreateAccountResponse), err
+	case EndpointCalculateChannelID:
+		var body wire.ChannelParameters
+		err := json.Unmarshal(data, &body)
+		return body, new(wire.ChannelID), err
+	default:
+		return nil, nil, fmt.Errorf("invalid endpoint: %s", endpoint)
+	}
+}
+
+// serverStatusOf returns the http status code a Server responds with to requests failing with the given error.
+func serverStatusOf(err error) int {
+	if IsUnsupportedEndpoint(err) {
+		return http.StatusNotFound
+	}
+	switch errclass.Of(err) {
+	case errclass.Client:
+		return http.StatusBadRequest
+	case errclass.KeyUnavailable:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
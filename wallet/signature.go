@@ -0,0 +1,149 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+)
+
+// Signatures that are not a single plain Ed25519 signature are wrapped in an envelope, because go-perun writes
+// signatures without a length prefix and DecodeSig must be able to tell where a signature ends. The envelope starts
+// with a header of wire.SignatureLength bytes whose last byte is envelopeMarker. The last byte of an Ed25519 signature
+// is the most significant byte of the scalar S, which is smaller than the group order (< 2^253) for every signature
+// accepted by the Cardano ledger. Therefore, it is always smaller than 0x20 and a plain signature is never mistaken for
+// an envelope.
+const envelopeMarker byte = 0xff
+
+// The envelope header consists of the envelope type, followed by the big-endian uint16 length of the payload. Scheme
+// envelopes additionally hold the signature scheme at envelopeSchemeOffset.
+const (
+	envelopeTypeOffset   = 0
+	envelopeLengthOffset = 1
+	envelopeSchemeOffset = 3
+)
+
+const envelopeTypeScheme byte = 0x02
+
+// makeEnvelope returns the header of an envelope of the given type with the given payload length.
+func makeEnvelope(envelopeType byte, payloadLength int) (wallet.Sig, error) {
+	if payloadLength > 0xffff {
+		return nil, fmt.Errorf("signature envelope payload is too long: %d bytes", payloadLength)
+	}
+	sig := make(wallet.Sig, wire.SignatureLength, wire.SignatureLength+payloadLength)
+	sig[envelopeTypeOffset] = envelopeType
+	binary.BigEndian.PutUint16(sig[envelopeLengthOffset:], uint16(payloadLength))
+	sig[wire.SignatureLength-1] = envelopeMarker
+	return sig, nil
+}
+
+// isEnvelope returns true, iff the given signature is an envelope of the given type.
+func isEnvelope(sig wallet.Sig, envelopeType byte) bool {
+	return len(sig) >= wire.SignatureLength &&
+		sig[wire.SignatureLength-1] == envelopeMarker &&
+		sig[envelopeTypeOffset] == envelopeType
+}
+
+// envelopePayload returns the payload of the given envelope and checks its length against the header.
+func envelopePayload(sig wallet.Sig) (wallet.Sig, error) {
+	payload := sig[wire.SignatureLength:]
+	if int(binary.BigEndian.Uint16(sig[envelopeLengthOffset:])) != len(payload) {
+		return nil, fmt.Errorf("signature envelope has invalid payload length: %d", len(payload))
+	}
+	return payload, nil
+}
+
+// readSig reads a plain signature or a signature envelope from the given reader.
+func readSig(reader io.Reader) (wallet.Sig, error) {
+	sig := make([]byte, wire.SignatureLength)
+	if _, err := io.ReadFull(reader, sig); err != nil {
+		return nil, fmt.Errorf("unable to read signature from reader: %w", err)
+	}
+	if sig[wire.SignatureLength-1] != envelopeMarker {
+		return sig, nil
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(sig[envelopeLengthOffset:]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, fmt.Errorf("unable to read signature envelope payload from reader: %w", err)
+	}
+	return append(sig, payload...), nil
+}
+
+// MakeSchemeSig wraps the given raw signature of the given signature scheme. Signatures of addresses with a signature
+// scheme other than address.Ed25519 are always wrapped, because they may be of any length and their last byte is not
+// restricted.
+func MakeSchemeSig(scheme address.SignatureScheme, rawSig []byte) (wallet.Sig, error) {
+	sig, err := makeEnvelope(envelopeTypeScheme, len(rawSig))
+	if err != nil {
+		return nil, err
+	}
+	sig[envelopeSchemeOffset] = byte(scheme)
+	return append(sig, rawSig...), nil
+}
+
+// IsSchemeSig returns true, iff the given signature is a wrapped signature of a signature scheme.
+func IsSchemeSig(sig wallet.Sig) bool {
+	return isEnvelope(sig, envelopeTypeScheme)
+}
+
+// DecodeSchemeSig returns the signature scheme and the raw signature of the given wrapped signature.
+func DecodeSchemeSig(sig wallet.Sig) (address.SignatureScheme, []byte, error) {
+	if !IsSchemeSig(sig) {
+		return 0, nil, errors.New("signature is not a signature scheme envelope")
+	}
+	payload, err := envelopePayload(sig)
+	if err != nil {
+		return 0, nil, err
+	}
+	return address.SignatureScheme(sig[envelopeSchemeOffset]), append([]byte{}, payload...), nil
+}
+
+// unwrapSig returns the raw signature of the given signature for the given single key address and the signature scheme
+// to verify it with. Ed25519 signatures must be plain signatures, signatures of all other schemes must be wrapped in a
+// scheme envelope of the signature scheme of the address.
+func unwrapSig(schemes SignatureSchemes, sig wallet.Sig, addr address.Address) ([]byte, SignatureScheme, error) {
+	scheme, err := schemes.Get(addr.GetSignatureScheme())
+	if err != nil {
+		return nil, nil, err
+	}
+	raw := []byte(sig)
+	if scheme.ID() != address.Ed25519 {
+		var sigScheme address.SignatureScheme
+		sigScheme, raw, err = DecodeSchemeSig(sig)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sigScheme != scheme.ID() {
+			return nil, nil, fmt.Errorf(
+				"signature scheme does not match address. expected: %s actual: %s",
+				scheme.ID(),
+				sigScheme,
+			)
+		}
+	}
+	if len(raw) != scheme.SignatureLength() {
+		return nil, nil, fmt.Errorf(
+			"signature has incorrect length. expected: %d bytes actual: %d bytes",
+			scheme.SignatureLength(),
+			len(raw),
+		)
+	}
+	return raw, scheme, nil
+}
@@ -33,9 +33,78 @@ func MakeRandomAddress(rng *rand.Rand) address.Address {
 	return addr
 }
 
+// MakeRandomAddressWithStakeCredential returns a random Address that carries a random stake credential.
+func MakeRandomAddressWithStakeCredential(rng *rand.Rand) address.Address {
+	addr := MakeRandomAddress(rng)
+	addr.SetStakeCredential(MakeRandomCredential(rng))
+	return addr
+}
+
+// MakeRandomCredential returns a key hash or script hash credential with a random hash.
+func MakeRandomCredential(rng *rand.Rand) address.Credential {
+	c := address.Credential{Type: address.CredentialType(rng.Intn(2))}
+	rng.Read(c.Hash[:])
+	return c
+}
+
+// MakeRandomCardanoAddress returns a random CIP-19 address of random kind on a random network.
+func MakeRandomCardanoAddress(rng *rand.Rand) address.CardanoAddress {
+	network := address.Network(rng.Intn(2))
+	switch address.Kind(rng.Intn(4)) {
+	case address.BaseAddress:
+		return address.MakeBaseAddress(network, MakeRandomCredential(rng), MakeRandomCredential(rng))
+	case address.PointerAddress:
+		return address.MakePointerAddress(network, MakeRandomCredential(rng), address.Pointer{
+			Slot:      rng.Uint64(),
+			TxIndex:   uint64(rng.Uint32()),
+			CertIndex: uint64(rng.Intn(0x100)),
+		})
+	case address.EnterpriseAddress:
+		return address.MakeEnterpriseAddress(network, MakeRandomCredential(rng))
+	default:
+		return address.MakeRewardAddress(network, MakeRandomCredential(rng))
+	}
+}
+
+// MakeRandomMultiSigAddress returns a random multi-signature Address with a script hash payment credential and
+// between one and maxKeys signing keys.
+func MakeRandomMultiSigAddress(rng *rand.Rand, maxKeys int) address.Address {
+	keys := make([][address.PubKeyLength]byte, rng.Intn(maxKeys)+1)
+	for i := range keys {
+		rng.Read(keys[i][:])
+	}
+	addr, err := address.MakeMultiSigAddress(rng.Intn(len(keys))+1, keys)
+	if err != nil {
+		panic(err)
+	}
+	scriptHash := [address.CredentialHashLength]byte{}
+	rng.Read(scriptHash[:])
+	addr.SetPaymentCredential(address.MakeScriptHashCredential(scriptHash))
+	return addr
+}
+
+// MakeRandomSchemeAddress returns a random Address with a random public key of a random built-in signature scheme and
+// a random payment public key hash.
+func MakeRandomSchemeAddress(rng *rand.Rand) address.Address {
+	scheme := address.SignatureScheme(rng.Intn(int(address.SchnorrSecp256k1) + 1))
+	pubKey := GetRandomByteSlice(address.PubKeyLength, address.PubKeyLength, rng)
+	if scheme == address.EcdsaSecp256k1 {
+		pubKey = append([]byte{byte(0x02 + rng.Intn(2))}, pubKey...)
+	}
+	addr, err := address.MakeAddressFromSchemePubKey(scheme, pubKey)
+	if err != nil {
+		panic(err)
+	}
+	_ = addr.SetPaymentPubKeyHashFromSlice(GetRandomByteSlice(address.PubKeyHashLength, address.PubKeyHashLength, rng))
+	return addr
+}
+
+// MakeRandomSignature returns a random plain Ed25519 signature. Like in valid Ed25519 signatures, the most significant
+// byte of S (the last byte) is smaller than 0x20, so the signature is never mistaken for a signature envelope.
 func MakeRandomSignature(rng *rand.Rand) gpwallet.Sig {
 	sig := make([]byte, wire.SignatureLength)
 	rng.Read(sig)
+	sig[wire.SignatureLength-1] &= 0x1f
 	return sig
 }
 
@@ -52,9 +121,13 @@ func MakeTooFewAddressBytes(rng *rand.Rand) []byte {
 	return GetRandomByteSlice(0, address.AddressLength-1, rng)
 }
 
+// MakeTooManyAddressBytes returns a byte slice that is longer than AddressLength but is not a valid extended address
+// encoding, because its flags byte is zero.
 func MakeTooManyAddressBytes(rng *rand.Rand) []byte {
 	const maxInvalidAddressLength = address.AddressLength * 2
-	return GetRandomByteSlice(address.AddressLength+1, maxInvalidAddressLength, rng)
+	b := GetRandomByteSlice(address.AddressLength+1, maxInvalidAddressLength, rng)
+	b[address.AddressLength] = 0
+	return b
 }
 
 func MakeTooLongSignature(rng *rand.Rand) gpwallet.Sig {
@@ -0,0 +1,122 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"perun.network/perun-cardano-backend/transport"
+	"perun.network/perun-cardano-backend/wallet"
+	"sync"
+	"time"
+)
+
+// RemoteFaults configures the faults a FaultyRemote injects into calls. Rates are probabilities in [0, 1], which are
+// rolled independently for every call in the order of the fields.
+type RemoteFaults struct {
+	// MaxLatency is the maximum latency added to every call. The latency is uniformly distributed in [0, MaxLatency].
+	MaxLatency time.Duration
+	// DropRate is the probability of a call failing with a transport.NetworkError before it reaches the remote.
+	DropRate float64
+	// ServerErrorRate is the probability of a call failing with a transport.StatusError (503 Service Unavailable)
+	// before it reaches the remote.
+	ServerErrorRate float64
+	// LostResponseRate is the probability of the response of a call getting lost after the call reached the remote.
+	// The call fails with a transport.NetworkError, or with a transport.AmbiguousError, if the endpoint is not
+	// idempotent.
+	LostResponseRate float64
+	// TruncateRate is the probability of the json response of a call being truncated after the call reached the
+	// remote, so that decoding it fails.
+	TruncateRate float64
+}
+
+// FaultyRemote is a wallet.Remote decorator, which injects the faults configured by RemoteFaults into the calls to the
+// decorated remote. The faults are driven by the given rng, so a failing run can be reproduced with the same seed, as
+// long as calls are issued in the same order.
+type FaultyRemote struct {
+	remote wallet.Remote
+	mu     sync.Mutex
+	rng    *rand.Rand
+	faults RemoteFaults
+}
+
+// NewFaultyRemote returns a new FaultyRemote decorating the given remote. The FaultyRemote must receive an exclusive
+// rand.Rand instance.
+func NewFaultyRemote(remote wallet.Remote, rng *rand.Rand, faults RemoteFaults) *FaultyRemote {
+	return &FaultyRemote{remote: remote, rng: rng, faults: faults}
+}
+
+// SetFaults sets the faults injected into subsequent calls.
+func (r *FaultyRemote) SetFaults(faults RemoteFaults) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.faults = faults
+}
+
+// CallEndpoint calls the given endpoint of the decorated remote, unless a fault prevents it.
+func (r *FaultyRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	latency, drop, serverError, lostResponse, truncate := r.roll()
+	time.Sleep(latency)
+	switch {
+	case drop:
+		return &transport.NetworkError{Endpoint: endpoint, URL: endpoint, Err: errors.New("connection dropped")}
+	case serverError:
+		return &transport.StatusError{
+			Server:     "wallet server",
+			Endpoint:   endpoint,
+			StatusCode: http.StatusServiceUnavailable,
+			Status:     fmt.Sprintf("%d %s", http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)),
+		}
+	}
+	if err := r.remote.CallEndpoint(endpoint, body, result); err != nil {
+		return err
+	}
+	switch {
+	case lostResponse:
+		err := &transport.NetworkError{Endpoint: endpoint, URL: endpoint, Err: errors.New("response lost")}
+		if wallet.IsIdempotentEndpoint(endpoint) {
+			return err
+		}
+		return &transport.AmbiguousError{Err: err}
+	case truncate:
+		response, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(response[:len(response)/2], result); err != nil {
+			return fmt.Errorf("failed to unmarshal wallet server response: %w", err)
+		}
+	}
+	return nil
+}
+
+// roll rolls the faults of a single call.
+func (r *FaultyRemote) roll() (latency time.Duration, drop, serverError, lostResponse, truncate bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.faults.MaxLatency > 0 {
+		latency = time.Duration(r.rng.Int63n(int64(r.faults.MaxLatency) + 1))
+	}
+	drop = r.rng.Float64() < r.faults.DropRate
+	serverError = r.rng.Float64() < r.faults.ServerErrorRate
+	lostResponse = r.rng.Float64() < r.faults.LostResponseRate
+	truncate = r.rng.Float64() < r.faults.TruncateRate
+	return
+}
+
+var _ wallet.Remote = &FaultyRemote{}
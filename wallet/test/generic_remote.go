@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"math/rand"
 	gpwallet "perun.network/go-perun/wallet"
-	"perun.network/perun-cardano-backend/channel/test"
 	"perun.network/perun-cardano-backend/channel/types"
 	"perun.network/perun-cardano-backend/wallet"
 	"perun.network/perun-cardano-backend/wallet/address"
@@ -28,31 +27,6 @@ import (
 	"polycry.pt/poly-go/sync"
 )
 
-type channelIDOracle struct {
-	lock       sync.Mutex
-	parameters []types.ChannelParameters
-	ids        []types.ID
-}
-
-var ChannelIDOracle = &channelIDOracle{
-	parameters: []types.ChannelParameters{},
-	ids:        []types.ID{},
-}
-
-func (o *channelIDOracle) GetChannelID(params types.ChannelParameters, rng *rand.Rand) types.ID {
-	o.lock.Lock()
-	defer o.lock.Unlock()
-	for i, p := range o.parameters {
-		if p.Equal(params) {
-			return o.ids[i]
-		}
-	}
-	o.parameters = append([]types.ChannelParameters{params}, o.parameters...)
-	id := test.MakeRandomChannelID(rng)
-	o.ids = append([]types.ID{id}, o.ids...)
-	return id
-}
-
 type validSignatures struct {
 	DataLock                    sync.Mutex
 	ChannelStateLock            sync.Mutex
@@ -135,14 +109,17 @@ func (d ChannelStateSignature) Equal(other ChannelStateSignature) bool {
 // Signatures are generated randomly and all valid signature t-tuples are collected globally
 // in ValidSignatures. This means that GenericRemote verifies a signature as valid, iff it has been signed by ANY
 // Generic remote before.
-// GenericRemote uses the ChannelIDOracle to come up with a random channel id that is then fixed to that
-// ChannelParameters.
+// GenericRemote calculates channel ids like the wallet server (see wire.CalculateChannelID).
 type GenericRemote struct {
 	AvailableAddresses []address.Address
 	rng                *rand.Rand
 	// The lock is needed because the same GenericRemote instance might be required to generate random signatures
 	// using the same rand.Rand instance in parallel and rand.Rand is not concurrently safe.
 	mutex sync.Mutex
+	// lockedKeys holds the public keys that were locked using wallet.EndpointLockKey.
+	lockedKeys map[[address.PubKeyLength]byte]bool
+	// walletIDs holds the cardano wallet ids of the accounts created using wallet.EndpointCreateAccount.
+	walletIDs map[[address.PubKeyLength]byte]string
 
 	callEndpoint func(string, interface{}, interface{}) error
 }
@@ -153,6 +130,8 @@ func NewGenericRemote(availableAddresses []address.Address, rng *rand.Rand) *Gen
 	g := GenericRemote{
 		AvailableAddresses: availableAddresses,
 		rng:                rng,
+		lockedKeys:         make(map[[address.PubKeyLength]byte]bool),
+		walletIDs:          make(map[[address.PubKeyLength]byte]string),
 	}
 	g.callEndpoint = makeGenericCallEndpointDefault(&g)
 	return &g
@@ -219,6 +198,45 @@ func makeGenericCallEndpointDefault(g *GenericRemote) func(string, interface{},
 				return fmt.Errorf("unable to cast response to VerificationResponse")
 			}
 			return g.endpointVerifyChannelStateSignature(request, response)
+		case wallet.EndpointVerifyChannelStateSignatures:
+			request, ok := req.(wire.BatchVerificationRequest)
+			if !ok {
+				return fmt.Errorf("unable to cast request to BatchVerificationRequest")
+			}
+			response, ok := resp.(*wire.BatchVerificationResponse)
+			if !ok {
+				return fmt.Errorf("unable to cast response to BatchVerificationResponse")
+			}
+			return g.endpointVerifyChannelStateSignatures(request, response)
+		case wallet.EndpointLockKey, wallet.EndpointUnlockKey:
+			request, ok := req.(wire.KeyLockRequest)
+			if !ok {
+				return fmt.Errorf("unable to cast request to KeyLockRequest")
+			}
+			response, ok := resp.(*wire.KeyLockResponse)
+			if !ok {
+				return fmt.Errorf("unable to cast response to KeyLockResponse")
+			}
+			return g.endpointSetKeyLock(request, response, endpoint == wallet.EndpointLockKey)
+		case wallet.EndpointListAccounts:
+			if _, ok := req.(wire.ListAccountsRequest); !ok {
+				return fmt.Errorf("unable to cast request to ListAccountsRequest")
+			}
+			response, ok := resp.(*wire.ListAccountsResponse)
+			if !ok {
+				return fmt.Errorf("unable to cast response to ListAccountsResponse")
+			}
+			return g.endpointListAccounts(response)
+		case wallet.EndpointCreateAccount:
+			request, ok := req.(wire.CreateAccountRequest)
+			if !ok {
+				return fmt.Errorf("unable to cast request to CreateAccountRequest")
+			}
+			response, ok := resp.(*wire.CreateAccountResponse)
+			if !ok {
+				return fmt.Errorf("unable to cast response to CreateAccountResponse")
+			}
+			return g.endpointCreateAccount(request, response)
 		case wallet.EndpointCalculateChannelID:
 			request, ok := req.(wire.ChannelParameters)
 			if !ok {
@@ -236,6 +254,8 @@ func makeGenericCallEndpointDefault(g *GenericRemote) func(string, interface{},
 }
 
 func (g *GenericRemote) isAvailable(address address.Address) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
 	for _, a := range g.AvailableAddresses {
 		if a.GetPubKey() == address.GetPubKey() {
 			return true
@@ -244,6 +264,62 @@ func (g *GenericRemote) isAvailable(address address.Address) bool {
 	return false
 }
 
+// AddAddress makes the key of the given address available in this GenericRemote.
+func (g *GenericRemote) AddAddress(address address.Address) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.AvailableAddresses = append(g.AvailableAddresses, address)
+}
+
+// IsLocked returns true, iff the key of the given address was locked in this GenericRemote.
+func (g *GenericRemote) IsLocked(address address.Address) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.lockedKeys[address.GetPubKey()]
+}
+
+func (g *GenericRemote) endpointSetKeyLock(request wire.KeyLockRequest, response *wire.KeyLockResponse, lock bool) error {
+	reqAddr, err := request.Decode()
+	if err != nil {
+		return fmt.Errorf("unable to decode PubKey from request")
+	}
+	*response = g.isAvailable(reqAddr)
+	if !*response {
+		return nil
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if lock {
+		g.lockedKeys[reqAddr.GetPubKey()] = true
+	} else {
+		delete(g.lockedKeys, reqAddr.GetPubKey())
+	}
+	return nil
+}
+
+func (g *GenericRemote) endpointListAccounts(response *wire.ListAccountsResponse) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	*response = make(wire.ListAccountsResponse, len(g.AvailableAddresses))
+	for i, addr := range g.AvailableAddresses {
+		(*response)[i] = wire.MakeAccountInfo(addr, g.walletIDs[addr.GetPubKey()])
+	}
+	return nil
+}
+
+func (g *GenericRemote) endpointCreateAccount(request wire.CreateAccountRequest, response *wire.CreateAccountResponse) error {
+	if request.Scheme != "" && request.Scheme != address.Ed25519.String() {
+		return fmt.Errorf("unsupported signature scheme: %s", request.Scheme)
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	addr := MakeRandomAddress(g.rng)
+	g.AvailableAddresses = append(g.AvailableAddresses, addr)
+	g.walletIDs[addr.GetPubKey()] = request.WalletID
+	*response = wire.MakeAccountInfo(addr, request.WalletID)
+	return nil
+}
+
 func (g *GenericRemote) endpointSignData(request wire.SigningRequest, response *wire.SigningResponse) error {
 	reqAddr, err := request.PubKey.Decode()
 	if err != nil {
@@ -252,6 +328,9 @@ func (g *GenericRemote) endpointSignData(request wire.SigningRequest, response *
 	if !g.isAvailable(reqAddr) {
 		return fmt.Errorf("account is not available in wallet")
 	}
+	if g.IsLocked(reqAddr) {
+		return fmt.Errorf("key is locked in wallet")
+	}
 	msg, err := hex.DecodeString(request.Message)
 	if err != nil {
 		return fmt.Errorf("unable to decode message")
@@ -303,6 +382,9 @@ func (g *GenericRemote) endpointSignChannelState(request wire.ChannelStateSignin
 	if !g.isAvailable(reqAddr) {
 		return fmt.Errorf("account is not available in wallet")
 	}
+	if g.IsLocked(reqAddr) {
+		return fmt.Errorf("key is locked in wallet")
+	}
 	state := request.ChannelState.Decode()
 	g.mutex.Lock()
 	sig := MakeRandomSignature(g.rng)
@@ -322,22 +404,34 @@ func (g *GenericRemote) endpointVerifyChannelStateSignature(request wire.Channel
 		return fmt.Errorf("unable to decode signature from request")
 	}
 	state := request.ChannelState.Decode()
+	// Like the wallet server, accept signatures on the canonical message of the channel state.
 	*response = VerifyChannelStateSig(ChannelStateSignature{
 		Address:      reqAddr,
 		Signature:    sig,
 		ChannelState: state,
+	}) || VerifyDataSig(DataSignature{
+		Address:   reqAddr,
+		Signature: sig,
+		Message:   wire.MakeChannelStateMessage(state),
 	})
 	return nil
 }
 
+func (g *GenericRemote) endpointVerifyChannelStateSignatures(request wire.BatchVerificationRequest, response *wire.BatchVerificationResponse) error {
+	*response = make(wire.BatchVerificationResponse, len(request.Requests))
+	for i, r := range request.Requests {
+		if err := g.endpointVerifyChannelStateSignature(r, &(*response)[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (g *GenericRemote) endpointCalculateChannelID(request wire.ChannelParameters, response *wire.ChannelID) error {
 	params, err := request.Decode()
 	if err != nil {
 		return fmt.Errorf("unable to decode the parameters")
 	}
-	g.mutex.Lock()
-	id := ChannelIDOracle.GetChannelID(params, g.rng)
-	g.mutex.Unlock()
-	*response = id
+	*response = wire.ChannelID(wire.CalculateChannelID(params))
 	return nil
 }
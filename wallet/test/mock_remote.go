@@ -160,6 +160,26 @@ func makeCallEndpointDefault(r *MockRemote) func(string, interface{}, interface{
 				return fmt.Errorf("unable to cast resp to VerificationResponse")
 			}
 			return callVerifyChannelState(r, request, response)
+		case wallet.EndpointLockKey, wallet.EndpointUnlockKey:
+			request, ok := req.(wire.KeyLockRequest)
+			if !ok {
+				return fmt.Errorf("unable to cast request to KeyLockRequest")
+			}
+			response, ok := resp.(*wire.KeyLockResponse)
+			if !ok {
+				return fmt.Errorf("unable to cast resp to KeyLockResponse")
+			}
+			return callSetKeyLock(r, request, response)
+		case wallet.EndpointListAccounts:
+			if _, ok := req.(wire.ListAccountsRequest); !ok {
+				return fmt.Errorf("unable to cast request to ListAccountsRequest")
+			}
+			response, ok := resp.(*wire.ListAccountsResponse)
+			if !ok {
+				return fmt.Errorf("unable to cast resp to ListAccountsResponse")
+			}
+			*response = wire.ListAccountsResponse{wire.MakeAccountInfo(r.MockAddress, "")}
+			return nil
 		default:
 			return fmt.Errorf("unable to recognize endpoint: %s", endpoint)
 
@@ -270,3 +290,12 @@ func callKeyAvailable(r *MockRemote, request wire.KeyAvailabilityRequest, respon
 	*response = reqAddr.GetPubKey() == r.MockPubKeyBytes
 	return nil
 }
+
+func callSetKeyLock(r *MockRemote, request wire.KeyLockRequest, response *wire.KeyLockResponse) error {
+	reqAddr, err := request.Decode()
+	if err != nil {
+		return fmt.Errorf("unable to decode address from request: %w", err)
+	}
+	*response = reqAddr.GetPubKey() == r.MockPubKeyBytes
+	return nil
+}
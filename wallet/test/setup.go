@@ -0,0 +1,103 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"math/rand"
+	gpwallet "perun.network/go-perun/wallet"
+	gptest "perun.network/go-perun/wallet/test"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+)
+
+// NewSetup returns a setup for the generic go-perun wallet tests (e.g. gptest.TestAccountWithWalletAndBackend) of this
+// backend using the given remote, which must hold the key of addressInWallet.
+func NewSetup(rng *rand.Rand, remote wallet.Remote, addressInWallet address.Address) *gptest.Setup {
+	b := wallet.MakeRemoteBackend(remote)
+	marshalledAddress, err := MakeRandomAddress(rng).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return &gptest.Setup{
+		Backend:           b,
+		Wallet:            NewRemoteWallet(remote),
+		AddressInWallet:   &addressInWallet,
+		ZeroAddress:       b.NewAddress(),
+		DataToSign:        GetRandomByteSlice(1, 0x100, rng),
+		AddressMarshalled: marshalledAddress,
+	}
+}
+
+// Randomizer is the go-perun wallet test randomizer of this backend (see gptest.SetRandomizer). All of its wallets
+// hold their accounts in the same GenericRemote. It allows running go-perun test suites, which create random
+// addresses and accounts, against this backend.
+type Randomizer struct {
+	remote *GenericRemote
+	wallet *RandomWallet
+}
+
+// RandomWallet is a go-perun test wallet, whose random accounts are added to a GenericRemote.
+type RandomWallet struct {
+	*wallet.RemoteWallet
+	remote *GenericRemote
+}
+
+// NewRandomizer returns a new Randomizer whose wallets hold their accounts in the given GenericRemote.
+func NewRandomizer(remote *GenericRemote) *Randomizer {
+	return &Randomizer{
+		remote: remote,
+		wallet: NewRandomWallet(remote),
+	}
+}
+
+// NewRandomWallet returns a new RandomWallet holding its accounts in the given GenericRemote.
+func NewRandomWallet(remote *GenericRemote) *RandomWallet {
+	return &RandomWallet{
+		RemoteWallet: NewRemoteWallet(remote),
+		remote:       remote,
+	}
+}
+
+// NewRandomAddress returns a random address.
+func (r *Randomizer) NewRandomAddress(rng *rand.Rand) gpwallet.Address {
+	addr := MakeRandomAddress(rng)
+	return &addr
+}
+
+// RandomWallet returns the wallet of the Randomizer.
+func (r *Randomizer) RandomWallet() gptest.Wallet {
+	return r.wallet
+}
+
+// NewWallet returns a new wallet, which did not create any accounts yet.
+func (r *Randomizer) NewWallet() gptest.Wallet {
+	return NewRandomWallet(r.remote)
+}
+
+// NewRandomAccount adds a random address to the GenericRemote of the wallet and returns the unlocked account of it.
+func (w *RandomWallet) NewRandomAccount(rng *rand.Rand) gpwallet.Account {
+	addr := MakeRandomAddress(rng)
+	w.remote.AddAddress(addr)
+	acc, err := w.Unlock(&addr)
+	if err != nil {
+		panic(err)
+	}
+	return acc
+}
+
+var (
+	_ gptest.Randomizer = (*Randomizer)(nil)
+	_ gptest.Wallet     = (*RandomWallet)(nil)
+)
@@ -0,0 +1,34 @@
+// Copyright 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"os"
+	"testing"
+)
+
+// WalletServerEnv is the environment variable holding the url of the perun-cardano-wallet server against which the
+// local Plutus Data serialization is checked.
+const WalletServerEnv = "PERUN_CARDANO_WALLET_URL"
+
+// WalletServerURL returns the url of the perun-cardano-wallet server given by WalletServerEnv. The wallet server
+// serializes with the contract's `serialiseData . toBuiltinData`. It skips the test, if WalletServerEnv is not set.
+func WalletServerURL(t *testing.T) string {
+	url, ok := os.LookupEnv(WalletServerEnv)
+	if !ok {
+		t.Skipf("%s is not set", WalletServerEnv)
+	}
+	return url
+}
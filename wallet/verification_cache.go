@@ -0,0 +1,118 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"io"
+	"perun.network/go-perun/wallet"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wire"
+	"sync"
+)
+
+// DefaultVerificationCacheSize is the number of verification results cached by a RemoteBackend created with
+// MakeRemoteBackend.
+const DefaultVerificationCacheSize = 4096
+
+// VerificationCache is a bounded cache of the results of channel state signature verifications. If it is full, the
+// least recently used result is evicted. It is safe for concurrent use.
+type VerificationCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[verificationKey]*list.Element
+}
+
+// verificationKey identifies a verification by the hash of the channel state, the signature and the address.
+type verificationKey [sha256.Size]byte
+
+type verificationEntry struct {
+	key   verificationKey
+	valid bool
+}
+
+// NewVerificationCache returns a new VerificationCache holding at most size results.
+func NewVerificationCache(size int) *VerificationCache {
+	return &VerificationCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[verificationKey]*list.Element),
+	}
+}
+
+// makeVerificationKey returns the key of the verification of the given signature on the given state for the given
+// address.
+func makeVerificationKey(state types.ChannelState, sig wallet.Sig, addr address.Address) (verificationKey, error) {
+	encodedAddr, err := addr.MarshalBinary()
+	if err != nil {
+		return verificationKey{}, err
+	}
+	h := sha256.New()
+	stateHash := sha256.Sum256(wire.MakeChannelStateMessage(state))
+	h.Write(stateHash[:])
+	writeLengthPrefixed(h, sig)
+	writeLengthPrefixed(h, encodedAddr)
+	var key verificationKey
+	copy(key[:], h.Sum(nil))
+	return key, nil
+}
+
+func writeLengthPrefixed(w io.Writer, data []byte) {
+	l := uint32(len(data))
+	_, _ = w.Write([]byte{byte(l >> 24), byte(l >> 16), byte(l >> 8), byte(l)})
+	_, _ = w.Write(data)
+}
+
+// get returns the cached result of the verification with the given key and true, iff it is cached.
+func (c *VerificationCache) get(key verificationKey) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return false, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*verificationEntry).valid, true
+}
+
+// add caches the result of the verification with the given key.
+func (c *VerificationCache) add(key verificationKey, valid bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*verificationEntry).valid = valid
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&verificationEntry{key: key, valid: valid})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*verificationEntry).key)
+	}
+}
+
+// Len returns the number of cached results.
+func (c *VerificationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
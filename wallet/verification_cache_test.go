@@ -0,0 +1,92 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet_test
+
+import (
+	"github.com/stretchr/testify/require"
+	ctest "perun.network/perun-cardano-backend/channel/test"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+	"sync"
+	"testing"
+)
+
+// countingRemote counts the calls to every endpoint of the wrapped Remote.
+type countingRemote struct {
+	wallet.Remote
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newCountingRemote(remote wallet.Remote) *countingRemote {
+	return &countingRemote{Remote: remote, calls: make(map[string]int)}
+}
+
+func (r *countingRemote) CallEndpoint(endpoint string, body interface{}, result interface{}) error {
+	r.mu.Lock()
+	r.calls[endpoint]++
+	r.mu.Unlock()
+	return r.Remote.CallEndpoint(endpoint, body, result)
+}
+
+func (r *countingRemote) count(endpoint string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[endpoint]
+}
+
+func TestVerificationCache(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addresses := []address.Address{test.MakeRandomAddress(rng), test.MakeRandomAddress(rng)}
+	remote := newCountingRemote(test.NewGenericRemote(addresses, rng))
+	cache := wallet.NewVerificationCache(1)
+	backend := wallet.MakeRemoteBackendWithCache(remote, cache)
+	state := ctest.MakeRandomChannelState(rng)
+	sigs := make([][]byte, len(addresses))
+	for i, addr := range addresses {
+		sig, err := test.MakeRemoteAccount(addr, remote).SignChannelState(state)
+		require.NoError(t, err)
+		sigs[i] = sig
+	}
+
+	verify := func(i int) {
+		valid, err := backend.VerifyChannelStateSignature(state, sigs[i], &addresses[i])
+		require.NoError(t, err)
+		require.True(t, valid)
+	}
+	verify(0)
+	verify(0)
+	require.Equal(t, 1, remote.count(wallet.EndpointVerifyChannelStateSignature), "cached result was not used")
+	require.Equal(t, 1, cache.Len())
+
+	verify(1)
+	verify(0)
+	require.Equal(t, 3, remote.count(wallet.EndpointVerifyChannelStateSignature), "least recently used result was not evicted")
+	require.Equal(t, 1, cache.Len())
+
+	valid, err := backend.VerifyChannelStateSignature(state, sigs[1], &addresses[0])
+	require.NoError(t, err)
+	require.False(t, valid, "cached result was used for another address")
+
+	uncached := wallet.MakeRemoteBackendWithCache(remote, nil)
+	for i := 0; i < 2; i++ {
+		valid, err = uncached.VerifyChannelStateSignature(state, sigs[0], &addresses[0])
+		require.NoError(t, err)
+		require.True(t, valid)
+	}
+	require.Equal(t, 6, remote.count(wallet.EndpointVerifyChannelStateSignature))
+}
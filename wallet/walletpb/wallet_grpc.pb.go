@@ -0,0 +1,534 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: wallet.proto
+
+package walletpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// WalletClient is the client API for Wallet service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WalletClient interface {
+	Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
+	SignChannelState(ctx context.Context, in *SignChannelStateRequest, opts ...grpc.CallOption) (*SignResponse, error)
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	VerifyChannelState(ctx context.Context, in *VerifyChannelStateRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	VerifyChannelStates(ctx context.Context, in *VerifyChannelStatesRequest, opts ...grpc.CallOption) (*VerifyChannelStatesResponse, error)
+	KeyAvailable(ctx context.Context, in *KeyAvailableRequest, opts ...grpc.CallOption) (*KeyAvailableResponse, error)
+	LockKey(ctx context.Context, in *KeyLockRequest, opts ...grpc.CallOption) (*KeyLockResponse, error)
+	UnlockKey(ctx context.Context, in *KeyLockRequest, opts ...grpc.CallOption) (*KeyLockResponse, error)
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+	CalculateChannelID(ctx context.Context, in *ChannelParameters, opts ...grpc.CallOption) (*CalculateChannelIDResponse, error)
+	Stream(ctx context.Context, opts ...grpc.CallOption) (Wallet_StreamClient, error)
+}
+
+type walletClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWalletClient(cc grpc.ClientConnInterface) WalletClient {
+	return &walletClient{cc}
+}
+
+func (c *walletClient) Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/Sign", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) SignChannelState(ctx context.Context, in *SignChannelStateRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/SignChannelState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/Verify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) VerifyChannelState(ctx context.Context, in *VerifyChannelStateRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/VerifyChannelState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) VerifyChannelStates(ctx context.Context, in *VerifyChannelStatesRequest, opts ...grpc.CallOption) (*VerifyChannelStatesResponse, error) {
+	out := new(VerifyChannelStatesResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/VerifyChannelStates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) KeyAvailable(ctx context.Context, in *KeyAvailableRequest, opts ...grpc.CallOption) (*KeyAvailableResponse, error) {
+	out := new(KeyAvailableResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/KeyAvailable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) LockKey(ctx context.Context, in *KeyLockRequest, opts ...grpc.CallOption) (*KeyLockResponse, error) {
+	out := new(KeyLockResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/LockKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) UnlockKey(ctx context.Context, in *KeyLockRequest, opts ...grpc.CallOption) (*KeyLockResponse, error) {
+	out := new(KeyLockResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/UnlockKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/ListAccounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/CreateAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) CalculateChannelID(ctx context.Context, in *ChannelParameters, opts ...grpc.CallOption) (*CalculateChannelIDResponse, error) {
+	out := new(CalculateChannelIDResponse)
+	err := c.cc.Invoke(ctx, "/perun.cardano.wallet.v1.Wallet/CalculateChannelID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletClient) Stream(ctx context.Context, opts ...grpc.CallOption) (Wallet_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Wallet_ServiceDesc.Streams[0], "/perun.cardano.wallet.v1.Wallet/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletStreamClient{stream}
+	return x, nil
+}
+
+type Wallet_StreamClient interface {
+	Send(*StreamRequest) error
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type walletStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletStreamClient) Send(m *StreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *walletStreamClient) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WalletServer is the server API for Wallet service.
+// All implementations must embed UnimplementedWalletServer
+// for forward compatibility
+type WalletServer interface {
+	Sign(context.Context, *SignRequest) (*SignResponse, error)
+	SignChannelState(context.Context, *SignChannelStateRequest) (*SignResponse, error)
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	VerifyChannelState(context.Context, *VerifyChannelStateRequest) (*VerifyResponse, error)
+	VerifyChannelStates(context.Context, *VerifyChannelStatesRequest) (*VerifyChannelStatesResponse, error)
+	KeyAvailable(context.Context, *KeyAvailableRequest) (*KeyAvailableResponse, error)
+	LockKey(context.Context, *KeyLockRequest) (*KeyLockResponse, error)
+	UnlockKey(context.Context, *KeyLockRequest) (*KeyLockResponse, error)
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	CreateAccount(context.Context, *CreateAccountRequest) (*AccountInfo, error)
+	CalculateChannelID(context.Context, *ChannelParameters) (*CalculateChannelIDResponse, error)
+	Stream(Wallet_StreamServer) error
+	mustEmbedUnimplementedWalletServer()
+}
+
+// UnimplementedWalletServer must be embedded to have forward compatible implementations.
+type UnimplementedWalletServer struct {
+}
+
+func (UnimplementedWalletServer) Sign(context.Context, *SignRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sign not implemented")
+}
+func (UnimplementedWalletServer) SignChannelState(context.Context, *SignChannelStateRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignChannelState not implemented")
+}
+func (UnimplementedWalletServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedWalletServer) VerifyChannelState(context.Context, *VerifyChannelStateRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyChannelState not implemented")
+}
+func (UnimplementedWalletServer) VerifyChannelStates(context.Context, *VerifyChannelStatesRequest) (*VerifyChannelStatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyChannelStates not implemented")
+}
+func (UnimplementedWalletServer) KeyAvailable(context.Context, *KeyAvailableRequest) (*KeyAvailableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KeyAvailable not implemented")
+}
+func (UnimplementedWalletServer) LockKey(context.Context, *KeyLockRequest) (*KeyLockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockKey not implemented")
+}
+func (UnimplementedWalletServer) UnlockKey(context.Context, *KeyLockRequest) (*KeyLockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockKey not implemented")
+}
+func (UnimplementedWalletServer) ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedWalletServer) CreateAccount(context.Context, *CreateAccountRequest) (*AccountInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedWalletServer) CalculateChannelID(context.Context, *ChannelParameters) (*CalculateChannelIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateChannelID not implemented")
+}
+func (UnimplementedWalletServer) Stream(Wallet_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+func (UnimplementedWalletServer) mustEmbedUnimplementedWalletServer() {}
+
+// UnsafeWalletServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WalletServer will
+// result in compilation errors.
+type UnsafeWalletServer interface {
+	mustEmbedUnimplementedWalletServer()
+}
+
+func RegisterWalletServer(s grpc.ServiceRegistrar, srv WalletServer) {
+	s.RegisterService(&Wallet_ServiceDesc, srv)
+}
+
+func _Wallet_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).Sign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/Sign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).Sign(ctx, req.(*SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_SignChannelState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignChannelStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).SignChannelState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/SignChannelState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).SignChannelState(ctx, req.(*SignChannelStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/Verify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_VerifyChannelState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyChannelStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).VerifyChannelState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/VerifyChannelState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).VerifyChannelState(ctx, req.(*VerifyChannelStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_VerifyChannelStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyChannelStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).VerifyChannelStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/VerifyChannelStates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).VerifyChannelStates(ctx, req.(*VerifyChannelStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_KeyAvailable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyAvailableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).KeyAvailable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/KeyAvailable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).KeyAvailable(ctx, req.(*KeyAvailableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_LockKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).LockKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/LockKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).LockKey(ctx, req.(*KeyLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_UnlockKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).UnlockKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/UnlockKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).UnlockKey(ctx, req.(*KeyLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/ListAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/CreateAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_CalculateChannelID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelParameters)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).CalculateChannelID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/perun.cardano.wallet.v1.Wallet/CalculateChannelID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).CalculateChannelID(ctx, req.(*ChannelParameters))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wallet_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WalletServer).Stream(&walletStreamServer{stream})
+}
+
+type Wallet_StreamServer interface {
+	Send(*StreamResponse) error
+	Recv() (*StreamRequest, error)
+	grpc.ServerStream
+}
+
+type walletStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletStreamServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *walletStreamServer) Recv() (*StreamRequest, error) {
+	m := new(StreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Wallet_ServiceDesc is the grpc.ServiceDesc for Wallet service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Wallet_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "perun.cardano.wallet.v1.Wallet",
+	HandlerType: (*WalletServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sign",
+			Handler:    _Wallet_Sign_Handler,
+		},
+		{
+			MethodName: "SignChannelState",
+			Handler:    _Wallet_SignChannelState_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _Wallet_Verify_Handler,
+		},
+		{
+			MethodName: "VerifyChannelState",
+			Handler:    _Wallet_VerifyChannelState_Handler,
+		},
+		{
+			MethodName: "VerifyChannelStates",
+			Handler:    _Wallet_VerifyChannelStates_Handler,
+		},
+		{
+			MethodName: "KeyAvailable",
+			Handler:    _Wallet_KeyAvailable_Handler,
+		},
+		{
+			MethodName: "LockKey",
+			Handler:    _Wallet_LockKey_Handler,
+		},
+		{
+			MethodName: "UnlockKey",
+			Handler:    _Wallet_UnlockKey_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _Wallet_ListAccounts_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _Wallet_CreateAccount_Handler,
+		},
+		{
+			MethodName: "CalculateChannelID",
+			Handler:    _Wallet_CalculateChannelID_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _Wallet_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "wallet.proto",
+}
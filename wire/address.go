@@ -0,0 +1,132 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"encoding/hex"
+	"fmt"
+	"perun.network/perun-cardano-backend/wallet/address"
+)
+
+const (
+	PubKeyCredentialTag = "PubKeyCredential"
+	ScriptCredentialTag = "ScriptCredential"
+	StakingHashTag      = "StakingHash"
+)
+
+// Address reflects the Haskell type `Plutus.V2.Ledger.Api.Address` in respect to its json encoding.
+type Address struct {
+	Credential        Credential         `json:"addressCredential"`
+	StakingCredential *StakingCredential `json:"addressStakingCredential"`
+}
+
+// Credential reflects the Haskell type `Plutus.V2.Ledger.Api.Credential` in respect to its json encoding.
+type Credential struct {
+	Contents CredentialHash `json:"contents"`
+	Tag      string         `json:"tag"`
+}
+
+// CredentialHash holds either a public key hash (for PubKeyCredentialTag) or a validator hash
+// (for ScriptCredentialTag).
+type CredentialHash struct {
+	PubKeyHash    string `json:"getPubKeyHash,omitempty"`
+	ValidatorHash string `json:"getValidatorHash,omitempty"`
+}
+
+// StakingCredential reflects the Haskell type `Plutus.V2.Ledger.Api.StakingCredential` in respect to its json
+// encoding. Only the `StakingHash` constructor is supported.
+type StakingCredential struct {
+	Contents Credential `json:"contents"`
+	Tag      string     `json:"tag"`
+}
+
+// MakeCredential returns the json serializable representation of the given credential.
+func MakeCredential(c address.Credential) Credential {
+	h := hex.EncodeToString(c.Hash[:])
+	if c.Type == address.ScriptHashCredential {
+		return Credential{Contents: CredentialHash{ValidatorHash: h}, Tag: ScriptCredentialTag}
+	}
+	return Credential{Contents: CredentialHash{PubKeyHash: h}, Tag: PubKeyCredentialTag}
+}
+
+// MakeAddress returns the payout address of the given address.Address. The staking credential is null, iff the
+// address has no stake credential.
+func MakeAddress(addr address.Address) Address {
+	a := Address{
+		Credential: MakeCredential(addr.GetPaymentCredential()),
+	}
+	if stake, ok := addr.GetStakeCredential(); ok {
+		a.StakingCredential = &StakingCredential{
+			Contents: MakeCredential(stake),
+			Tag:      StakingHashTag,
+		}
+	}
+	return a
+}
+
+// hash returns the hex encoded hash of this Credential.
+func (c Credential) hash() string {
+	if c.Tag == ScriptCredentialTag {
+		return c.Contents.ValidatorHash
+	}
+	return c.Contents.PubKeyHash
+}
+
+// Decode returns the address.Credential represented by this Credential.
+func (c Credential) Decode() (address.Credential, error) {
+	var t address.CredentialType
+	var h string
+	switch c.Tag {
+	case PubKeyCredentialTag:
+		t, h = address.KeyHashCredential, c.Contents.PubKeyHash
+	case ScriptCredentialTag:
+		t, h = address.ScriptHashCredential, c.Contents.ValidatorHash
+	default:
+		return address.Credential{}, fmt.Errorf("invalid credential tag: %s", c.Tag)
+	}
+	hash, err := hex.DecodeString(h)
+	if err != nil {
+		return address.Credential{}, fmt.Errorf("unable to decode credential hash: %w", err)
+	}
+	if len(hash) != address.CredentialHashLength {
+		return address.Credential{}, fmt.Errorf("credential hash has wrong length: %d", len(hash))
+	}
+	cred := address.Credential{Type: t}
+	copy(cred.Hash[:], hash)
+	return cred, nil
+}
+
+// DecodeInto sets the payment public key hash and stake credential of the given address.Address to the ones of this
+// Address.
+func (a Address) DecodeInto(addr *address.Address) error {
+	payment, err := a.Credential.Decode()
+	if err != nil {
+		return err
+	}
+	addr.SetPaymentCredential(payment)
+	if a.StakingCredential == nil {
+		addr.RemoveStakeCredential()
+		return nil
+	}
+	if a.StakingCredential.Tag != StakingHashTag {
+		return fmt.Errorf("unsupported staking credential tag: %s", a.StakingCredential.Tag)
+	}
+	stake, err := a.StakingCredential.Contents.Decode()
+	if err != nil {
+		return err
+	}
+	addr.SetStakeCredential(stake)
+	return nil
+}
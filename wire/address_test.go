@@ -0,0 +1,69 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+)
+
+func TestAddress(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		expected := test.MakeRandomAddress(rng)
+		if rng.Intn(2) == 0 {
+			expected = test.MakeRandomAddressWithStakeCredential(rng)
+		}
+		data, err := json.Marshal(wire.MakeAddress(expected))
+		require.NoError(t, err, "unable to marshal address")
+		var decoded wire.Address
+		require.NoError(t, json.Unmarshal(data, &decoded), "unable to unmarshal address")
+		actual := address.MakeAddressFromPubKeyByteArray(expected.GetPubKey())
+		require.NoError(t, decoded.DecodeInto(&actual), "unable to decode address")
+		require.Equal(t, expected, actual, "decoded address is not as expected")
+	}
+}
+
+func TestAddress_ScriptCredential(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	expected := test.MakeRandomMultiSigAddress(rng, 5)
+	encoded := wire.MakeAddress(expected)
+	require.Equal(t, wire.ScriptCredentialTag, encoded.Credential.Tag, "payment credential should be a script credential")
+	actual := test.MakeRandomAddress(rng)
+	require.NoError(t, encoded.DecodeInto(&actual), "unable to decode address")
+	require.Equal(t, expected.GetPaymentCredential(), actual.GetPaymentCredential(), "decoded payment credential is not as expected")
+}
+
+func TestAddress_Decode_Invalid(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	addr := test.MakeRandomAddressWithStakeCredential(rng)
+
+	invalidTag := wire.MakeAddress(addr)
+	invalidTag.Credential.Tag = "InvalidCredential"
+	require.Error(t, invalidTag.DecodeInto(&addr), "failed to error on invalid credential tag")
+
+	pointer := wire.MakeAddress(addr)
+	pointer.StakingCredential.Tag = "StakingPtr"
+	require.Error(t, pointer.DecodeInto(&addr), "failed to error on staking pointer")
+
+	invalidHash := wire.MakeAddress(addr)
+	invalidHash.Credential.Contents.PubKeyHash = "abcd"
+	require.Error(t, invalidHash.DecodeInto(&addr), "failed to error on invalid hash length")
+}
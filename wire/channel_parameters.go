@@ -15,6 +15,7 @@
 package wire
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -24,11 +25,29 @@ import (
 )
 
 // ChannelParameters reflects the Haskell type `Channel` of the Channel Smart Contract in respect to its json encoding.
+//
+// PaymentAddresses and MultiSigPolicies extend the parameters of the deployed contract, which pays out to the enterprise
+// addresses of PaymentPubKeyHashes and verifies single key signatures only. To keep the encoding compatible with it,
+// PaymentAddresses is only set, iff a party carries a stake credential or a script payment credential, and
+// MultiSigPolicies only, iff a party is a multi-signature party. Channels with such parties require a contract that
+// supports the extension.
 type ChannelParameters struct {
 	Nonce               string              `json:"pNonce"`
 	PaymentPubKeyHashes []PaymentPubKeyHash `json:"pPaymentPKs"`
 	SigningPubKeys      []PaymentPubKey     `json:"pSigningPKs"`
 	TimeLock            int64               `json:"pTimeLock"`
+	// PaymentAddresses holds the full payout address of every party. The payment credentials match
+	// PaymentPubKeyHashes.
+	PaymentAddresses []Address `json:"pPaymentAddresses,omitempty"`
+	// MultiSigPolicies holds the multi-signature policy for every party, which is nil for single key parties.
+	MultiSigPolicies []*MultiSigPolicy `json:"pMultiSigPolicies,omitempty"`
+}
+
+// MultiSigPolicy is the json serializable signing policy of a multi-signature party. A signature of that party is
+// valid, iff at least Threshold of the SigningPubKeys signed.
+type MultiSigPolicy struct {
+	Threshold      int             `json:"msThreshold"`
+	SigningPubKeys []PaymentPubKey `json:"msSigningPKs"`
 }
 
 type PaymentPubKey struct {
@@ -50,19 +69,85 @@ func MakePaymentPubKey(address address.Address) PaymentPubKey {
 }
 
 func MakeChannelParameters(parameters types.ChannelParameters) ChannelParameters {
-	pubKeyHashes := make([]PaymentPubKeyHash, len(parameters.Parties))
+	paymentAddresses := make([]Address, len(parameters.Parties))
 	signingPubKeys := make([]PaymentPubKey, len(parameters.Parties))
+	var multiSigPolicies []*MultiSigPolicy
 	for i, addr := range parameters.Parties {
-		pubKeyHashes[i] = MakePaymentPubKeyHash(addr)
+		paymentAddresses[i] = MakeAddress(addr)
 		signingPubKeys[i] = MakePaymentPubKey(addr)
+		if addr.IsMultiSig() {
+			if multiSigPolicies == nil {
+				multiSigPolicies = make([]*MultiSigPolicy, len(parameters.Parties))
+			}
+			multiSigPolicies[i] = MakeMultiSigPolicy(addr)
+		}
+	}
+	cp := ChannelParameters{
+		Nonce:            fmt.Sprintf("%x", parameters.Nonce),
+		SigningPubKeys:   signingPubKeys,
+		MultiSigPolicies: multiSigPolicies,
+		TimeLock:         parameters.Timeout.Milliseconds(),
+	}
+	cp.SetPaymentAddresses(paymentAddresses)
+	return cp
+}
+
+// SetPaymentAddresses sets PaymentPubKeyHashes to the payment credentials of the given payout addresses. The addresses
+// themselves are only kept in PaymentAddresses, iff one of them carries a stake credential or a script payment
+// credential.
+func (cp *ChannelParameters) SetPaymentAddresses(addresses []Address) {
+	cp.PaymentPubKeyHashes = make([]PaymentPubKeyHash, len(addresses))
+	cp.PaymentAddresses = nil
+	for i, addr := range addresses {
+		cp.PaymentPubKeyHashes[i] = PaymentPubKeyHash{PubKeyHash: PubKeyHash{Hex: addr.Credential.hash()}}
+		if addr.StakingCredential != nil || addr.Credential.Tag != PubKeyCredentialTag {
+			cp.PaymentAddresses = addresses
+		}
+	}
+}
+
+// GetPaymentAddresses returns the payout address of every party. These are PaymentAddresses, if set, and the
+// enterprise addresses of PaymentPubKeyHashes otherwise.
+func (cp ChannelParameters) GetPaymentAddresses() []Address {
+	if cp.PaymentAddresses != nil {
+		return cp.PaymentAddresses
+	}
+	addresses := make([]Address, len(cp.PaymentPubKeyHashes))
+	for i, pkh := range cp.PaymentPubKeyHashes {
+		addresses[i] = Address{
+			Credential: Credential{
+				Contents: CredentialHash{PubKeyHash: pkh.PubKeyHash.Hex},
+				Tag:      PubKeyCredentialTag,
+			},
+		}
 	}
-	return ChannelParameters{
-		Nonce:               fmt.Sprintf("%x", parameters.Nonce),
-		PaymentPubKeyHashes: pubKeyHashes,
-		SigningPubKeys:      signingPubKeys,
-		TimeLock:            parameters.Timeout.Milliseconds(),
+	return addresses
+}
+
+// MakeMultiSigPolicy returns the multi-signature policy of the given multi-signature address.
+func MakeMultiSigPolicy(addr address.Address) *MultiSigPolicy {
+	keys := addr.GetSigningPubKeys()
+	signingPubKeys := make([]PaymentPubKey, len(keys))
+	for i, k := range keys {
+		signingPubKeys[i] = MakePaymentPubKey(address.MakeAddressFromPubKeyByteArray(k))
 	}
+	return &MultiSigPolicy{
+		Threshold:      addr.GetSignatureThreshold(),
+		SigningPubKeys: signingPubKeys,
+	}
+}
 
+// Decode returns a multi-signature address.Address for this policy. The payment credential is not set.
+func (p MultiSigPolicy) Decode() (address.Address, error) {
+	keys := make([][address.PubKeyLength]byte, len(p.SigningPubKeys))
+	for i, k := range p.SigningPubKeys {
+		addr, err := k.PubKey.Decode()
+		if err != nil {
+			return address.Address{}, err
+		}
+		keys[i] = addr.GetPubKey()
+	}
+	return address.MakeMultiSigAddress(p.Threshold, keys)
 }
 
 func MakePaymentPubKeyHash(address address.Address) PaymentPubKeyHash {
@@ -91,19 +176,63 @@ func (cp ChannelParameters) Decode() (types.ChannelParameters, error) {
 	if !ok {
 		return types.ChannelParameters{}, fmt.Errorf("unable to decode nonce: %s", cp.Nonce)
 	}
+	if len(cp.PaymentPubKeyHashes) != len(cp.SigningPubKeys) {
+		return types.ChannelParameters{}, fmt.Errorf(
+			"mismatching number of payment public key hashes and signing public keys: %d, %d",
+			len(cp.PaymentPubKeyHashes),
+			len(cp.SigningPubKeys),
+		)
+	}
+	if cp.PaymentAddresses != nil && len(cp.PaymentAddresses) != len(cp.SigningPubKeys) {
+		return types.ChannelParameters{}, fmt.Errorf(
+			"mismatching number of payment addresses and signing public keys: %d, %d",
+			len(cp.PaymentAddresses),
+			len(cp.SigningPubKeys),
+		)
+	}
+	if cp.MultiSigPolicies != nil && len(cp.MultiSigPolicies) != len(cp.SigningPubKeys) {
+		return types.ChannelParameters{}, fmt.Errorf(
+			"mismatching number of multi-signature policies and signing public keys: %d, %d",
+			len(cp.MultiSigPolicies),
+			len(cp.SigningPubKeys),
+		)
+	}
 	for i, ppk := range cp.SigningPubKeys {
 		addr, err := ppk.PubKey.Decode()
 		if err != nil {
 			return types.ChannelParameters{}, err
 		}
+		if cp.MultiSigPolicies != nil && cp.MultiSigPolicies[i] != nil {
+			if addr, err = cp.MultiSigPolicies[i].Decode(); err != nil {
+				return types.ChannelParameters{}, err
+			}
+			if MakePaymentPubKey(addr) != ppk {
+				return types.ChannelParameters{}, fmt.Errorf(
+					"signing public key of party %d is not the first key of its multi-signature policy",
+					i,
+				)
+			}
+		}
 		pkh, err := cp.PaymentPubKeyHashes[i].PubKeyHash.Decode()
 		if err != nil {
 			return types.ChannelParameters{}, err
 		}
-		err = addr.SetPaymentPubKeyHashFromSlice(pkh)
-		if err != nil {
+		if cp.PaymentAddresses == nil {
+			if err = addr.SetPaymentPubKeyHashFromSlice(pkh); err != nil {
+				return types.ChannelParameters{}, err
+			}
+			parties[i] = addr
+			continue
+		}
+		if err = cp.PaymentAddresses[i].DecodeInto(&addr); err != nil {
 			return types.ChannelParameters{}, err
 		}
+		if hash := addr.GetPubKeyHash(); !bytes.Equal(hash[:], pkh) {
+			return types.ChannelParameters{}, fmt.Errorf(
+				"payment address of party %d does not match its payment public key hash",
+				i,
+			)
+		}
 		parties[i] = addr
 	}
 	return types.ChannelParameters{
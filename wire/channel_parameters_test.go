@@ -0,0 +1,85 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	pkgtest "polycry.pt/poly-go/test"
+	"testing"
+	"time"
+)
+
+func TestChannelParameters(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 100; i++ {
+		expected := types.ChannelParameters{
+			Parties: []address.Address{
+				test.MakeRandomAddress(rng),
+				test.MakeRandomAddressWithStakeCredential(rng),
+				test.MakeRandomMultiSigAddress(rng, 5),
+			},
+			Nonce:   new(big.Int).SetUint64(rng.Uint64()),
+			Timeout: time.Duration(rng.Int63n(1<<40)) * time.Millisecond,
+		}
+		data, err := json.Marshal(wire.MakeChannelParameters(expected))
+		require.NoError(t, err, "unable to marshal channel parameters")
+		var decoded wire.ChannelParameters
+		require.NoError(t, json.Unmarshal(data, &decoded), "unable to unmarshal channel parameters")
+		actual, err := decoded.Decode()
+		require.NoError(t, err, "unable to decode channel parameters")
+		require.True(t, expected.Equal(actual), "decoded channel parameters are not as expected")
+	}
+}
+
+func TestChannelParameters_DeployedContractSchema(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	params := types.ChannelParameters{
+		Parties: []address.Address{test.MakeRandomAddress(rng), test.MakeRandomAddress(rng)},
+		Nonce:   new(big.Int).SetUint64(rng.Uint64()),
+	}
+	data, err := json.Marshal(wire.MakeChannelParameters(params))
+	require.NoError(t, err, "unable to marshal channel parameters")
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fields))
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	require.ElementsMatch(t, []string{"pNonce", "pPaymentPKs", "pSigningPKs", "pTimeLock"}, keys,
+		"parameters of single key enterprise parties must match the deployed contract")
+}
+
+func TestChannelParameters_PaymentAddresses(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	params := types.ChannelParameters{
+		Parties: []address.Address{test.MakeRandomAddress(rng), test.MakeRandomAddressWithStakeCredential(rng)},
+		Nonce:   new(big.Int).SetUint64(rng.Uint64()),
+	}
+	wp := wire.MakeChannelParameters(params)
+	require.Len(t, wp.PaymentAddresses, 2, "payment addresses of stake credential parties must be set")
+	for i, party := range params.Parties {
+		require.Equal(t, wire.MakePaymentPubKeyHash(party), wp.PaymentPubKeyHashes[i])
+	}
+
+	wp.PaymentPubKeyHashes[1] = wp.PaymentPubKeyHashes[0]
+	_, err := wp.Decode()
+	require.Error(t, err, "failed to reject payment address not matching its payment public key hash")
+}
@@ -20,16 +20,22 @@ import (
 	"perun.network/perun-cardano-backend/wallet/address"
 )
 
-// PubKey is a json serializable public key to communicate with cardano apis (see: Ledger.Crypto.PubKey).
+// PubKey is a json serializable public key to communicate with cardano apis (see: Ledger.Crypto.PubKey). Scheme is
+// the name of the signature scheme of the key (see address.SignatureScheme) and omitted for Ed25519 keys.
 type PubKey struct {
-	Hex string `json:"getPubKey"`
+	Hex    string `json:"getPubKey"`
+	Scheme string `json:"pubKeyScheme,omitempty"`
 }
 
 // MakePubKey returns a PubKey
-func MakePubKey(address address.Address) PubKey {
-	return PubKey{
-		Hex: hex.EncodeToString(address.GetPubKeySlice()),
+func MakePubKey(addr address.Address) PubKey {
+	key := PubKey{
+		Hex: hex.EncodeToString(addr.GetSchemePubKey()),
 	}
+	if scheme := addr.GetSignatureScheme(); scheme != address.Ed25519 {
+		key.Scheme = scheme.String()
+	}
+	return key
 }
 
 func (key PubKey) Decode() (address.Address, error) {
@@ -37,5 +43,12 @@ func (key PubKey) Decode() (address.Address, error) {
 	if err != nil {
 		return address.Address{}, fmt.Errorf("unable to decode PubKey hex string: %w", err)
 	}
-	return address.MakeAddressFromPubKeyByteSlice(pubKey)
+	if key.Scheme == "" {
+		return address.MakeAddressFromPubKeyByteSlice(pubKey)
+	}
+	scheme, err := address.ParseSignatureScheme(key.Scheme)
+	if err != nil {
+		return address.Address{}, err
+	}
+	return address.MakeAddressFromSchemePubKey(scheme, pubKey)
 }
@@ -16,6 +16,7 @@ package wire_test
 
 import (
 	"github.com/stretchr/testify/require"
+	"perun.network/perun-cardano-backend/wallet/address"
 	"perun.network/perun-cardano-backend/wallet/test"
 	"perun.network/perun-cardano-backend/wire"
 	pkgtest "polycry.pt/poly-go/test"
@@ -29,3 +30,20 @@ func TestPubKey(t *testing.T) {
 	require.NoError(t, err, "unexpected error when decoding public key")
 	require.Equal(t, expected.GetPubKey(), actual.GetPubKey(), "PubKey.Decode returned a wrong address")
 }
+
+func TestPubKey_SignatureScheme(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 10; i++ {
+		expected := test.MakeRandomSchemeAddress(rng)
+		key := wire.MakePubKey(expected)
+		if expected.GetSignatureScheme() == address.Ed25519 {
+			require.Empty(t, key.Scheme, "signature scheme of Ed25519 keys should be omitted")
+		}
+		actual, err := key.Decode()
+		require.NoError(t, err, "unexpected error when decoding public key")
+		require.Equal(t, expected.GetSignatureScheme(), actual.GetSignatureScheme(), "PubKey.Decode returned a wrong scheme")
+		require.Equal(t, expected.GetSchemePubKey(), actual.GetSchemePubKey(), "PubKey.Decode returned a wrong address")
+	}
+	_, err := wire.PubKey{Hex: "00", Scheme: "RSA"}.Decode()
+	require.Error(t, err, "failed to error on unknown signature scheme")
+}
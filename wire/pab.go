@@ -64,6 +64,8 @@ func MakeAdjudicatorSubscriptionActivationBody(channelId ChannelID, walletId str
 	}
 }
 
+// OpenParams are the parameters of the start endpoint. Like in ChannelParameters, PaymentAddresses and
+// MultiSigPolicies are only set for parties the deployed contract does not support.
 type OpenParams struct {
 	Balances            []uint64            `json:"spBalances"`
 	ChannelID           ChannelID           `json:"spChannelId"`
@@ -71,6 +73,8 @@ type OpenParams struct {
 	PaymentPubKeyHashes []PaymentPubKeyHash `json:"spPaymentPKs"`
 	SigningPubKeys      []PaymentPubKey     `json:"spSigningPKs"`
 	TimeLock            int64               `json:"spTimeLock"`
+	PaymentAddresses    []Address           `json:"spPaymentAddresses,omitempty"`
+	MultiSigPolicies    []*MultiSigPolicy   `json:"spMultiSigPolicies,omitempty"`
 }
 
 func MakeOpenParams(id ChannelID, p types.ChannelParameters, s types.ChannelState) OpenParams {
@@ -82,6 +86,20 @@ func MakeOpenParams(id ChannelID, p types.ChannelParameters, s types.ChannelStat
 		PaymentPubKeyHashes: wp.PaymentPubKeyHashes,
 		SigningPubKeys:      wp.SigningPubKeys,
 		TimeLock:            wp.TimeLock,
+		PaymentAddresses:    wp.PaymentAddresses,
+		MultiSigPolicies:    wp.MultiSigPolicies,
+	}
+}
+
+// ChannelParameters returns the channel parameters of these OpenParams.
+func (op OpenParams) ChannelParameters() ChannelParameters {
+	return ChannelParameters{
+		Nonce:               op.Nonce,
+		PaymentPubKeyHashes: op.PaymentPubKeyHashes,
+		SigningPubKeys:      op.SigningPubKeys,
+		TimeLock:            op.TimeLock,
+		PaymentAddresses:    op.PaymentAddresses,
+		MultiSigPolicies:    op.MultiSigPolicies,
 	}
 }
 
@@ -140,7 +158,39 @@ func MakeCloseParams(id ChannelID, token types.ChannelToken, params types.Channe
 	}
 }
 
+func MakeDisputeParams(id ChannelID, token types.ChannelToken, params types.ChannelParameters, state types.ChannelState, sigs []wallet.Sig) DisputeParams {
+	wp := MakeChannelParameters(params)
+
+	return DisputeParams{
+		ChannelID:      id,
+		ChannelToken:   MakeAssetClass(token),
+		SignedState:    MakeStateSignatures(state, sigs),
+		SigningPubKeys: wp.SigningPubKeys,
+	}
+}
+
 type ForceCloseParams struct {
 	ChannelToken AssetClass `json:"fcpChannelToken"`
 	ChannelID    ChannelID  `json:"fcpChannelId"`
 }
+
+func MakeForceCloseParams(id ChannelID, token types.ChannelToken) ForceCloseParams {
+	return ForceCloseParams{
+		ChannelToken: MakeAssetClass(token),
+		ChannelID:    id,
+	}
+}
+
+// AbortParams are the parameters of the abort endpoint, which reclaims the funding of a channel that is not fully
+// funded.
+type AbortParams struct {
+	ChannelToken AssetClass `json:"apChannelToken"`
+	ChannelID    ChannelID  `json:"apChannelId"`
+}
+
+func MakeAbortParams(id ChannelID, token types.ChannelToken) AbortParams {
+	return AbortParams{
+		ChannelToken: MakeAssetClass(token),
+		ChannelID:    id,
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+const (
+	// ContractInstanceStatusActive is the status of a contract instance that is still running.
+	ContractInstanceStatusActive = "Active"
+	// LogLevelError is the level of log messages with which a contract reports errors.
+	LogLevelError = "Error"
+)
+
+// txIDPattern matches hex encoded transaction ids in contract log messages.
+var txIDPattern = regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`)
+
+// ContractInstanceStatus is the state of a contract instance as reported by the status endpoint of the PAB
+// (ContractInstanceClientState).
+type ContractInstanceStatus struct {
+	ContractID   ContractInstanceID `json:"cicContract"`
+	CurrentState ContractState      `json:"cicCurrentState"`
+	Status       string             `json:"cicStatus"`
+}
+
+// ContractState is the current state of a contract instance (PartiallyDecodedResponse).
+type ContractState struct {
+	Hooks           []ContractHook  `json:"hooks"`
+	Logs            []LogMessage    `json:"logs"`
+	Err             json.RawMessage `json:"err"`
+	ObservableState json.RawMessage `json:"observableState"`
+}
+
+// ContractHook is an endpoint currently exposed by a contract instance. The request id is increased for every new
+// request of the contract instance.
+type ContractHook struct {
+	RequestID int `json:"rqID"`
+	Request   struct {
+		Description struct {
+			Endpoint string `json:"getEndpointDescription"`
+		} `json:"aeDescription"`
+	} `json:"rqRequest"`
+}
+
+// LogMessage is a log message emitted by a contract instance.
+type LogMessage struct {
+	Level   string          `json:"_logLevel"`
+	Content json.RawMessage `json:"_logMessageContent"`
+}
+
+// Message returns the content of the log message. String contents are returned as is, all other contents as json.
+func (m LogMessage) Message() string {
+	return decodeMessage(m.Content)
+}
+
+// TxIDs returns all transaction ids contained in the log message.
+func (m LogMessage) TxIDs() []string {
+	return txIDPattern.FindAllString(m.Message(), -1)
+}
+
+// IsError returns true, iff the log message reports an error.
+func (m LogMessage) IsError() bool {
+	return m.Level == LogLevelError
+}
+
+// Error returns the error the contract instance failed with and true, iff the contract instance failed or stopped.
+func (s ContractInstanceStatus) Error() (string, bool) {
+	if len(s.CurrentState.Err) != 0 && string(s.CurrentState.Err) != "null" {
+		return decodeMessage(s.CurrentState.Err), true
+	}
+	if s.Status != "" && s.Status != ContractInstanceStatusActive {
+		return "contract instance status is " + s.Status, true
+	}
+	return "", false
+}
+
+// ErrorSince returns the error the contract instance failed with and true, iff it failed and did not fail with the same
+// error in the given earlier status. An error that was already reported before is not attributed to later calls.
+func (s ContractInstanceStatus) ErrorSince(before ContractInstanceStatus) (string, bool) {
+	msg, failed := s.Error()
+	if !failed {
+		return "", false
+	}
+	if beforeMsg, beforeFailed := before.Error(); beforeFailed && beforeMsg == msg {
+		return "", false
+	}
+	return msg, true
+}
+
+// LatestRequestID returns the highest request id of all hooks of the contract instance or -1, if there are none.
+func (s ContractInstanceStatus) LatestRequestID() int {
+	latest := -1
+	for _, hook := range s.CurrentState.Hooks {
+		if hook.RequestID > latest {
+			latest = hook.RequestID
+		}
+	}
+	return latest
+}
+
+// ExposesEndpointSince returns true, iff the contract instance exposes the given endpoint with a request id higher than
+// the given one, i.e., the contract instance handled the request issued after the given request id and waits for the
+// endpoint to be called again.
+func (s ContractInstanceStatus) ExposesEndpointSince(endpoint string, requestID int) bool {
+	for _, hook := range s.CurrentState.Hooks {
+		if hook.Request.Description.Endpoint == endpoint && hook.RequestID > requestID {
+			return true
+		}
+	}
+	return false
+}
+
+// LogsSince returns the log messages emitted after the given number of log messages.
+func (s ContractInstanceStatus) LogsSince(count int) []LogMessage {
+	if count >= len(s.CurrentState.Logs) {
+		return nil
+	}
+	return s.CurrentState.Logs[count:]
+}
+
+func decodeMessage(content json.RawMessage) string {
+	var message string
+	if err := json.Unmarshal(content, &message); err == nil {
+		return message
+	}
+	return string(content)
+}
@@ -0,0 +1,78 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"perun.network/perun-cardano-backend/wire"
+	"testing"
+)
+
+const jsonContractInstanceStatus = `{
+  "cicContract": {"unContractInstanceId": "4f3c6a8b-perun"},
+  "cicStatus": "Active",
+  "cicCurrentState": {
+    "observableState": [],
+    "err": null,
+    "hooks": [
+      {"rqID": 4, "itID": 2, "rqRequest": {"aeDescription": {"getEndpointDescription": "fund"}, "aeMetadata": null}},
+      {"rqID": 5, "itID": 2, "rqRequest": {"aeDescription": {"getEndpointDescription": "close"}, "aeMetadata": null}}
+    ],
+    "logs": [
+      {"_logLevel": "Info", "_logMessageContent": "Started channel"},
+      {"_logLevel": "Info", "_logMessageContent": "Submitted tx a707536d289e9eb51f251ffb193704f8c1c99692148bcc992616b1b11d364c41"},
+      {"_logLevel": "Error", "_logMessageContent": {"tag": "OtherContractError", "contents": "validation failed"}}
+    ],
+    "lastLogs": []
+  }
+}`
+
+func TestContractInstanceStatus(t *testing.T) {
+	var status wire.ContractInstanceStatus
+	require.NoError(t, json.Unmarshal([]byte(jsonContractInstanceStatus), &status))
+	require.Equal(t, "4f3c6a8b-perun", status.ContractID.Decode())
+	_, failed := status.Error()
+	require.False(t, failed)
+
+	require.Equal(t, 5, status.LatestRequestID())
+	require.True(t, status.ExposesEndpointSince("fund", 3))
+	require.False(t, status.ExposesEndpointSince("fund", 4))
+	require.False(t, status.ExposesEndpointSince("start", -1))
+
+	logs := status.LogsSince(1)
+	require.Len(t, logs, 2)
+	require.Equal(t, []string{"a707536d289e9eb51f251ffb193704f8c1c99692148bcc992616b1b11d364c41"}, logs[0].TxIDs())
+	require.False(t, logs[0].IsError())
+	require.True(t, logs[1].IsError())
+	require.JSONEq(t, `{"tag": "OtherContractError", "contents": "validation failed"}`, logs[1].Message())
+	require.Empty(t, status.LogsSince(3))
+
+	before := status
+	status.CurrentState.Err = json.RawMessage(`"insufficient funds"`)
+	msg, failed := status.Error()
+	require.True(t, failed)
+	require.Equal(t, "insufficient funds", msg)
+	msg, failed = status.ErrorSince(before)
+	require.True(t, failed)
+	require.Equal(t, "insufficient funds", msg)
+	_, failed = status.ErrorSince(status)
+	require.False(t, failed, "error was already reported before")
+
+	status.CurrentState.Err = nil
+	status.Status = "Stopped"
+	_, failed = status.Error()
+	require.True(t, failed)
+}
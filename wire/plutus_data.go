@@ -0,0 +1,208 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet/address"
+)
+
+// CBOR major types and special values used in the serialization of Plutus Data.
+const (
+	cborUnsignedInt     byte = 0 << 5
+	cborByteString      byte = 2 << 5
+	cborArray           byte = 4 << 5
+	cborTag             byte = 6 << 5
+	cborIndefiniteBytes byte = cborByteString | 31
+	cborIndefiniteList  byte = cborArray | 31
+	cborBreak           byte = 0xff
+)
+
+// plutusBytesChunkSize is the maximal length of a byte string chunk in the serialization of Plutus Data.
+const plutusBytesChunkSize = 64
+
+// plutusConstrTag is the CBOR tag of the Plutus Data constructor with index 0. Constructors with index i < 7 are
+// tagged with plutusConstrTag + i.
+const plutusConstrTag = 121
+
+// MakeChannelStateMessage returns the message that is signed for the given ChannelState: the serialization of the
+// Plutus Data representation of the Haskell type `ChannelState` (i.e. `serialiseData . toBuiltinData`). This allows
+// signing channel states without a wallet server.
+func MakeChannelStateMessage(state types.ChannelState) []byte {
+	balances := make([][]byte, len(state.Balances))
+	for i, b := range state.Balances {
+		balances[i] = appendCborHead(nil, cborUnsignedInt, b)
+	}
+	return encodePlutusConstr(0,
+		append(appendCborHead(nil, cborByteString, uint64(len(state.ID))), state.ID[:]...),
+		encodePlutusList(balances...),
+		appendCborHead(nil, cborUnsignedInt, state.Version),
+		encodePlutusBool(state.Final),
+	)
+}
+
+// MakeChannelParametersMessage returns the serialization of the Plutus Data representation of the Haskell type
+// `Channel` of the Channel Smart Contract for the given ChannelParameters (i.e. `serialiseData . toBuiltinData`). The
+// fields are serialized in the order of their declaration: pTimeLock (in milliseconds), pSigningPKs, pPaymentPKs and
+// pNonce (as byte string, see ChannelParameters). If a party has a stake credential or is a multi-signature party, the
+// payout addresses and, for multi-signature parties, the multi-signature policies of all parties are appended
+// (see ChannelParameters.SetPaymentAddresses).
+func MakeChannelParametersMessage(params types.ChannelParameters) []byte {
+	signingPubKeys := make([][]byte, len(params.Parties))
+	paymentPubKeyHashes := make([][]byte, len(params.Parties))
+	paymentAddresses := make([][]byte, len(params.Parties))
+	policies := make([][]byte, len(params.Parties))
+	hasPaymentAddresses, hasMultiSig := false, false
+	for i, party := range params.Parties {
+		signingPubKeys[i] = encodePlutusBytes(party.GetSchemePubKey())
+		credential := party.GetPaymentCredential()
+		paymentPubKeyHashes[i] = encodePlutusBytes(credential.Hash[:])
+		paymentAddresses[i] = encodePlutusAddress(party)
+		if _, ok := party.GetStakeCredential(); ok || party.GetPaymentCredential().Type == address.ScriptHashCredential {
+			hasPaymentAddresses = true
+		}
+		policies[i] = encodePlutusMaybe(nil)
+		if party.IsMultiSig() {
+			hasPaymentAddresses, hasMultiSig = true, true
+			keys := party.GetSigningPubKeys()
+			encodedKeys := make([][]byte, len(keys))
+			for j := range keys {
+				encodedKeys[j] = encodePlutusBytes(keys[j][:])
+			}
+			policies[i] = encodePlutusMaybe(encodePlutusConstr(0,
+				appendCborHead(nil, cborUnsignedInt, uint64(party.GetSignatureThreshold())),
+				encodePlutusList(encodedKeys...),
+			))
+		}
+	}
+	var nonce []byte
+	if params.Nonce != nil {
+		nonce = params.Nonce.Bytes()
+	}
+	fields := [][]byte{
+		appendCborHead(nil, cborUnsignedInt, uint64(params.Timeout.Milliseconds())),
+		encodePlutusList(signingPubKeys...),
+		encodePlutusList(paymentPubKeyHashes...),
+		encodePlutusBytes(nonce),
+	}
+	if hasPaymentAddresses {
+		fields = append(fields, encodePlutusList(paymentAddresses...))
+	}
+	if hasMultiSig {
+		fields = append(fields, encodePlutusList(policies...))
+	}
+	return encodePlutusConstr(0, fields...)
+}
+
+// CalculateChannelID derives the channel id of the given ChannelParameters locally: the sha2_256 hash of
+// MakeChannelParametersMessage. This allows calculating channel ids without a wallet server.
+func CalculateChannelID(params types.ChannelParameters) types.ID {
+	return sha256.Sum256(MakeChannelParametersMessage(params))
+}
+
+// encodePlutusAddress returns the serialization of the Plutus Data representation of the Haskell type
+// `Plutus.V2.Ledger.Api.Address` of the payout address of the given address.Address (see MakeAddress).
+func encodePlutusAddress(addr address.Address) []byte {
+	stake := encodePlutusMaybe(nil)
+	if c, ok := addr.GetStakeCredential(); ok {
+		stake = encodePlutusMaybe(encodePlutusConstr(0, encodePlutusCredential(c)))
+	}
+	return encodePlutusConstr(0, encodePlutusCredential(addr.GetPaymentCredential()), stake)
+}
+
+// encodePlutusCredential returns the serialization of the Plutus Data representation of the Haskell type
+// `Plutus.V2.Ledger.Api.Credential`.
+func encodePlutusCredential(c address.Credential) []byte {
+	if c.Type == address.ScriptHashCredential {
+		return encodePlutusConstr(1, encodePlutusBytes(c.Hash[:]))
+	}
+	return encodePlutusConstr(0, encodePlutusBytes(c.Hash[:]))
+}
+
+// encodePlutusMaybe returns the serialization of the Plutus Data representation of a Haskell `Maybe`. The given
+// serialized value is nil for `Nothing`.
+func encodePlutusMaybe(value []byte) []byte {
+	if value == nil {
+		return encodePlutusConstr(1)
+	}
+	return encodePlutusConstr(0, value)
+}
+
+// encodePlutusBytes returns the serialization of the Plutus Data representation of a byte string. Byte strings of more
+// than 64 bytes are encoded with indefinite length in chunks of 64 bytes.
+func encodePlutusBytes(b []byte) []byte {
+	if len(b) <= plutusBytesChunkSize {
+		return append(appendCborHead(nil, cborByteString, uint64(len(b))), b...)
+	}
+	data := []byte{cborIndefiniteBytes}
+	for len(b) > 0 {
+		n := len(b)
+		if n > plutusBytesChunkSize {
+			n = plutusBytesChunkSize
+		}
+		data = append(appendCborHead(data, cborByteString, uint64(n)), b[:n]...)
+		b = b[n:]
+	}
+	return append(data, cborBreak)
+}
+
+// encodePlutusConstr returns the serialization of the Plutus Data constructor with the given index (< 7) and the given
+// serialized fields.
+func encodePlutusConstr(index uint64, fields ...[]byte) []byte {
+	return append(appendCborHead(nil, cborTag, plutusConstrTag+index), encodePlutusList(fields...)...)
+}
+
+// encodePlutusList returns the serialization of a Plutus Data list of the given serialized items. Non-empty lists are
+// encoded with indefinite length.
+func encodePlutusList(items ...[]byte) []byte {
+	if len(items) == 0 {
+		return appendCborHead(nil, cborArray, 0)
+	}
+	data := []byte{cborIndefiniteList}
+	for _, item := range items {
+		data = append(data, item...)
+	}
+	return append(data, cborBreak)
+}
+
+// encodePlutusBool returns the serialization of the Plutus Data representation of a Haskell `Bool`.
+func encodePlutusBool(b bool) []byte {
+	if b {
+		return encodePlutusConstr(1)
+	}
+	return encodePlutusConstr(0)
+}
+
+// appendCborHead appends the CBOR head of the given major type with the given argument to data.
+func appendCborHead(data []byte, majorType byte, argument uint64) []byte {
+	var n int
+	switch {
+	case argument < 24:
+		return append(data, majorType|byte(argument))
+	case argument <= 0xff:
+		data, n = append(data, majorType|24), 1
+	case argument <= 0xffff:
+		data, n = append(data, majorType|25), 2
+	case argument <= 0xffffffff:
+		data, n = append(data, majorType|26), 4
+	default:
+		data, n = append(data, majorType|27), 8
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], argument)
+	return append(data, b[8-n:]...)
+}
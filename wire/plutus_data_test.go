@@ -0,0 +1,150 @@
+// Copyright 2022, 2023 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"perun.network/perun-cardano-backend/channel/types"
+	"perun.network/perun-cardano-backend/wallet"
+	"perun.network/perun-cardano-backend/wallet/address"
+	"perun.network/perun-cardano-backend/wallet/test"
+	"perun.network/perun-cardano-backend/wire"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMakeChannelStateMessage(t *testing.T) {
+	var id types.ID
+	for i := range id {
+		id[i] = byte(i)
+	}
+	idHex := hex.EncodeToString(id[:])
+	vectors := []struct {
+		name     string
+		state    types.ChannelState
+		expected string
+	}{
+		{
+			"final",
+			types.MakeChannelState(id, []uint64{1, 1000000}, 300, true),
+			"d8799f5820" + idHex + "9f011a000f4240ff19012cd87a80ff",
+		},
+		{
+			"not final",
+			types.MakeChannelState(id, []uint64{23, 24, 1 << 32}, 0, false),
+			"d8799f5820" + idHex + "9f1718181b0000000100000000ff00d87980ff",
+		},
+		{
+			"no balances",
+			types.MakeChannelState(id, nil, 1<<16-1, false),
+			"d8799f5820" + idHex + "8019ffffd87980ff",
+		},
+	}
+	for _, v := range vectors {
+		actual := wire.MakeChannelStateMessage(v.state)
+		require.Equalf(t, v.expected, hex.EncodeToString(actual), "message of %s channel state is not as expected", v.name)
+	}
+}
+
+func TestMakeChannelStateMessage_Golden(t *testing.T) {
+	// ChannelState {channelId = goldenChannelID, balances = [5000000, 2500000], version = 7, final = False}
+	// serialized as Plutus Data.
+	expected := "d8799f" +
+		"5820d2a9f34ee22a0863aeb7bf53c5f5aff3a3447d8f50958b4259f080170225f653" +
+		"9f1a004c4b401a002625a0ff" +
+		"07" +
+		"d87980" +
+		"ff"
+	id := wire.CalculateChannelID(goldenParams(t))
+	state := types.MakeChannelState(id, []uint64{5000000, 2500000}, 7, false)
+	require.Equal(t, expected, hex.EncodeToString(wire.MakeChannelStateMessage(state)))
+}
+
+// goldenParties returns the parties of the golden vectors: the parties with the public keys 0x00..1f and 0x20..3f,
+// which are paid out to the hashes of their public keys.
+func goldenParties(t *testing.T) []address.Address {
+	parties := make([]address.Address, 2)
+	for i := range parties {
+		var pubKey [address.PubKeyLength]byte
+		for j := range pubKey {
+			pubKey[j] = byte(i*address.PubKeyLength + j)
+		}
+		pubKeyHash, err := address.CalculatePubKeyHash(pubKey)
+		require.NoError(t, err)
+		parties[i] = address.MakeAddressFromPubKeyByteArray(pubKey)
+		parties[i].SetPaymentPubKeyHash(pubKeyHash)
+	}
+	return parties
+}
+
+// goldenParams returns the channel parameters of the golden vectors.
+func goldenParams(t *testing.T) types.ChannelParameters {
+	nonce, _ := new(big.Int).SetString("a0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebf", 16)
+	return types.ChannelParameters{
+		Parties: goldenParties(t),
+		Nonce:   nonce,
+		Timeout: 10 * time.Second,
+	}
+}
+
+// goldenChannelID is the channel id of goldenParams. The golden vectors follow the declarations of the contract's
+// types, but were not produced by the contract. TestCalculateChannelID_WalletServer checks them against the contract's
+// `serialiseData . toBuiltinData`.
+const goldenChannelID = "d2a9f34ee22a0863aeb7bf53c5f5aff3a3447d8f50958b4259f080170225f653"
+
+func TestMakeChannelParametersMessage(t *testing.T) {
+	// Channel {pTimeLock = 10000, pSigningPKs = [pk 0x00..1f, pk 0x20..3f], pPaymentPKs = [blake2b_224 pk, ...],
+	// pNonce = 0xa0..bf} serialized as Plutus Data.
+	expected := "d8799f" +
+		"192710" +
+		"9f" +
+		"5820000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
+		"5820202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f" +
+		"ff" +
+		"9f" +
+		"581c491112dd01155c07dab485f71b572e0cae759e2cd38b1c0e97554297" +
+		"581c3d56a149cbd95dffa093ebbf864ceb86113dfe87146812148e0524e7" +
+		"ff" +
+		"5820a0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebf" +
+		"ff"
+	params := goldenParams(t)
+	require.Equal(t, expected, hex.EncodeToString(wire.MakeChannelParametersMessage(params)))
+	id := wire.CalculateChannelID(params)
+	require.Equal(t, goldenChannelID, hex.EncodeToString(id[:]))
+	require.Equal(t, types.ID(sha256.Sum256(wire.MakeChannelParametersMessage(params))), id)
+
+	params.Nonce = new(big.Int).Lsh(big.NewInt(1), 64*8)
+	require.Contains(t, hex.EncodeToString(wire.MakeChannelParametersMessage(params)),
+		"5f584001"+strings.Repeat("00", 63)+"4100ff", "nonce of more than 64 bytes is not encoded in chunks")
+
+	params = goldenParams(t)
+	multiSig, err := address.MakeMultiSigAddress(1, [][address.PubKeyLength]byte{params.Parties[0].GetPubKey()})
+	require.NoError(t, err)
+	params.Parties[1] = multiSig
+	require.True(t, strings.HasSuffix(hex.EncodeToString(wire.MakeChannelParametersMessage(params)),
+		"9fd87a80d8799fd8799f019f5820"+hex.EncodeToString(params.Parties[0].GetSchemePubKey())+"ffffffffff"),
+		"multi-signature policies are not appended")
+}
+
+func TestCalculateChannelID_WalletServer(t *testing.T) {
+	backend := wallet.MakeRemoteBackend(wallet.NewPerunCardanoWallet(test.WalletServerURL(t)))
+	id, err := backend.CalculateChannelID(goldenParams(t))
+	require.NoError(t, err)
+	require.Equal(t, goldenChannelID, hex.EncodeToString(id[:]), "wallet server calculates another channel id")
+}
@@ -22,7 +22,7 @@ import (
 	"perun.network/perun-cardano-backend/wallet/address"
 )
 
-// SignatureLength is the length of valid Cardano signatures in bytes.
+// SignatureLength is the length of valid Cardano (Ed25519) signatures in bytes.
 const SignatureLength = 64
 
 // SigningRequest is the json serializable request for signing via the perun-cardano-wallet api.
@@ -104,19 +104,33 @@ func MakeKeyAvailabilityRequest(address address.Address) KeyAvailabilityRequest
 	return MakePubKey(address)
 }
 
+// KeyLockRequest is the json serializable request for locking or unlocking a key via the perun-cardano-wallet api.
+type KeyLockRequest = PubKey
+
+// MakeKeyLockRequest returns a new KeyLockRequest for the given address.
+func MakeKeyLockRequest(address address.Address) KeyLockRequest {
+	return MakePubKey(address)
+}
+
 // SigningResponse is the json serializable response when signing via the perun-cardano-wallet api.
 type SigningResponse = Signature
 
 // Decode decodes the siganture from a SigningResponse.
 func (sr SigningResponse) Decode() (wallet.Sig, error) {
+	return sr.DecodeWithLength(SignatureLength)
+}
+
+// DecodeWithLength decodes the signature from a SigningResponse and checks that it is of the given length. This is
+// used for signature schemes with signatures that are not SignatureLength bytes long.
+func (sr SigningResponse) DecodeWithLength(length int) (wallet.Sig, error) {
 	sig, err := hex.DecodeString(sr.Hex)
 	if err != nil {
 		return nil, fmt.Errorf("unable to decode Signature from hex string: %w", err)
 	}
-	if len(sig) != SignatureLength {
+	if len(sig) != length {
 		return nil, fmt.Errorf(
 			"signature has incorrect length. expected: %d bytes, actual: %d bytes",
-			SignatureLength,
+			length,
 			len(sig),
 		)
 	}
@@ -129,3 +143,57 @@ type VerificationResponse = bool
 // KeyAvailabilityResponse is json serializable response when requesting key-availability via the
 // perun-cardano-wallet api.
 type KeyAvailabilityResponse = bool
+
+// KeyLockResponse is the json serializable response when locking or unlocking a key via the perun-cardano-wallet api.
+// It is true, iff the wallet server holds the key.
+type KeyLockResponse = bool
+
+// BatchVerificationRequest is the json serializable request for verifying multiple signatures on ChannelStates in a
+// single call via the perun-cardano-wallet api.
+type BatchVerificationRequest struct {
+	Requests []ChannelStateVerificationRequest `json:"bvRequests"`
+}
+
+// BatchVerificationResponse is the json serializable response to a BatchVerificationRequest. It holds the result of
+// every verification in the order of the requests.
+type BatchVerificationResponse = []bool
+
+// AccountInfo is the json serializable description of an account the perun-cardano-wallet server holds the key of.
+type AccountInfo struct {
+	PubKey   PubKey `json:"aiPubKey"`
+	WalletID string `json:"aiWalletId"`
+}
+
+// MakeAccountInfo returns a new AccountInfo for the given address and cardano wallet id.
+func MakeAccountInfo(address address.Address, walletID string) AccountInfo {
+	return AccountInfo{
+		PubKey:   MakePubKey(address),
+		WalletID: walletID,
+	}
+}
+
+// ListAccountsRequest is the json serializable request for listing the accounts of the perun-cardano-wallet server.
+type ListAccountsRequest struct{}
+
+// ListAccountsResponse is the json serializable response to a ListAccountsRequest.
+type ListAccountsResponse = []AccountInfo
+
+// CreateAccountRequest is the json serializable request for generating a new key pair in the perun-cardano-wallet
+// server. The scheme is empty for Ed25519 keys. The new account belongs to the given cardano wallet, if the wallet id is
+// not empty.
+type CreateAccountRequest struct {
+	Scheme   string `json:"caScheme,omitempty"`
+	WalletID string `json:"caWalletId,omitempty"`
+}
+
+// MakeCreateAccountRequest returns a new CreateAccountRequest for a key of the given signature scheme.
+func MakeCreateAccountRequest(scheme address.SignatureScheme, walletID string) CreateAccountRequest {
+	request := CreateAccountRequest{WalletID: walletID}
+	if scheme != address.Ed25519 {
+		request.Scheme = scheme.String()
+	}
+	return request
+}
+
+// CreateAccountResponse is the json serializable response to a CreateAccountRequest.
+type CreateAccountResponse = AccountInfo